@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/igoramorim/gopengl/internal/app"
+	"github.com/igoramorim/gopengl/internal/golden"
+)
+
+// checkGolden renders each scene headless and compares the result against the golden
+// image with the same name in dir. When update is set the golden images are replaced
+// by the new renders instead.
+func checkGolden(names []string, cfg app.Config, dir string, opts golden.Options, update bool) error {
+	cfg.Headless = true
+
+	var failed []string
+	for _, name := range names {
+		scene := allScenes[name]
+		if err := app.Run(scene, cfg); err != nil {
+			fmt.Printf("golden: %s: %v\n", name, err)
+			failed = append(failed, name)
+			continue
+		}
+
+		gotPath := filepath.Join(cfg.OutDir, name+".png")
+		wantPath := filepath.Join(dir, name+".png")
+
+		if update {
+			img, err := golden.Load(gotPath)
+			if err == nil {
+				err = golden.Save(wantPath, img)
+			}
+			if err != nil {
+				fmt.Printf("golden: %s: %v\n", name, err)
+				failed = append(failed, name)
+				continue
+			}
+			fmt.Printf("golden: %s: updated %s\n", name, wantPath)
+			continue
+		}
+
+		result, err := golden.CompareFiles(gotPath, wantPath, opts)
+		if err != nil {
+			fmt.Printf("golden: %s: %v\n", name, err)
+			failed = append(failed, name)
+			continue
+		}
+
+		if !result.Passed(opts) {
+			fmt.Printf("golden: %s: FAIL %d pixels (%.4f%%) differ, see %s\n",
+				name, result.DiffPixels, result.Ratio()*100, golden.DiffPath(gotPath))
+			failed = append(failed, name)
+			continue
+		}
+
+		fmt.Printf("golden: %s: ok %d pixels (%.4f%%) differ\n", name, result.DiffPixels, result.Ratio()*100)
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("golden: %d of %d scenes failed: %q", len(failed), len(names), failed)
+	}
+
+	return nil
+}
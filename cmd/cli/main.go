@@ -1,12 +1,19 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
 	"runtime"
+	"sort"
 
+	"github.com/igoramorim/gopengl/internal/app"
+	"github.com/igoramorim/gopengl/internal/assets"
+	"github.com/igoramorim/gopengl/internal/golden"
 	"github.com/igoramorim/gopengl/internal/scenes"
 	"github.com/igoramorim/gopengl/internal/sshot"
+	"github.com/igoramorim/gopengl/pkg/camera"
 )
 
 func init() {
@@ -15,51 +22,145 @@ func init() {
 }
 
 func main() {
-	if len(os.Args) <= 1 {
+	var cfg app.Config
+	flag.BoolVar(&cfg.Headless, "headless", false, "render offscreen without showing a window")
+	flag.IntVar(&cfg.Frames, "frames", 1, "number of frames to render in headless mode")
+	flag.StringVar(&cfg.ContextAPI, "context", "native", "context creation api: native, egl or osmesa")
+	flag.StringVar(&cfg.OutDir, "out", filepath.Join(".", "images", "headless"), "directory where headless renders are saved")
+	flag.BoolVar(&cfg.HotReload, "hot-reload", false, "rebuild the shaders when their source files change")
+
+	var cameraPath string
+	flag.StringVar(&cameraPath, "camera-path", "", "move the camera along this json path, recorded with the K and J keys, instead of the input")
+
+	var assetsDir string
+	flag.StringVar(&assetsDir, "assets", "", "load the assets from this directory instead of the ones embedded in the binary")
+
+	var goldenDir string
+	var updateGolden bool
+	goldenOpts := golden.DefaultOptions
+	flag.StringVar(&goldenDir, "golden", "", "compare headless renders against the golden pngs in this directory")
+	flag.BoolVar(&updateGolden, "update-golden", false, "replace the golden pngs by the new renders")
+	flag.Float64Var(&goldenOpts.Threshold, "tolerance", goldenOpts.Threshold, "perceptual distance (0-1) above which two pixels differ")
+	flag.Float64Var(&goldenOpts.MaxDiffRatio, "max-diff", goldenOpts.MaxDiffRatio, "fraction (0-1) of pixels allowed to differ from the golden png")
+
+	flag.Usage = help
+	flag.Parse()
+
+	if flag.NArg() < 1 {
 		help()
 		os.Exit(1)
 	}
 
-	arg := os.Args[1]
+	// Hot reload watches the files on disk, the embedded ones never change
+	if assetsDir == "" && cfg.HotReload {
+		assetsDir = filepath.Join("internal", "assets")
+	}
+	if assetsDir != "" {
+		assets.UseDir(assetsDir)
+	}
+
+	arg := flag.Arg(0)
+
+	if goldenDir != "" {
+		names := []string{arg}
+		if arg == allScenesArg {
+			names = possibleScenes()
+		} else if _, ok := allScenes[arg]; !ok {
+			help()
+			os.Exit(1)
+		}
+
+		if err := checkGolden(names, cfg, goldenDir, goldenOpts, updateGolden); err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		return
+	}
+
 	scene, ok := allScenes[arg]
 	if !ok {
 		help()
 		os.Exit(1)
 	}
 
-	defer func() {
-		err := sshot.MakeGIF()
-		if err != nil {
+	if cameraPath != "" {
+		if err := followPath(scene, cameraPath); err != nil {
 			fmt.Println(err.Error())
+			os.Exit(1)
 		}
-	}()
+	}
+
+	if !cfg.Headless {
+		defer func() {
+			err := sshot.MakeGIF()
+			if err != nil {
+				fmt.Println(err.Error())
+			}
+		}()
+	}
+
+	if err := app.Run(scene, cfg); err != nil {
+		fmt.Println(err.Error())
+		if cfg.Headless {
+			// CI needs to know the render failed
+			os.Exit(1)
+		}
+	}
+}
 
-	scene.Show()
+// pathFollower is a scene whose camera can play a camera.Path.
+type pathFollower interface {
+	FollowPath(path *camera.Path)
 }
 
+// followPath makes the camera of scene play the path in file.
+func followPath(scene scenes.Scene, file string) error {
+	follower, ok := scene.(pathFollower)
+	if !ok {
+		return fmt.Errorf("scene %s has no camera to move along a path", scene.Name())
+	}
+
+	path, err := camera.LoadPath(file)
+	if err != nil {
+		return err
+	}
+	follower.FollowPath(path)
+
+	return nil
+}
+
+// allScenesArg can be used instead of a scene name to check every scene against its golden png.
+const allScenesArg = "all"
+
 var allScenes = map[string]scenes.Scene{
-	scenes.Triangle{}.Name():         scenes.Triangle{},
-	scenes.Shaders{}.Name():          scenes.Shaders{},
-	scenes.Textures{}.Name():         scenes.Textures{},
-	scenes.Transformations{}.Name():  scenes.Transformations{},
-	scenes.CoordinateSystem{}.Name(): scenes.CoordinateSystem{},
-	scenes.Cube{}.Name():             scenes.Cube{},
-	scenes.Camera{}.Name():           scenes.NewCamera(),
-	scenes.LightColors{}.Name():      scenes.NewLightColors(),
-	scenes.BasicLight{}.Name():       scenes.NewBasicLight(),
-	scenes.Materials{}.Name():        scenes.NewMaterials(),
-	scenes.LightMaps{}.Name():        scenes.NewLightMaps(),
-	scenes.DirectionalLight{}.Name(): scenes.NewDirectionalLight(),
-	scenes.PointLight{}.Name():       scenes.NewPointLight(),
-	scenes.SpotLight{}.Name():        scenes.NewSpotLight(),
-	scenes.ModelLoading{}.Name():     scenes.NewModelLoading(),
-	scenes.DepthTesting{}.Name():     scenes.NewDepthTesting(),
-	scenes.StencilTesting{}.Name():   scenes.NewStencilTesting(),
+	scenes.Triangle{}.Name():          &scenes.Triangle{},
+	scenes.Shaders{}.Name():           &scenes.Shaders{},
+	scenes.Textures{}.Name():          &scenes.Textures{},
+	scenes.Transformations{}.Name():   &scenes.Transformations{},
+	scenes.CoordinateSystem{}.Name():  &scenes.CoordinateSystem{},
+	scenes.Cube{}.Name():              &scenes.Cube{},
+	scenes.Camera{}.Name():            scenes.NewCamera(),
+	scenes.LightColors{}.Name():       scenes.NewLightColors(),
+	scenes.BasicLight{}.Name():        scenes.NewBasicLight(),
+	scenes.Materials{}.Name():         scenes.NewMaterials(),
+	scenes.LightMaps{}.Name():         scenes.NewLightMaps(),
+	scenes.DirectionalLight{}.Name():  scenes.NewDirectionalLight(),
+	scenes.PointLight{}.Name():        scenes.NewPointLight(),
+	scenes.SpotLight{}.Name():         scenes.NewSpotLight(),
+	scenes.MultipleLights{}.Name():    scenes.NewMultipleLights(),
+	scenes.ModelLoading{}.Name():      scenes.NewModelLoading(),
+	scenes.SkeletalAnimation{}.Name(): scenes.NewSkeletalAnimation(),
+	scenes.DepthTesting{}.Name():      scenes.NewDepthTesting(),
+	scenes.StencilTesting{}.Name():    scenes.NewStencilTesting(),
+	scenes.Skybox{}.Name():            scenes.NewSkybox(),
+	scenes.PostProcessing{}.Name():    scenes.NewPostProcessing(),
 }
 
 func help() {
 	fmt.Printf("scene name is required\n")
 	fmt.Printf("possible values are: %q\n", possibleScenes())
+	fmt.Printf("usage: %s [flags] ${scene}\n", filepath.Base(os.Args[0]))
+	flag.PrintDefaults()
 	// TODO: Add message about possible controls (camera movement, screenshot etc)
 }
 
@@ -68,5 +169,6 @@ func possibleScenes() []string {
 	for k, _ := range allScenes {
 		names = append(names, k)
 	}
+	sort.Strings(names)
 	return names
 }
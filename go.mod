@@ -7,10 +7,10 @@ require (
 	github.com/go-gl/glfw/v3.3/glfw v0.0.0-20240506104042-037f3cc74f2a
 )
 
-require github.com/bloeys/gglm v0.3.1 // indirect
+require github.com/bloeys/gglm v0.3.1
 
 require (
 	github.com/bloeys/assimp-go v0.6.0
-	github.com/go-gl/mathgl v1.2.0 // indirect
+	github.com/go-gl/mathgl v1.2.0
 	golang.org/x/image v0.24.0
 )
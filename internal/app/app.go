@@ -0,0 +1,249 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/igoramorim/gopengl/internal/sshot"
+	"github.com/igoramorim/gopengl/pkg/bounds"
+	"github.com/igoramorim/gopengl/pkg/framebuffer"
+	"github.com/igoramorim/gopengl/pkg/shader"
+)
+
+// Scene is the lifecycle contract driven by Run. A scene only supplies its resources
+// and per frame logic, the window, the GL context and the main loop are owned by the runner.
+type Scene interface {
+	Name() string
+	Width() int
+	Height() int
+
+	// Init is called once after the GL context is current. It is where the scene creates
+	// its shaders, buffers and textures and registers any input callbacks it needs.
+	Init(w *glfw.Window) error
+
+	// Update is called once per frame before Render with the time in seconds elapsed since
+	// the last frame.
+	Update(w *glfw.Window, deltaTime float64)
+
+	// Render draws the frame. time is the number of seconds since the runner started.
+	Render(time float64)
+
+	// Resize is called whenever the framebuffer changes in size.
+	Resize(width, height int)
+
+	// Destroy releases every resource created by Init.
+	Destroy()
+}
+
+// cullStatser is a scene that culls what is out of its view. Run shows its stats in the
+// title of the window.
+type cullStatser interface {
+	// CullStats returns how many objects the last frame tested and culled.
+	CullStats() bounds.CullStats
+}
+
+// Config controls how Run creates the window and drives the scene.
+type Config struct {
+	// Headless renders the scene into an offscreen framebuffer of an invisible window,
+	// so it can run on machines without a GPU or a display such as CI.
+	Headless bool
+
+	// Frames is how many frames are rendered in headless mode before the last one is saved.
+	Frames int
+
+	// ContextAPI is the API used to create the OpenGL context: "native" (default),
+	// "egl" or "osmesa". The latter two together with Mesa llvmpipe give a software context.
+	ContextAPI string
+
+	// OutDir is the directory where the last frame is saved in headless mode.
+	OutDir string
+
+	// HotReload rebuilds the shaders of the scene whenever their source files change.
+	// It is ignored in headless mode.
+	HotReload bool
+}
+
+// hotReloadInterval is how often the shader source files are checked for changes.
+const hotReloadInterval = 500 * time.Millisecond
+
+// headlessFrameTime is the fixed time step used in headless mode, so the rendered
+// frames do not depend on how fast the machine is.
+const headlessFrameTime = 1.0 / 60.0
+
+var contextAPIs = map[string]int{
+	"":       glfw.NativeContextAPI,
+	"native": glfw.NativeContextAPI,
+	"egl":    glfw.EGLContextAPI,
+	"osmesa": glfw.OSMesaContextAPI,
+}
+
+// Run creates the window and the OpenGL context for the scene and drives it
+// until the window is closed, or until cfg.Frames are rendered in headless mode.
+func Run(scene Scene, cfg Config) error {
+	contextAPI, ok := contextAPIs[cfg.ContextAPI]
+	if !ok {
+		return fmt.Errorf("unknown context api %q", cfg.ContextAPI)
+	}
+
+	if err := glfw.Init(); err != nil {
+		return fmt.Errorf("initialize glfw: %w", err)
+	}
+	defer glfw.Terminate()
+
+	glfw.WindowHint(glfw.Resizable, glfw.True)
+	glfw.WindowHint(glfw.ContextVersionMajor, 4)
+	glfw.WindowHint(glfw.ContextVersionMinor, 1)
+	glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
+	glfw.WindowHint(glfw.OpenGLForwardCompatible, glfw.True)
+	glfw.WindowHint(glfw.ContextCreationAPI, contextAPI)
+
+	if cfg.Headless {
+		glfw.WindowHint(glfw.Visible, glfw.False)
+		glfw.WindowHint(glfw.Resizable, glfw.False)
+	}
+
+	window, err := glfw.CreateWindow(scene.Width(), scene.Height(), scene.Name(), nil, nil)
+	if err != nil {
+		return fmt.Errorf("create window: %w", err)
+	}
+	window.MakeContextCurrent()
+
+	// Initialize Glow. The functions are loaded through glfw so it works with any context api
+	if err := gl.InitWithProcAddrFunc(glfw.GetProcAddress); err != nil {
+		return fmt.Errorf("initialize gl: %w", err)
+	}
+
+	version := gl.GoStr(gl.GetString(gl.VERSION))
+	renderer := gl.GoStr(gl.GetString(gl.RENDERER))
+	fmt.Println("OpenGL version:", version, renderer)
+
+	if cfg.Headless {
+		return runHeadless(window, scene, cfg)
+	}
+
+	// Handles window resize. Calls the callback whenever the window changes in size
+	window.SetFramebufferSizeCallback(func(w *glfw.Window, width, height int) {
+		gl.Viewport(0, 0, int32(width), int32(height))
+		scene.Resize(width, height)
+	})
+
+	if cfg.HotReload {
+		shader.EnableHotReload(hotReloadInterval)
+	}
+
+	if err := scene.Init(window); err != nil {
+		return fmt.Errorf("init scene %s: %w", scene.Name(), err)
+	}
+	defer scene.Destroy()
+
+	var lastFrame float64
+	var shownStats bounds.CullStats
+
+	// Main loop
+	for !window.ShouldClose() {
+		processInput(window, scene)
+		shader.PollHotReload()
+
+		// deltaTime is the time between current frame and last frame
+		currentFrame := glfw.GetTime()
+		deltaTime := currentFrame - lastFrame
+		lastFrame = currentFrame
+
+		scene.Update(window, deltaTime)
+		scene.Render(currentFrame)
+		showCullStats(window, scene, &shownStats)
+
+		// Swap the front buffer and the back buffer
+		window.SwapBuffers()
+		// Checks if any events are triggered (like keyboard)
+		glfw.PollEvents()
+	}
+
+	return nil
+}
+
+// runHeadless renders cfg.Frames frames of the scene into an offscreen framebuffer
+// using a fixed time step and saves the last one in cfg.OutDir.
+func runHeadless(window *glfw.Window, scene Scene, cfg Config) error {
+	if cfg.Frames <= 0 {
+		return fmt.Errorf("headless mode needs at least one frame, got %d", cfg.Frames)
+	}
+
+	// The offscreen target replaces the window as the default framebuffer, so nothing
+	// has to be presented. Scenes use both depth and stencil testing
+	colorbuffer := framebuffer.Attachment{Point: gl.COLOR_ATTACHMENT0, InternalFormat: gl.RGBA8, Renderbuffer: true}
+	target, err := framebuffer.New(scene.Width(), scene.Height(), colorbuffer, framebuffer.DepthStencil())
+	if err != nil {
+		return fmt.Errorf("offscreen target: %w", err)
+	}
+	defer target.Delete()
+
+	framebuffer.SetDefault(target)
+	defer framebuffer.SetDefault(nil)
+
+	if err := scene.Init(window); err != nil {
+		return fmt.Errorf("init scene %s: %w", scene.Name(), err)
+	}
+	defer scene.Destroy()
+
+	// Scenes may bind their own framebuffers while initializing
+	target.Bind()
+	scene.Resize(scene.Width(), scene.Height())
+
+	for frame := 0; frame < cfg.Frames; frame++ {
+		scene.Update(window, headlessFrameTime)
+		scene.Render(float64(frame) * headlessFrameTime)
+	}
+	gl.Finish()
+
+	sshoter := sshot.NewScreenShoter(scene.Name(), scene.Width(), scene.Height())
+	path, err := sshoter.SaveOne(cfg.OutDir)
+	if err != nil {
+		return fmt.Errorf("save frame: %w", err)
+	}
+	fmt.Printf("app: rendered %d frames of %s to %s\n", cfg.Frames, scene.Name(), path)
+
+	return nil
+}
+
+// showCullStats shows in the title of the window how many objects the scene drew, when it
+// culls them and their number changed since shown.
+func showCullStats(w *glfw.Window, scene Scene, shown *bounds.CullStats) {
+	culling, ok := scene.(cullStatser)
+	if !ok {
+		return
+	}
+
+	stats := culling.CullStats()
+	if stats == *shown {
+		return
+	}
+	*shown = stats
+
+	w.SetTitle(fmt.Sprintf("%s - drawn %d of %d, culled %d", scene.Name(), stats.Visible(), stats.Tested, stats.Culled))
+}
+
+func processInput(w *glfw.Window, scene Scene) {
+	if w.GetKey(glfw.KeyEscape) == glfw.Press {
+		// Closes window
+		w.SetShouldClose(true)
+	}
+
+	if w.GetKey(glfw.KeyL) == glfw.Press {
+		// Enables wireframe drawing
+		gl.PolygonMode(gl.FRONT_AND_BACK, gl.LINE)
+	}
+
+	if w.GetKey(glfw.KeyF) == glfw.Press {
+		// Disables wireframe drawing
+		gl.PolygonMode(gl.FRONT_AND_BACK, gl.FILL)
+	}
+
+	if w.GetKey(glfw.KeyLeftControl) == glfw.Press && w.GetKey(glfw.KeyP) == glfw.Press {
+		// Takes a screen shot
+		sshoter := sshot.NewScreenShoter(scene.Name(), scene.Width(), scene.Height())
+		sshoter.TakeOne()
+	}
+}
@@ -0,0 +1,21 @@
+// Package assets holds the shaders, textures and models used by the scenes.
+package assets
+
+import (
+	"embed"
+	"io/fs"
+	"os"
+)
+
+//go:embed shaders textures models
+var embedded embed.FS
+
+// FS is where the scenes load their assets from. It defaults to the assets embedded
+// in the binary, so it runs from any directory.
+var FS fs.FS = embedded
+
+// UseDir makes the scenes load their assets from dir on disk instead, e.g. to edit
+// the shaders while a scene is running with hot reload.
+func UseDir(dir string) {
+	FS = os.DirFS(dir)
+}
@@ -1,5 +1,7 @@
 #version 330 core
 
+#include "shadows.glsl"
+
 in vec3 Normal;
 in vec3 FragPos;
 in vec2 TexCoords;
@@ -19,6 +21,7 @@ struct Light {
 
 uniform Material material;
 uniform Light light;
+uniform Shadow shadow;
 uniform vec3 viewPos;
 
 out vec4 FragColor;
@@ -40,6 +43,9 @@ void main() {
 	vec3 specTexel = texture(material.specular, TexCoords).rgb;
 	vec3 specular = light.specular * spec * specTexel;
 
-	vec3 result = ambient + diffuse + specular;
+	// shadow, the ambient light is not blocked
+	float inShadow = shadowFactor(shadow, FragPos, norm, lightDir);
+
+	vec3 result = ambient + (1.0 - inShadow) * (diffuse + specular);
 	FragColor = vec4(result, 1.0);
 }
@@ -0,0 +1,26 @@
+#version 330 core
+
+in vec3 FragPos;
+in vec3 Normal;
+
+uniform vec3 viewPos;
+uniform samplerCube skybox;
+// refraction is the ratio between the refractive indices of the two materials,
+// e.g. 1.00 / 1.52 from air to glass. Zero reflects the skybox instead
+uniform float refraction;
+
+out vec4 FragColor;
+
+void main() {
+	vec3 I = normalize(FragPos - viewPos);
+	vec3 N = normalize(Normal);
+
+	vec3 R;
+	if (refraction > 0.0) {
+		R = refract(I, N, refraction);
+	} else {
+		R = reflect(I, N);
+	}
+
+	FragColor = vec4(texture(skybox, R).rgb, 1.0);
+}
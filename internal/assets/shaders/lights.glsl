@@ -0,0 +1,97 @@
+// Phong lighting for the light package. Include it after #version and upload the lights
+// with light.DirectionalLight.Upload and friends, or with a light.UniformBuffer.
+//
+// The fields are ordered so the structs have no padding in the std140 layout used by
+// the uniform buffer. Keep them in sync with pkg/light/buffer.go.
+
+struct DirectionalLight {
+	vec3 direction;
+	vec3 ambient;
+	vec3 diffuse;
+	vec3 specular;
+};
+
+struct PointLight {
+	vec3 position;
+	float constant;
+	vec3 ambient;
+	float linear;
+	vec3 diffuse;
+	float quadratic;
+	vec3 specular;
+};
+
+struct SpotLight {
+	vec3 position;
+	float constant;
+	vec3 direction;
+	float linear;
+	vec3 ambient;
+	float quadratic;
+	vec3 diffuse;
+	float cutOff;
+	vec3 specular;
+	float outerCutOff;
+};
+
+// With LIGHTS_BLOCK defined the lights are read from a light.UniformBuffer, whose
+// sizes must be NR_POINT_LIGHTS and NR_SPOT_LIGHTS
+#ifdef LIGHTS_BLOCK
+layout (std140) uniform Lights {
+	DirectionalLight dirLight;
+	int nrPointLights;
+	int nrSpotLights;
+	PointLight pointLights[NR_POINT_LIGHTS];
+	SpotLight spotLights[NR_SPOT_LIGHTS];
+};
+#endif
+
+// SurfaceColors are the colors of the fragment lit by the lights.
+struct SurfaceColors {
+	vec3 diffuse;
+	vec3 specular;
+	float shininess;
+};
+
+// phong returns the ambient, diffuse and specular light reflected to viewDir.
+// lightDir points from the fragment to the light.
+vec3 phong(vec3 ambient, vec3 diffuse, vec3 specular, vec3 lightDir, vec3 normal, vec3 viewDir, SurfaceColors surface) {
+	float diff = max(dot(normal, lightDir), 0.0);
+
+	vec3 reflectDir = reflect(-lightDir, normal);
+	float spec = pow(max(dot(viewDir, reflectDir), 0.0), surface.shininess);
+
+	return ambient * surface.diffuse
+		+ diffuse * diff * surface.diffuse
+		+ specular * spec * surface.specular;
+}
+
+float attenuation(float constant, float linear, float quadratic, float distance) {
+	return 1.0 / (constant + linear * distance + quadratic * (distance * distance));
+}
+
+vec3 calcDirLight(DirectionalLight light, vec3 normal, vec3 viewDir, SurfaceColors surface) {
+	vec3 lightDir = normalize(-light.direction);
+	return phong(light.ambient, light.diffuse, light.specular, lightDir, normal, viewDir, surface);
+}
+
+vec3 calcPointLight(PointLight light, vec3 fragPos, vec3 normal, vec3 viewDir, SurfaceColors surface) {
+	vec3 lightDir = normalize(light.position - fragPos);
+	float distance = length(light.position - fragPos);
+
+	vec3 result = phong(light.ambient, light.diffuse, light.specular, lightDir, normal, viewDir, surface);
+	return result * attenuation(light.constant, light.linear, light.quadratic, distance);
+}
+
+vec3 calcSpotLight(SpotLight light, vec3 fragPos, vec3 normal, vec3 viewDir, SurfaceColors surface) {
+	vec3 lightDir = normalize(light.position - fragPos);
+	float distance = length(light.position - fragPos);
+
+	// The ambient light is not limited to the cone
+	float theta = dot(lightDir, normalize(-light.direction));
+	float epsilon = light.cutOff - light.outerCutOff;
+	float intensity = clamp((theta - light.outerCutOff) / epsilon, 0.0, 1.0);
+
+	vec3 result = phong(light.ambient, light.diffuse * intensity, light.specular * intensity, lightDir, normal, viewDir, surface);
+	return result * attenuation(light.constant, light.linear, light.quadratic, distance);
+}
@@ -1,5 +1,11 @@
 #version 330 core
 
+struct Material {
+	sampler2D diffuse;
+	sampler2D specular;
+	float shininess;
+};
+
 struct Light {
 	vec3 position;
 	vec3 ambient;
@@ -14,9 +20,7 @@ in vec3 Normal;
 in vec3 FragPos;
 in vec2 TexCoords;
 
-uniform sampler2D texture_diffuse1;
-uniform sampler2D texture_specular1;
-
+uniform Material material;
 uniform Light light;
 uniform vec3 viewPos;
 
@@ -24,20 +28,19 @@ out vec4 FragColor;
 
 void main() {
 	// ambient
-	vec3 ambient = light.ambient * vec3(texture(texture_diffuse1, TexCoords));
+	vec3 ambient = light.ambient * vec3(texture(material.diffuse, TexCoords));
 
 	// diffuse
 	vec3 norm = normalize(Normal);
 	vec3 lightDir = normalize(light.position - FragPos);
 	float diff = max(dot(norm, lightDir), 0.0);
-	vec3 diffuse = light.diffuse * diff * vec3(texture(texture_diffuse1, TexCoords));
+	vec3 diffuse = light.diffuse * diff * vec3(texture(material.diffuse, TexCoords));
 
 	// specular
 	vec3 viewDir = normalize(viewPos - FragPos);
 	vec3 reflectDir = reflect(-lightDir, norm);
-	float shine = 32.0;
-	float spec = pow(max(dot(viewDir, reflectDir), 0.0), shine);
-	vec3 specTexel = texture(texture_specular1, TexCoords).rgb;
+	float spec = pow(max(dot(viewDir, reflectDir), 0.0), material.shininess);
+	vec3 specTexel = texture(material.specular, TexCoords).rgb;
 	vec3 specular = light.specular * spec * specTexel;
 
 	// attenuation
@@ -51,6 +54,6 @@ void main() {
 	vec3 result = ambient + diffuse + specular;
 	FragColor = vec4(result, 1.0);
 
-	// FragColor = texture(texture_specular1, TexCoords);
+	// FragColor = texture(material.specular, TexCoords);
 	// FragColor = vec4(0.0, TexCoords.x, TexCoords.y, 1.0);
 }
@@ -0,0 +1,51 @@
+#version 330 core
+
+#include "lights.glsl"
+
+in vec3 Normal;
+in vec3 FragPos;
+in vec2 TexCoords;
+
+struct Material {
+	sampler2D diffuse;
+	sampler2D specular;
+	float shininess;
+};
+
+uniform Material material;
+// With LIGHTS_BLOCK the lights are in the Lights block of lights.glsl instead
+#ifndef LIGHTS_BLOCK
+uniform DirectionalLight dirLight;
+uniform PointLight pointLights[NR_POINT_LIGHTS];
+uniform SpotLight spotLight;
+#endif
+uniform vec3 viewPos;
+
+out vec4 FragColor;
+
+void main() {
+	vec3 norm = normalize(Normal);
+	vec3 viewDir = normalize(viewPos - FragPos);
+
+	SurfaceColors surface;
+	surface.diffuse = texture(material.diffuse, TexCoords).rgb;
+	surface.specular = texture(material.specular, TexCoords).rgb;
+	surface.shininess = material.shininess;
+
+	vec3 result = calcDirLight(dirLight, norm, viewDir, surface);
+#ifdef LIGHTS_BLOCK
+	for (int i = 0; i < nrPointLights; i++) {
+		result += calcPointLight(pointLights[i], FragPos, norm, viewDir, surface);
+	}
+	for (int i = 0; i < nrSpotLights; i++) {
+		result += calcSpotLight(spotLights[i], FragPos, norm, viewDir, surface);
+	}
+#else
+	for (int i = 0; i < NR_POINT_LIGHTS; i++) {
+		result += calcPointLight(pointLights[i], FragPos, norm, viewDir, surface);
+	}
+	result += calcSpotLight(spotLight, FragPos, norm, viewDir, surface);
+#endif
+
+	FragColor = vec4(result, 1.0);
+}
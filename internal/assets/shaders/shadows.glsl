@@ -0,0 +1,95 @@
+// Shadow mapping for the shadow package. Include it after #version and set the
+// uniforms with shadow.Map.Bind and shadow.CubeMap.Bind.
+
+// Shadow of a directional or spot light.
+struct Shadow {
+	sampler2D map;
+	mat4 lightSpace;
+	float bias;
+	float slopeBias;
+	int pcf;
+};
+
+// PointShadow is the shadow of a point light.
+struct PointShadow {
+	samplerCube map;
+	vec3 position;
+	float far;
+	float bias;
+	float slopeBias;
+	int pcf;
+};
+
+// shadowBias grows the bias with the slope of the surface relative to the light.
+float shadowBias(float bias, float slopeBias, vec3 normal, vec3 lightDir) {
+	float cosTheta = clamp(dot(normal, lightDir), 0.0, 1.0);
+	float tanTheta = sqrt(1.0 - cosTheta * cosTheta) / max(cosTheta, 0.001);
+	return bias + slopeBias * min(tanTheta, 10.0);
+}
+
+// shadowFactor returns how much of fragPos is in the shadow, from 0.0 (lit) to 1.0.
+// lightDir points from the fragment to the light.
+float shadowFactor(Shadow shadow, vec3 fragPos, vec3 normal, vec3 lightDir) {
+	vec4 lightSpacePos = shadow.lightSpace * vec4(fragPos, 1.0);
+	// From clip space to [0, 1]
+	vec3 coords = lightSpacePos.xyz / lightSpacePos.w * 0.5 + 0.5;
+
+	// Outside of the shadow map nothing casts shadows
+	if (coords.z > 1.0 || any(lessThan(coords.xy, vec2(0.0))) || any(greaterThan(coords.xy, vec2(1.0)))) {
+		return 0.0;
+	}
+
+	float bias = shadowBias(shadow.bias, shadow.slopeBias, normal, lightDir);
+	vec2 texelSize = 1.0 / vec2(textureSize(shadow.map, 0));
+
+	// Percentage-closer filtering: the share of the texels around that are closer to the light
+	float shadowed = 0.0;
+	for (int x = -shadow.pcf; x <= shadow.pcf; x++) {
+		for (int y = -shadow.pcf; y <= shadow.pcf; y++) {
+			float closest = texture(shadow.map, coords.xy + vec2(x, y) * texelSize).r;
+			shadowed += coords.z - bias > closest ? 1.0 : 0.0;
+		}
+	}
+
+	float samples = float((2 * shadow.pcf + 1) * (2 * shadow.pcf + 1));
+	return shadowed / samples;
+}
+
+// pointShadowOffsets are directions spread around the sampled one, for the filtering
+// of point shadows.
+const vec3 pointShadowOffsets[20] = vec3[](
+	vec3(1, 1, 1), vec3(1, -1, 1), vec3(-1, -1, 1), vec3(-1, 1, 1),
+	vec3(1, 1, -1), vec3(1, -1, -1), vec3(-1, -1, -1), vec3(-1, 1, -1),
+	vec3(1, 1, 0), vec3(1, -1, 0), vec3(-1, -1, 0), vec3(-1, 1, 0),
+	vec3(1, 0, 1), vec3(-1, 0, 1), vec3(1, 0, -1), vec3(-1, 0, -1),
+	vec3(0, 1, 1), vec3(0, -1, 1), vec3(0, -1, -1), vec3(0, 1, -1)
+);
+
+// pointShadowFactor returns how much of fragPos is in the shadow of a point light, from
+// 0.0 (lit) to 1.0.
+float pointShadowFactor(PointShadow shadow, vec3 fragPos, vec3 normal) {
+	vec3 toFrag = fragPos - shadow.position;
+	float current = length(toFrag);
+	if (current > shadow.far) {
+		return 0.0;
+	}
+
+	// The bias is in the same units as the distances, not in [0, 1]
+	float bias = shadowBias(shadow.bias, shadow.slopeBias, normal, -toFrag / current) * shadow.far;
+
+	if (shadow.pcf == 0) {
+		float closest = texture(shadow.map, toFrag).r * shadow.far;
+		return current - bias > closest ? 1.0 : 0.0;
+	}
+
+	// The filter is wider for the fragments further from the light, like the texels
+	float radius = float(shadow.pcf) * current / shadow.far * 0.05;
+
+	float shadowed = 0.0;
+	for (int i = 0; i < 20; i++) {
+		float closest = texture(shadow.map, toFrag + pointShadowOffsets[i] * radius).r * shadow.far;
+		shadowed += current - bias > closest ? 1.0 : 0.0;
+	}
+
+	return shadowed / 20.0;
+}
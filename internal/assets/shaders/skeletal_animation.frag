@@ -0,0 +1,45 @@
+#version 330 core
+
+struct Material {
+	sampler2D diffuse;
+	float shininess;
+};
+
+struct Light {
+	vec3 direction;
+	vec3 ambient;
+	vec3 diffuse;
+	vec3 specular;
+};
+
+in vec3 FragPos;
+in vec3 Normal;
+in vec2 TexCoords;
+
+uniform Material material;
+uniform Light light;
+uniform vec3 viewPos;
+
+out vec4 FragColor;
+
+void main() {
+	// Materials without a texture bind one of their color, see material.Bind
+	vec3 color = texture(material.diffuse, TexCoords).rgb;
+
+	// ambient
+	vec3 ambient = light.ambient * color;
+
+	// diffuse
+	vec3 norm = normalize(Normal);
+	vec3 lightDir = normalize(-light.direction);
+	float diff = max(dot(norm, lightDir), 0.0);
+	vec3 diffuse = light.diffuse * diff * color;
+
+	// specular
+	vec3 viewDir = normalize(viewPos - FragPos);
+	vec3 halfwayDir = normalize(lightDir + viewDir);
+	float spec = pow(max(dot(norm, halfwayDir), 0.0), material.shininess);
+	vec3 specular = light.specular * spec * 0.3;
+
+	FragColor = vec4(ambient + diffuse + specular, 1.0);
+}
@@ -0,0 +1,41 @@
+#version 330 core
+
+// MAX_BONES is defined by the scene, the number of bones of the skeleton
+#ifndef MAX_BONES
+#define MAX_BONES 100
+#endif
+
+layout (location = 0) in vec3 position;
+layout (location = 1) in vec3 normal;
+layout (location = 2) in vec2 texCoords;
+layout (location = 5) in ivec4 boneIds;
+layout (location = 6) in vec4 weights;
+
+uniform mat4 model;
+uniform mat4 view;
+uniform mat4 projection;
+uniform mat4 bones[MAX_BONES];
+
+out vec3 FragPos;
+out vec3 Normal;
+out vec2 TexCoords;
+
+void main() {
+	// Each bone moves the vertex from the bind pose to its animated pose, the result is
+	// the blend of the positions by the weights
+	mat4 skin = mat4(0.0);
+	for (int i = 0; i < 4; i++) {
+		skin += bones[boneIds[i]] * weights[i];
+	}
+	// Vertices without bones are not animated
+	if (weights[0] + weights[1] + weights[2] + weights[3] == 0.0) {
+		skin = mat4(1.0);
+	}
+
+	mat4 skinnedModel = model * skin;
+	FragPos = vec3(skinnedModel * vec4(position, 1.0));
+	Normal = mat3(transpose(inverse(skinnedModel))) * normal;
+	TexCoords = texCoords;
+
+	gl_Position = projection * view * vec4(FragPos, 1.0);
+}
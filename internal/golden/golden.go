@@ -0,0 +1,192 @@
+package golden
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+)
+
+// maxDelta is the biggest possible YIQ distance between two colors.
+const maxDelta = 35215.0
+
+// Options controls how different two images can be before the comparison fails.
+type Options struct {
+	// Threshold is the perceptual distance, between 0 and 1, above which two pixels are
+	// considered different. Smaller values make the comparison more sensitive.
+	Threshold float64
+
+	// MaxDiffRatio is the fraction of pixels, between 0 and 1, allowed to be different.
+	MaxDiffRatio float64
+}
+
+// DefaultOptions tolerates the small differences between GL implementations, like
+// rasterization rules and texture filtering, while still catching real regressions.
+var DefaultOptions = Options{
+	Threshold:    0.1,
+	MaxDiffRatio: 0.001,
+}
+
+// Result is the outcome of comparing an image against its golden image.
+type Result struct {
+	Width      int
+	Height     int
+	DiffPixels int
+	// Diff highlights the different pixels in red over a faded copy of the golden image.
+	Diff *image.RGBA
+}
+
+// Ratio returns the fraction of pixels that are different.
+func (r *Result) Ratio() float64 {
+	return float64(r.DiffPixels) / float64(r.Width*r.Height)
+}
+
+// Passed reports whether the images are similar enough under opts.
+func (r *Result) Passed(opts Options) bool {
+	return r.Ratio() <= opts.MaxDiffRatio
+}
+
+// Compare diffs got against want pixel by pixel using the YIQ perceptual color distance.
+// Both images must have the same size.
+func Compare(got, want image.Image, opts Options) (*Result, error) {
+	if got.Bounds().Size() != want.Bounds().Size() {
+		return nil, fmt.Errorf("golden: image size %v does not match golden size %v",
+			got.Bounds().Size(), want.Bounds().Size())
+	}
+
+	size := want.Bounds().Size()
+	result := &Result{
+		Width:  size.X,
+		Height: size.Y,
+		Diff:   image.NewRGBA(image.Rect(0, 0, size.X, size.Y)),
+	}
+
+	maxAllowed := opts.Threshold * opts.Threshold * maxDelta
+	gotMin := got.Bounds().Min
+	wantMin := want.Bounds().Min
+
+	for y := 0; y < size.Y; y++ {
+		for x := 0; x < size.X; x++ {
+			a := got.At(gotMin.X+x, gotMin.Y+y)
+			b := want.At(wantMin.X+x, wantMin.Y+y)
+
+			if colorDelta(a, b) > maxAllowed {
+				result.DiffPixels++
+				result.Diff.Set(x, y, color.RGBA{R: 255, A: 255})
+				continue
+			}
+
+			// Faded gray copy of the golden image to give context to the different pixels
+			gray := uint8(255 - (255-luma(b))/10)
+			result.Diff.Set(x, y, color.RGBA{R: gray, G: gray, B: gray, A: 255})
+		}
+	}
+
+	return result, nil
+}
+
+// CompareFiles compares the png at gotPath against the golden png at wantPath. When the
+// comparison fails the diff image is saved next to gotPath with a _diff suffix.
+func CompareFiles(gotPath, wantPath string, opts Options) (*Result, error) {
+	got, err := Load(gotPath)
+	if err != nil {
+		return nil, err
+	}
+
+	want, err := Load(wantPath)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := Compare(got, want, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if !result.Passed(opts) {
+		if err := Save(DiffPath(gotPath), result.Diff); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// DiffPath returns where the diff image of the png at path is saved.
+func DiffPath(path string) string {
+	ext := filepath.Ext(path)
+	return path[:len(path)-len(ext)] + "_diff" + ext
+}
+
+// Load decodes the png at path.
+func Load(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("golden: %w", err)
+	}
+	defer f.Close()
+
+	img, err := png.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("golden: decode %s: %w", path, err)
+	}
+
+	return img, nil
+}
+
+// Save encodes img as a png at path, creating its directory if needed.
+func Save(path string, img image.Image) error {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return png.Encode(f, img)
+}
+
+// colorDelta returns the squared YIQ distance between two colors, blended over white
+// so transparent pixels are compared by how they look.
+func colorDelta(a, b color.Color) float64 {
+	r1, g1, b1 := blend(a)
+	r2, g2, b2 := blend(b)
+
+	y := rgbToY(r1, g1, b1) - rgbToY(r2, g2, b2)
+	i := rgbToI(r1, g1, b1) - rgbToI(r2, g2, b2)
+	q := rgbToQ(r1, g1, b1) - rgbToQ(r2, g2, b2)
+
+	return 0.5053*y*y + 0.299*i*i + 0.1957*q*q
+}
+
+func blend(c color.Color) (r, g, b float64) {
+	cr, cg, cb, ca := c.RGBA()
+	alpha := float64(ca) / 0xffff
+	white := 255 * (1 - alpha)
+
+	// RGBA returns alpha-premultiplied values in the [0, 0xffff] range
+	return float64(cr)/0x101 + white, float64(cg)/0x101 + white, float64(cb)/0x101 + white
+}
+
+func luma(c color.Color) uint8 {
+	r, g, b := blend(c)
+	return uint8(math.Min(rgbToY(r, g, b), 255))
+}
+
+func rgbToY(r, g, b float64) float64 {
+	return r*0.29889531 + g*0.58662247 + b*0.11448223
+}
+
+func rgbToI(r, g, b float64) float64 {
+	return r*0.59597799 - g*0.27417610 - b*0.32180189
+}
+
+func rgbToQ(r, g, b float64) float64 {
+	return r*0.21147017 - g*0.52261711 + b*0.31114694
+}
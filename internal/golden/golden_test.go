@@ -0,0 +1,201 @@
+package golden
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var (
+	gray  = color.RGBA{R: 128, G: 128, B: 128, A: 255}
+	red   = color.RGBA{R: 255, A: 255}
+	black = color.RGBA{A: 255}
+	white = color.RGBA{R: 255, G: 255, B: 255, A: 255}
+)
+
+// newImage returns a 10x10 gray image with the pixels in changed set to their colors.
+func newImage(changed map[image.Point]color.RGBA) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	for y := range 10 {
+		for x := range 10 {
+			img.SetRGBA(x, y, gray)
+		}
+	}
+	for p, c := range changed {
+		img.SetRGBA(p.X, p.Y, c)
+	}
+	return img
+}
+
+func TestColorDelta(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b color.Color
+		want float64
+	}{
+		{name: "same", a: gray, b: gray, want: 0},
+		// Only the brightness differs
+		{name: "black and white", a: black, b: white, want: 0.5053 * 255 * 255},
+		{name: "transparent over white", a: color.RGBA{}, b: white, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := colorDelta(tt.a, tt.b); math.Abs(got-tt.want) > 1 {
+				t.Errorf("colorDelta = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name    string
+		changed map[image.Point]color.RGBA
+		// diffs are the pixels expected to be different
+		diffs []image.Point
+	}{
+		{name: "identical"},
+		{name: "under the threshold", changed: map[image.Point]color.RGBA{{3, 4}: {R: 130, G: 130, B: 130, A: 255}}},
+		{name: "over the threshold", changed: map[image.Point]color.RGBA{{3, 4}: red}, diffs: []image.Point{{3, 4}}},
+		{name: "two pixels", changed: map[image.Point]color.RGBA{{0, 0}: red, {9, 9}: black}, diffs: []image.Point{{0, 0}, {9, 9}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Compare(newImage(tt.changed), newImage(nil), DefaultOptions)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if result.Width != 10 || result.Height != 10 {
+				t.Errorf("size = %dx%d, want 10x10", result.Width, result.Height)
+			}
+			if result.DiffPixels != len(tt.diffs) {
+				t.Errorf("got %d different pixels, want %d", result.DiffPixels, len(tt.diffs))
+			}
+
+			for _, p := range tt.diffs {
+				if got := result.Diff.RGBAAt(p.X, p.Y); got != red {
+					t.Errorf("diff pixel %v = %v, want red", p, got)
+				}
+			}
+			// The other pixels are a faded copy of the golden image
+			if len(tt.diffs) == 0 {
+				if got := result.Diff.RGBAAt(3, 4); got.R != got.G || got.R <= gray.R {
+					t.Errorf("diff pixel (3, 4) = %v, want a light gray", got)
+				}
+			}
+		})
+	}
+}
+
+func TestCompareSubImage(t *testing.T) {
+	// The images are compared from their own origins
+	parent := image.NewRGBA(image.Rect(0, 0, 20, 20))
+	got := parent.SubImage(image.Rect(5, 5, 15, 15)).(*image.RGBA)
+	for y := 5; y < 15; y++ {
+		for x := 5; x < 15; x++ {
+			got.SetRGBA(x, y, gray)
+		}
+	}
+
+	result, err := Compare(got, newImage(nil), DefaultOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.DiffPixels != 0 {
+		t.Errorf("got %d different pixels, want 0", result.DiffPixels)
+	}
+}
+
+func TestCompareSizeMismatch(t *testing.T) {
+	small := image.NewRGBA(image.Rect(0, 0, 10, 9))
+	if _, err := Compare(small, newImage(nil), DefaultOptions); err == nil {
+		t.Error("Compare of images of different sizes succeeded")
+	}
+}
+
+func TestResultPassed(t *testing.T) {
+	opts := Options{Threshold: 0.1, MaxDiffRatio: 0.01}
+
+	tests := []struct {
+		diffPixels int
+		ratio      float64
+		passed     bool
+	}{
+		{diffPixels: 0, ratio: 0, passed: true},
+		// Exactly the allowed ratio of the 100 pixels
+		{diffPixels: 1, ratio: 0.01, passed: true},
+		{diffPixels: 2, ratio: 0.02, passed: false},
+	}
+
+	for _, tt := range tests {
+		r := &Result{Width: 10, Height: 10, DiffPixels: tt.diffPixels}
+		if got := r.Ratio(); got != tt.ratio {
+			t.Errorf("%d pixels: Ratio() = %v, want %v", tt.diffPixels, got, tt.ratio)
+		}
+		if got := r.Passed(opts); got != tt.passed {
+			t.Errorf("%d pixels: Passed() = %v, want %v", tt.diffPixels, got, tt.passed)
+		}
+	}
+}
+
+func TestDiffPath(t *testing.T) {
+	tests := []struct {
+		path, want string
+	}{
+		{path: "images/headless/cube.png", want: "images/headless/cube_diff.png"},
+		{path: "shot.final.png", want: "shot.final_diff.png"},
+		{path: "v1.2/shot", want: "v1.2/shot_diff"},
+	}
+
+	for _, tt := range tests {
+		if got := DiffPath(tt.path); got != tt.want {
+			t.Errorf("DiffPath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestCompareFiles(t *testing.T) {
+	dir := t.TempDir()
+	wantPath := filepath.Join(dir, "golden", "cube.png")
+	if err := Save(wantPath, newImage(nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		changed map[image.Point]color.RGBA
+		// saved is whether the diff image is saved
+		saved bool
+	}{
+		{name: "passed"},
+		{name: "failed", changed: map[image.Point]color.RGBA{{3, 4}: red}, saved: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotPath := filepath.Join(dir, tt.name, "cube.png")
+			if err := Save(gotPath, newImage(tt.changed)); err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := CompareFiles(gotPath, wantPath, DefaultOptions)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Passed(DefaultOptions) == tt.saved {
+				t.Errorf("Passed() = %v, want %v", !tt.saved, tt.saved)
+			}
+
+			_, err = os.Stat(DiffPath(gotPath))
+			if saved := err == nil; saved != tt.saved {
+				t.Errorf("diff image saved = %v, want %v", saved, tt.saved)
+			}
+		})
+	}
+}
@@ -1,20 +1,21 @@
 package scenes
 
 import (
-	"fmt"
-	"log"
 	"math"
 
 	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/glfw/v3.3/glfw"
 	"github.com/go-gl/mathgl/mgl32"
 	"github.com/go-gl/mathgl/mgl64"
+	"github.com/igoramorim/gopengl/internal/assets"
+	"github.com/igoramorim/gopengl/pkg/camera"
 	"github.com/igoramorim/gopengl/pkg/shader"
 	"github.com/igoramorim/gopengl/pkg/texture"
+	"github.com/igoramorim/gopengl/pkg/vertex"
 )
 
-func NewCamera() Camera {
-	return Camera{
+func NewCamera() *Camera {
+	return &Camera{
 		cameraPos:   mgl32.Vec3{0.0, 0.0, 3.0},
 		cameraFront: mgl32.Vec3{0.0, 0.0, -1.0},
 		cameraUp:    mgl32.Vec3{0.0, 1.0, 0.0},
@@ -23,24 +24,32 @@ func NewCamera() Camera {
 		lastY:       float64(height) / 2,
 		yaw:         -90.0,
 		pitch:       0.0,
-		fov:         45.0,
-		deltaTime:   0.0,
-		lastFrame:   0.0,
+		projection: camera.Projection{
+			Mode:   camera.Perspective,
+			Fov:    45.0,
+			Near:   0.1,
+			Far:    100.0,
+			Aspect: float32(width) / float32(height),
+		},
 	}
 }
 
 type Camera struct {
-	cameraPos   mgl32.Vec3
-	cameraFront mgl32.Vec3
-	cameraUp    mgl32.Vec3
-	firstMouse  bool
-	lastX       float64
-	lastY       float64
-	yaw         float64
-	pitch       float64
-	fov         float64
-	deltaTime   float64 // Time between current frame and last frame
-	lastFrame   float64
+	cameraPos     mgl32.Vec3
+	cameraFront   mgl32.Vec3
+	cameraUp      mgl32.Vec3
+	firstMouse    bool
+	lastX         float64
+	lastY         float64
+	yaw           float64
+	pitch         float64
+	projection    camera.Projection
+	shader        *shader.Shader
+	vao           uint32
+	vbo           uint32
+	texture0      *texture.Texture
+	texture1      *texture.Texture
+	cubePositions []mgl32.Vec3
 }
 
 func (s Camera) Name() string {
@@ -55,43 +64,18 @@ func (s Camera) Height() int {
 	return height
 }
 
-func (s Camera) Show() {
-	if err := glfw.Init(); err != nil {
-		log.Fatalln("initialize glfw:", err)
-	}
-	defer glfw.Terminate()
-
-	glfw.WindowHint(glfw.Resizable, glfw.True)
-	glfw.WindowHint(glfw.ContextVersionMajor, 4)
-	glfw.WindowHint(glfw.ContextVersionMinor, 1)
-	glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
-	glfw.WindowHint(glfw.OpenGLForwardCompatible, glfw.True)
-
-	window, err := glfw.CreateWindow(s.Width(), s.Height(), s.Name(), nil, nil)
-	if err != nil {
-		panic(err)
-	}
-	window.MakeContextCurrent()
-
-	window.SetFramebufferSizeCallback(frameBufferSizeCallback)
-
+func (s *Camera) Init(w *glfw.Window) error {
 	// Handles mouse position. Calls mouseCallback every time the cursor moves
-	window.SetCursorPosCallback(s.mouseCallback)
+	w.SetCursorPosCallback(s.mouseCallback)
 	// Handles mouse scroll. Calls mouseScrollCallback evert time the scrolling is used
-	window.SetScrollCallback(s.mouseScrollCallback)
+	w.SetScrollCallback(s.mouseScrollCallback)
 	// Hides the mouse cursor
-	window.SetInputMode(glfw.CursorMode, glfw.CursorDisabled)
-
-	if err := gl.Init(); err != nil {
-		panic(err)
-	}
+	w.SetInputMode(glfw.CursorMode, glfw.CursorDisabled)
 
-	version := gl.GoStr(gl.GetString(gl.VERSION))
-	fmt.Println("OpenGL version:", version)
-
-	shader, err := shader.New("internal/assets/shaders/camera.vert", "internal/assets/shaders/camera.frag")
+	var err error
+	s.shader, err = shader.NewFS(assets.FS, "shaders/camera.vert", "shaders/camera.frag")
 	if err != nil {
-		panic(err)
+		return err
 	}
 
 	var vertices = []float32{
@@ -138,56 +122,39 @@ func (s Camera) Show() {
 		-0.5, 0.5, 0.5, 0.0, 0.0,
 		-0.5, 0.5, -0.5, 0.0, 1.0,
 	}
+	gl.GenVertexArrays(1, &s.vao)
+	gl.BindVertexArray(s.vao)
 
-	var vao uint32
-	gl.GenVertexArrays(1, &vao)
-	gl.BindVertexArray(vao)
-
-	var vbo uint32
-	gl.GenBuffers(1, &vbo)
-	gl.BindBuffer(gl.ARRAY_BUFFER, vbo)
+	gl.GenBuffers(1, &s.vbo)
+	gl.BindBuffer(gl.ARRAY_BUFFER, s.vbo)
 	gl.BufferData(gl.ARRAY_BUFFER, len(vertices)*floatSize, gl.Ptr(vertices), gl.STATIC_DRAW)
 
-	// Position attribute
-	gl.VertexAttribPointer(0, 3, gl.FLOAT, false, 5*floatSize, nil)
-	gl.EnableVertexAttribArray(0)
+	// Position and texture coord attributes
+	vertex.Floats(3, 2).Apply()
 
-	// Texture Coord attribute
-	gl.VertexAttribPointerWithOffset(1, 2, gl.FLOAT, false, 5*floatSize, 3*floatSize)
-	gl.EnableVertexAttribArray(1)
+	gl.BindVertexArray(s.vao)
 
-	gl.BindVertexArray(vao)
-
-	texture0, err := texture.New("internal/assets/textures/container.jpg", gl.TEXTURE_2D, gl.TEXTURE0, gl.RGBA, gl.RGBA, gl.UNSIGNED_INT)
+	s.texture0, err = texture.NewFS(assets.FS, "textures/container.jpg", gl.TEXTURE0, texture.DefaultOptions)
 	if err != nil {
-		panic(err)
+		return err
 	}
 
-	texture1, err := texture.New("internal/assets/textures/awesomeface.png", gl.TEXTURE_2D, gl.TEXTURE1, gl.RGBA, gl.RGBA, gl.UNSIGNED_INT)
+	s.texture1, err = texture.NewFS(assets.FS, "textures/awesomeface.png", gl.TEXTURE1, texture.DefaultOptions)
 	if err != nil {
-		panic(err)
+		return err
 	}
 
-	// Clean up all resources
-	defer func() {
-		gl.DeleteVertexArrays(1, &vao)
-		gl.DeleteBuffers(1, &vbo)
-		shader.Delete()
-		texture0.Delete()
-		texture1.Delete()
-	}()
-
-	shader.Use()
+	s.shader.Use()
 
-	texture0.ActiveAndBind()
-	shader.SetInt("texture0", 0)
+	s.texture0.ActiveAndBind()
+	s.shader.SetInt("texture0", 0)
 
-	texture1.ActiveAndBind()
-	shader.SetInt("texture1", 1)
+	s.texture1.ActiveAndBind()
+	s.shader.SetInt("texture1", 1)
 
 	gl.Enable(gl.DEPTH_TEST)
 
-	cubePositions := []mgl32.Vec3{
+	s.cubePositions = []mgl32.Vec3{
 		mgl32.Vec3{0.0, 0.0, 0.0},
 		mgl32.Vec3{2.0, 5.0, -15.0},
 		mgl32.Vec3{-2.0, 5.0, -15.0},
@@ -199,64 +166,12 @@ func (s Camera) Show() {
 		mgl32.Vec3{-2.0, -2.5, -5.0},
 	}
 
-	// Main loop
-	for !window.ShouldClose() {
-		s.processInput(window)
-
-		gl.ClearColor(0.2, 0.3, 0.3, 1.0)
-		gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
-
-		// Per frame logic
-		currentFrame := glfw.GetTime()
-		s.deltaTime = currentFrame - s.lastFrame
-		s.lastFrame = currentFrame
-
-		time := glfw.GetTime()
-
-		// Transformations to make it 3D
-
-		viewMatrix := mgl32.Ident4()
-		viewMatrix = mgl32.LookAtV(
-			s.cameraPos,                    // Position of the camera - 'eye'
-			s.cameraPos.Add(s.cameraFront), // Target position
-			s.cameraUp,                     // Vector that points UP in the world space
-		)
-
-		projectionMatrix := mgl32.Ident4()
-		projectionMatrix = mgl32.Perspective(mgl32.DegToRad(float32(s.fov)), width/height, 0.1, 100.0)
-
-		shader.SetMat4("view", viewMatrix)
-		shader.SetMat4("projection", projectionMatrix)
-
-		for _, cubePos := range cubePositions {
-			modelMatrix := mgl32.Ident4()
-
-			translate := mgl32.Translate3D(cubePos.X(), cubePos.Y(), cubePos.Z())
-			angle := float32(time) * mgl32.DegToRad(45.0)
-			rotateX := mgl32.HomogRotate3D(angle, mgl32.Vec3{1.0, 0.0, 0.0})
-			rotateY := mgl32.HomogRotate3D(angle, mgl32.Vec3{0.0, 1.0, 0.0})
-			rotateZ := mgl32.HomogRotate3D(angle, mgl32.Vec3{0.0, 0.0, 1.0})
-
-			modelMatrix = modelMatrix.Mul4(translate)
-			modelMatrix = modelMatrix.Mul4(rotateX)
-			modelMatrix = modelMatrix.Mul4(rotateY)
-			modelMatrix = modelMatrix.Mul4(rotateZ)
-
-			shader.SetMat4("model", modelMatrix)
-
-			gl.DrawArrays(gl.TRIANGLES, 0, 36) // 36 vertices to make a cube
-		}
-
-		window.SwapBuffers()
-		glfw.PollEvents()
-	}
+	return nil
 }
 
-func (s *Camera) processInput(w *glfw.Window) {
-	processInput(w, s)
-
+func (s *Camera) Update(w *glfw.Window, deltaTime float64) {
 	// deltaTime used to make speed consistency among different hardware setups
-	cameraSpeed := 2.5 * float32(s.deltaTime)
+	cameraSpeed := 2.5 * float32(deltaTime)
 	if w.GetKey(glfw.KeyW) == glfw.Press {
 		s.cameraPos = s.cameraPos.Add(s.cameraFront.Mul(cameraSpeed))
 	}
@@ -274,6 +189,57 @@ func (s *Camera) processInput(w *glfw.Window) {
 	}
 }
 
+func (s *Camera) Render(time float64) {
+	gl.ClearColor(0.2, 0.3, 0.3, 1.0)
+	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+
+	// Transformations to make it 3D
+
+	viewMatrix := mgl32.Ident4()
+	viewMatrix = mgl32.LookAtV(
+		s.cameraPos,                    // Position of the camera - 'eye'
+		s.cameraPos.Add(s.cameraFront), // Target position
+		s.cameraUp,                     // Vector that points UP in the world space
+	)
+
+	projectionMatrix := s.projection.ProjectionMatrix()
+
+	s.shader.SetMat4("view", viewMatrix)
+	s.shader.SetMat4("projection", projectionMatrix)
+
+	for _, cubePos := range s.cubePositions {
+		modelMatrix := mgl32.Ident4()
+
+		translate := mgl32.Translate3D(cubePos.X(), cubePos.Y(), cubePos.Z())
+		angle := float32(time) * mgl32.DegToRad(45.0)
+		rotateX := mgl32.HomogRotate3D(angle, mgl32.Vec3{1.0, 0.0, 0.0})
+		rotateY := mgl32.HomogRotate3D(angle, mgl32.Vec3{0.0, 1.0, 0.0})
+		rotateZ := mgl32.HomogRotate3D(angle, mgl32.Vec3{0.0, 0.0, 1.0})
+
+		modelMatrix = modelMatrix.Mul4(translate)
+		modelMatrix = modelMatrix.Mul4(rotateX)
+		modelMatrix = modelMatrix.Mul4(rotateY)
+		modelMatrix = modelMatrix.Mul4(rotateZ)
+
+		s.shader.SetMat4("model", modelMatrix)
+
+		gl.DrawArrays(gl.TRIANGLES, 0, 36) // 36 vertices to make a cube
+	}
+}
+
+func (s *Camera) Resize(width, height int) {
+	s.projection.SetAspect(width, height)
+}
+
+// Destroy cleans up all resources
+func (s *Camera) Destroy() {
+	gl.DeleteVertexArrays(1, &s.vao)
+	gl.DeleteBuffers(1, &s.vbo)
+	s.shader.Delete()
+	s.texture0.Delete()
+	s.texture1.Delete()
+}
+
 func (s *Camera) mouseCallback(w *glfw.Window, xpos, ypos float64) {
 	if s.firstMouse {
 		s.lastX = xpos
@@ -310,13 +276,13 @@ func (s *Camera) mouseCallback(w *glfw.Window, xpos, ypos float64) {
 }
 
 func (s *Camera) mouseScrollCallback(w *glfw.Window, xoff, yoff float64) {
-	s.fov -= yoff
+	s.projection.Fov -= yoff
 
-	if s.fov < 1.0 {
-		s.fov = 1.0
+	if s.projection.Fov < 1.0 {
+		s.projection.Fov = 1.0
 	}
 
-	if s.fov > 45.0 {
-		s.fov = 45.0
+	if s.projection.Fov > 45.0 {
+		s.projection.Fov = 45.0
 	}
 }
@@ -1,10 +1,14 @@
 package scenes
 
 import (
+	"fmt"
+
 	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/glfw/v3.3/glfw"
-	"github.com/igoramorim/gopengl/internal/sshot"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/igoramorim/gopengl/pkg/bounds"
 	"github.com/igoramorim/gopengl/pkg/camera"
+	"github.com/igoramorim/gopengl/pkg/shader"
 )
 
 const (
@@ -15,27 +19,236 @@ const (
 	uint32Size = 4
 )
 
-func processInput(w *glfw.Window, scene Scene) {
-	if w.GetKey(glfw.KeyEscape) == glfw.Press {
-		// Closes window
-		w.SetShouldClose(true)
+func newCameraControls() cameraControls {
+	return cameraControls{
+		camera:     camera.New(camera.WithAspect(width, height)),
+		keysDown:   make(map[glfw.Key]bool),
+		firstMouse: true,
+		lastX:      float64(width) / 2,
+		lastY:      float64(height) / 2,
+	}
+}
+
+// cameraPathFile is where the J key saves the recorded camera path.
+const cameraPathFile = "camera_path.json"
+
+// cameraControls drives a camera.Camera with the keyboard and the mouse.
+// Scenes that use the fly-through camera embed it. The V key switches it to free flight,
+// with quaternions, and back. Those that set an orbit camera with useOrbit can switch to
+// it with the O key.
+//
+// The K key records the pose of the camera as a keyframe of a camera.Path, J saves the
+// path to cameraPathFile and N plays it or stops it.
+//
+// The scenes cull what is out of the view with cull, and the B key turns it off to
+// compare.
+type cameraControls struct {
+	camera *camera.Camera
+	// orbit is nil unless the scene calls useOrbit
+	orbit    *camera.Orbit
+	orbiting bool
+	// path is nil until a keyframe is recorded or FollowPath is called
+	path *camera.Path
+	// pathTime is the time along path, while playing it or recording it
+	pathTime float64
+	playing  bool
+	// culler tests what the scene draws against the frustum of the camera in use
+	culler bounds.Culler
+	// keysDown holds the keys that were down on the last frame
+	keysDown   map[glfw.Key]bool
+	firstMouse bool
+	lastX      float64
+	lastY      float64
+}
+
+// FollowPath plays path from its start, instead of the input driving the camera. It is
+// advanced by the time between the frames, so the headless renders, with their fixed
+// time step, are the same every time.
+func (c *cameraControls) FollowPath(path *camera.Path) {
+	c.path = path
+	c.pathTime = 0
+	c.playing = true
+}
+
+// useOrbit lets the O key switch between the fly-through camera and orbit. The orbit
+// camera rotates while dragging with the left mouse button, pans with the right one and
+// zooms with the scroll.
+func (c *cameraControls) useOrbit(orbit *camera.Orbit) {
+	orbit.Aspect = c.camera.Aspect
+	c.orbit = orbit
+}
+
+// resize follows the size of the framebuffer with the projections of the cameras.
+func (c *cameraControls) resize(width, height int) {
+	c.camera.SetAspect(width, height)
+	if c.orbit != nil {
+		c.orbit.SetAspect(width, height)
+	}
+}
+
+// cull starts the culling of a frame against the frustum of the camera in use, whose
+// matrices must not change until it is drawn.
+func (c *cameraControls) cull() *bounds.Culler {
+	c.culler.Reset(c.view().ViewProjection())
+	return &c.culler
+}
+
+// CullStats returns how many objects the last frame tested and culled, e.g. for a debug
+// overlay.
+func (c *cameraControls) CullStats() bounds.CullStats {
+	return c.culler.Stats
+}
+
+// view returns the camera in use.
+func (c *cameraControls) view() camera.Viewer {
+	if c.orbiting {
+		return c.orbit
+	}
+	return c.camera
+}
+
+// setOrbiting switches to the orbit camera, showing the cursor to drag with it, or back
+// to the fly-through camera.
+func (c *cameraControls) setOrbiting(w *glfw.Window, orbiting bool) {
+	c.orbiting = orbiting
+	// The cursor jumps when its mode changes
+	c.firstMouse = true
+
+	if orbiting {
+		w.SetInputMode(glfw.CursorMode, glfw.CursorNormal)
+	} else {
+		w.SetInputMode(glfw.CursorMode, glfw.CursorDisabled)
+	}
+}
+
+// attach hides the mouse cursor and registers the mouse callbacks on the window.
+func (c *cameraControls) attach(w *glfw.Window) {
+	// Handles mouse position. Calls mouseCallback every time the cursor moves
+	w.SetCursorPosCallback(c.mouseCallback)
+	// Handles mouse scroll. Calls mouseScrollCallback evert time the scrolling is used
+	w.SetScrollCallback(c.mouseScrollCallback)
+	// Hides the mouse cursor
+	w.SetInputMode(glfw.CursorMode, glfw.CursorDisabled)
+}
+
+// justPressed tells if key is down and was not on the last frame.
+func (c *cameraControls) justPressed(w *glfw.Window, key glfw.Key) bool {
+	down := w.GetKey(key) == glfw.Press
+	pressed := down && !c.keysDown[key]
+	c.keysDown[key] = down
+	return pressed
+}
+
+func (c *cameraControls) processInput(w *glfw.Window, deltaTime float64) {
+	c.processPathInput(w)
+
+	if c.justPressed(w, glfw.KeyB) {
+		c.culler.Disabled = !c.culler.Disabled
+		if c.culler.Disabled {
+			fmt.Println("camera: culling off")
+		} else {
+			fmt.Println("camera: culling on")
+		}
+	}
+
+	if c.path != nil {
+		c.pathTime += deltaTime
+	}
+
+	if c.playing {
+		if c.orbiting {
+			c.setOrbiting(w, false)
+		}
+		c.camera.Follow(c.path.Sample(c.pathTime))
+		return
 	}
 
-	if w.GetKey(glfw.KeyL) == glfw.Press {
-		// Enables wireframe drawing
-		gl.PolygonMode(gl.FRONT_AND_BACK, gl.LINE)
+	if c.orbit != nil && c.justPressed(w, glfw.KeyO) {
+		c.setOrbiting(w, !c.orbiting)
 	}
 
-	if w.GetKey(glfw.KeyF) == glfw.Press {
-		// Disables wireframe drawing
-		gl.PolygonMode(gl.FRONT_AND_BACK, gl.FILL)
+	if c.orbiting {
+		processOrbitKeyboardInput(w, c.orbit, deltaTime)
+		return
 	}
 
-	if w.GetKey(glfw.KeyLeftControl) == glfw.Press && w.GetKey(glfw.KeyP) == glfw.Press {
-		// Takes a screen shot
-		sshoter := sshot.NewScreenShoter(scene.Name(), scene.Width(), scene.Height())
-		sshoter.TakeOne()
+	if c.justPressed(w, glfw.KeyV) {
+		if c.camera.OrientationMode == camera.Euler {
+			c.camera.SetOrientationMode(camera.Quaternion)
+			fmt.Println("camera: free flight, Z and X roll")
+		} else {
+			c.camera.SetOrientationMode(camera.Euler)
+			fmt.Println("camera: yaw and pitch")
+		}
 	}
+
+	processCameraKeyboardInput(w, c.camera, deltaTime)
+}
+
+// processPathInput records, saves and plays the camera path.
+func (c *cameraControls) processPathInput(w *glfw.Window) {
+	if c.justPressed(w, glfw.KeyK) && !c.playing {
+		if c.path == nil {
+			// The time of the keyframes starts at the first one
+			c.path = &camera.Path{}
+			c.pathTime = 0
+		}
+		c.path.Record(c.camera, c.pathTime)
+		fmt.Printf("camera: keyframe %d at %.2fs\n", len(c.path.Keyframes)-1, c.pathTime)
+	}
+
+	if c.justPressed(w, glfw.KeyJ) && c.path != nil {
+		if err := c.path.Save(cameraPathFile); err != nil {
+			fmt.Println(err.Error())
+		} else {
+			fmt.Printf("camera: path saved to %s\n", cameraPathFile)
+		}
+	}
+
+	if c.justPressed(w, glfw.KeyN) && c.path != nil {
+		c.playing = !c.playing
+		if c.playing {
+			c.pathTime = 0
+			fmt.Println("camera: playing the path")
+		} else {
+			// Recording goes on after the last keyframe
+			c.pathTime = c.path.Keyframes[len(c.path.Keyframes)-1].Time
+			fmt.Println("camera: path stopped")
+		}
+	}
+}
+
+func (c *cameraControls) mouseCallback(w *glfw.Window, xpos, ypos float64) {
+	if c.firstMouse {
+		c.lastX = xpos
+		c.lastY = ypos
+		c.firstMouse = false
+	}
+
+	xoffset := xpos - c.lastX
+	yoffset := c.lastY - ypos
+	c.lastX = xpos
+	c.lastY = ypos
+
+	if c.orbiting {
+		if w.GetMouseButton(glfw.MouseButtonLeft) == glfw.Press {
+			c.orbit.Rotate(xoffset, yoffset)
+		}
+		if w.GetMouseButton(glfw.MouseButtonRight) == glfw.Press {
+			c.orbit.Pan(xoffset, yoffset)
+		}
+		return
+	}
+
+	c.camera.ProcessMouseMovement(xoffset, yoffset, true)
+}
+
+func (c *cameraControls) mouseScrollCallback(w *glfw.Window, xoff, yoff float64) {
+	if c.orbiting {
+		c.orbit.Zoom(yoff)
+		return
+	}
+	c.camera.ProcessMouseScroll(yoff)
 }
 
 func processCameraKeyboardInput(w *glfw.Window, c *camera.Camera, deltaTime float64) {
@@ -79,8 +292,93 @@ func processCameraKeyboardInput(w *glfw.Window, c *camera.Camera, deltaTime floa
 	if w.GetKey(glfw.KeyDown) == glfw.Press {
 		c.ProcessMouseMovement(0.0, -rotate, true)
 	}
+
+	// Only the quaternion mode rolls
+	const roll = 90.0
+	if w.GetKey(glfw.KeyZ) == glfw.Press {
+		c.Roll(-roll * deltaTime)
+	}
+
+	if w.GetKey(glfw.KeyX) == glfw.Press {
+		c.Roll(roll * deltaTime)
+	}
 }
 
-func frameBufferSizeCallback(w *glfw.Window, width, height int) {
-	gl.Viewport(0, 0, int32(width), int32(height))
+// processOrbitKeyboardInput rotates the orbit camera with the arrows and zooms with W
+// and S.
+func processOrbitKeyboardInput(w *glfw.Window, o *camera.Orbit, deltaTime float64) {
+	const rotate = 300.0
+	if w.GetKey(glfw.KeyLeft) == glfw.Press {
+		o.Rotate(-rotate*deltaTime, 0.0)
+	}
+
+	if w.GetKey(glfw.KeyRight) == glfw.Press {
+		o.Rotate(rotate*deltaTime, 0.0)
+	}
+
+	if w.GetKey(glfw.KeyUp) == glfw.Press {
+		o.Rotate(0.0, rotate*deltaTime)
+	}
+
+	if w.GetKey(glfw.KeyDown) == glfw.Press {
+		o.Rotate(0.0, -rotate*deltaTime)
+	}
+
+	const zoom = 10.0
+	if w.GetKey(glfw.KeyW) == glfw.Press {
+		o.Zoom(zoom * deltaTime)
+	}
+
+	if w.GetKey(glfw.KeyS) == glfw.Press {
+		o.Zoom(-zoom * deltaTime)
+	}
+}
+
+// cubeRadius is the radius of the sphere around a unit cube, turned any way.
+const cubeRadius = 0.87
+
+// drawCubes draws the 36 vertices of vao at each position, rotated a bit more than the
+// previous one, with sh, which must be in use. It is shared by the lighting scenes so
+// their shadow passes draw the same cubes. The cubes out of the frustum of culler are
+// skipped, unless it is nil, e.g. for the shadow passes that see what the camera does
+// not.
+func drawCubes(sh *shader.Shader, vao uint32, positions []mgl32.Vec3, culler *bounds.Culler) {
+	gl.BindVertexArray(vao)
+	for i, pos := range positions {
+		if culler != nil && !culler.VisibleSphere(bounds.Sphere{Center: pos, Radius: cubeRadius}) {
+			continue
+		}
+
+		modelMatrix := mgl32.Ident4()
+
+		translate := mgl32.Translate3D(pos.X(), pos.Y(), pos.Z())
+		angle := mgl32.DegToRad(20.0 * float32(i))
+		rotateX := mgl32.HomogRotate3D(angle, mgl32.Vec3{1.0, 0.0, 0.0})
+		rotateY := mgl32.HomogRotate3D(angle, mgl32.Vec3{0.0, 1.0, 0.0})
+		rotateZ := mgl32.HomogRotate3D(angle, mgl32.Vec3{0.0, 0.0, 1.0})
+
+		modelMatrix = modelMatrix.Mul4(translate)
+		modelMatrix = modelMatrix.Mul4(rotateX)
+		modelMatrix = modelMatrix.Mul4(rotateY)
+		modelMatrix = modelMatrix.Mul4(rotateZ)
+
+		sh.SetMat4("model", modelMatrix)
+		gl.DrawArrays(gl.TRIANGLES, 0, 36)
+	}
+}
+
+// cubesBounds returns a sphere around the unit cubes at positions.
+func cubesBounds(positions []mgl32.Vec3) (mgl32.Vec3, float32) {
+	var center mgl32.Vec3
+	for _, pos := range positions {
+		center = center.Add(pos)
+	}
+	center = center.Mul(1 / float32(len(positions)))
+
+	var radius float32
+	for _, pos := range positions {
+		radius = max(radius, pos.Sub(center).Len())
+	}
+
+	return center, radius + cubeRadius
 }
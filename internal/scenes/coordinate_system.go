@@ -1,17 +1,26 @@
 package scenes
 
 import (
-	"fmt"
-	"log"
-
 	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/glfw/v3.3/glfw"
 	"github.com/go-gl/mathgl/mgl32"
+	"github.com/igoramorim/gopengl/internal/assets"
 	"github.com/igoramorim/gopengl/pkg/shader"
 	"github.com/igoramorim/gopengl/pkg/texture"
+	"github.com/igoramorim/gopengl/pkg/vertex"
 )
 
-type CoordinateSystem struct{}
+type CoordinateSystem struct {
+	shader           *shader.Shader
+	vao              uint32
+	vbo              uint32
+	ebo              uint32
+	texture0         *texture.Texture
+	texture1         *texture.Texture
+	modelMatrix      mgl32.Mat4
+	viewMatrix       mgl32.Mat4
+	projectionMatrix mgl32.Mat4
+}
 
 func (s CoordinateSystem) Name() string {
 	return "coordinate_system"
@@ -25,36 +34,11 @@ func (s CoordinateSystem) Height() int {
 	return height
 }
 
-func (s CoordinateSystem) Show() {
-	if err := glfw.Init(); err != nil {
-		log.Fatalln("initialize glfw:", err)
-	}
-	defer glfw.Terminate()
-
-	glfw.WindowHint(glfw.Resizable, glfw.True)
-	glfw.WindowHint(glfw.ContextVersionMajor, 4)
-	glfw.WindowHint(glfw.ContextVersionMinor, 1)
-	glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
-	glfw.WindowHint(glfw.OpenGLForwardCompatible, glfw.True)
-
-	window, err := glfw.CreateWindow(s.Width(), s.Height(), s.Name(), nil, nil)
+func (s *CoordinateSystem) Init(w *glfw.Window) error {
+	var err error
+	s.shader, err = shader.NewFS(assets.FS, "shaders/coordinate-system.vert", "shaders/coordinate-system.frag")
 	if err != nil {
-		panic(err)
-	}
-	window.MakeContextCurrent()
-
-	window.SetFramebufferSizeCallback(frameBufferSizeCallback)
-
-	if err := gl.Init(); err != nil {
-		panic(err)
-	}
-
-	version := gl.GoStr(gl.GetString(gl.VERSION))
-	fmt.Println("OpenGL version:", version)
-
-	shader, err := shader.New("internal/assets/shaders/coordinate-system.vert", "internal/assets/shaders/coordinate-system.frag")
-	if err != nil {
-		panic(err)
+		return err
 	}
 
 	var vertices = []float32{
@@ -70,95 +54,87 @@ func (s CoordinateSystem) Show() {
 		1, 2, 3, // second triangle
 	}
 
-	var vao uint32
-	gl.GenVertexArrays(1, &vao)
-	gl.BindVertexArray(vao)
+	gl.GenVertexArrays(1, &s.vao)
+	gl.BindVertexArray(s.vao)
 
-	var vbo uint32
-	gl.GenBuffers(1, &vbo)
-	gl.BindBuffer(gl.ARRAY_BUFFER, vbo)
+	gl.GenBuffers(1, &s.vbo)
+	gl.BindBuffer(gl.ARRAY_BUFFER, s.vbo)
 	gl.BufferData(gl.ARRAY_BUFFER, len(vertices)*floatSize, gl.Ptr(vertices), gl.STATIC_DRAW)
 
 	// Element Buffer Object
-	var ebo uint32
-	gl.GenBuffers(1, &ebo)
-	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, ebo)
+	gl.GenBuffers(1, &s.ebo)
+	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, s.ebo)
 	gl.BufferData(gl.ELEMENT_ARRAY_BUFFER, len(indices)*uint32Size, gl.Ptr(indices), gl.STATIC_DRAW)
 
-	// Position attribute
-	gl.VertexAttribPointer(0, 3, gl.FLOAT, false, 5*floatSize, nil)
-	gl.EnableVertexAttribArray(0)
-
-	// Texture Coord attribute
-	gl.VertexAttribPointerWithOffset(1, 2, gl.FLOAT, false, 5*floatSize, 3*floatSize)
-	gl.EnableVertexAttribArray(1)
+	// Position and texture coord attributes
+	vertex.Floats(3, 2).Apply()
 
-	texture0, err := texture.New("internal/assets/textures/container.jpg", gl.TEXTURE_2D, gl.TEXTURE0, gl.RGBA, gl.RGBA, gl.UNSIGNED_INT)
+	s.texture0, err = texture.NewFS(assets.FS, "textures/container.jpg", gl.TEXTURE0, texture.DefaultOptions)
 	if err != nil {
-		panic(err)
+		return err
 	}
 
-	texture1, err := texture.New("internal/assets/textures/awesomeface.png", gl.TEXTURE_2D, gl.TEXTURE1, gl.RGBA, gl.RGBA, gl.UNSIGNED_INT)
+	s.texture1, err = texture.NewFS(assets.FS, "textures/awesomeface.png", gl.TEXTURE1, texture.DefaultOptions)
 	if err != nil {
-		panic(err)
+		return err
 	}
 
-	// Clean up all resources
-	defer func() {
-		gl.DeleteVertexArrays(1, &vao)
-		gl.DeleteBuffers(1, &vbo)
-		gl.DeleteBuffers(1, &ebo)
-		shader.Delete()
-		texture0.Delete()
-		texture1.Delete()
-	}()
+	s.shader.Use()
 
-	shader.Use()
+	s.texture0.ActiveAndBind()
+	s.shader.SetInt("texture0", 0)
 
-	texture0.ActiveAndBind()
-	shader.SetInt("texture0", 0)
-
-	texture1.ActiveAndBind()
-	shader.SetInt("texture1", 1)
+	s.texture1.ActiveAndBind()
+	s.shader.SetInt("texture1", 1)
 
 	// Transformations to make it 3D
 
 	// Model matrix. Applies transformations to the object's vertices
-	modelMatrix := mgl32.Ident4()
+	s.modelMatrix = mgl32.Ident4()
 	rotate := mgl32.HomogRotate3D(mgl32.DegToRad(-55.0), mgl32.Vec3{1.0, 0.0, 0.0})
-	modelMatrix = modelMatrix.Mul4(rotate)
+	s.modelMatrix = s.modelMatrix.Mul4(rotate)
 
 	// View matrix. Applies transformations to the 'eye' but actually we move the entire scene
 	// so if you want to move the backwards, you move the entire scene forward
-	viewMatrix := mgl32.Ident4()
+	s.viewMatrix = mgl32.Ident4()
 	translate := mgl32.Translate3D(0.0, 0.0, -2.0)
-	viewMatrix = viewMatrix.Mul4(translate)
+	s.viewMatrix = s.viewMatrix.Mul4(translate)
 
 	// Projection matrix
-	projectionMatrix := mgl32.Ident4()
+	s.projectionMatrix = mgl32.Ident4()
 	perspective := mgl32.Perspective(
 		mgl32.DegToRad(45.0),           // Field of view (FOV)
 		float32(width)/float32(height), // Aspect ratio
 		0.1,                            // Near plane. Vertices between the near plane and the 'eye' won't be rendered
 		100.0,                          // Far plane. Vertices after it won't be rendered
 	)
-	projectionMatrix = projectionMatrix.Mul4(perspective)
+	s.projectionMatrix = s.projectionMatrix.Mul4(perspective)
 
-	// Main loop
-	for !window.ShouldClose() {
-		processInput(window, s)
+	return nil
+}
 
-		gl.ClearColor(0.2, 0.3, 0.3, 1.0)
-		gl.Clear(gl.COLOR_BUFFER_BIT)
+func (s *CoordinateSystem) Update(w *glfw.Window, deltaTime float64) {}
 
-		shader.SetMat4("model", modelMatrix)
-		shader.SetMat4("view", viewMatrix)
-		shader.SetMat4("projection", projectionMatrix)
+func (s *CoordinateSystem) Render(time float64) {
+	gl.ClearColor(0.2, 0.3, 0.3, 1.0)
+	gl.Clear(gl.COLOR_BUFFER_BIT)
 
-		gl.BindVertexArray(vao)
-		gl.DrawElements(gl.TRIANGLES, 6, gl.UNSIGNED_INT, nil)
+	s.shader.SetMat4("model", s.modelMatrix)
+	s.shader.SetMat4("view", s.viewMatrix)
+	s.shader.SetMat4("projection", s.projectionMatrix)
 
-		window.SwapBuffers()
-		glfw.PollEvents()
-	}
+	gl.BindVertexArray(s.vao)
+	gl.DrawElements(gl.TRIANGLES, 6, gl.UNSIGNED_INT, nil)
+}
+
+func (s *CoordinateSystem) Resize(width, height int) {}
+
+// Destroy cleans up all resources
+func (s *CoordinateSystem) Destroy() {
+	gl.DeleteVertexArrays(1, &s.vao)
+	gl.DeleteBuffers(1, &s.vbo)
+	gl.DeleteBuffers(1, &s.ebo)
+	s.shader.Delete()
+	s.texture0.Delete()
+	s.texture1.Delete()
 }
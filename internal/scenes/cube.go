@@ -1,17 +1,22 @@
 package scenes
 
 import (
-	"fmt"
-	"log"
-
 	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/glfw/v3.3/glfw"
 	"github.com/go-gl/mathgl/mgl32"
+	"github.com/igoramorim/gopengl/internal/assets"
 	"github.com/igoramorim/gopengl/pkg/shader"
 	"github.com/igoramorim/gopengl/pkg/texture"
+	"github.com/igoramorim/gopengl/pkg/vertex"
 )
 
-type Cube struct{}
+type Cube struct {
+	shader   *shader.Shader
+	vao      uint32
+	vbo      uint32
+	texture0 *texture.Texture
+	texture1 *texture.Texture
+}
 
 func (s Cube) Name() string {
 	return "cube"
@@ -25,36 +30,11 @@ func (s Cube) Height() int {
 	return height
 }
 
-func (s Cube) Show() {
-	if err := glfw.Init(); err != nil {
-		log.Fatalln("initialize glfw:", err)
-	}
-	defer glfw.Terminate()
-
-	glfw.WindowHint(glfw.Resizable, glfw.True)
-	glfw.WindowHint(glfw.ContextVersionMajor, 4)
-	glfw.WindowHint(glfw.ContextVersionMinor, 1)
-	glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
-	glfw.WindowHint(glfw.OpenGLForwardCompatible, glfw.True)
-
-	window, err := glfw.CreateWindow(s.Width(), s.Height(), s.Name(), nil, nil)
+func (s *Cube) Init(w *glfw.Window) error {
+	var err error
+	s.shader, err = shader.NewFS(assets.FS, "shaders/coordinate-system.vert", "shaders/coordinate-system.frag")
 	if err != nil {
-		panic(err)
-	}
-	window.MakeContextCurrent()
-
-	window.SetFramebufferSizeCallback(frameBufferSizeCallback)
-
-	if err := gl.Init(); err != nil {
-		panic(err)
-	}
-
-	version := gl.GoStr(gl.GetString(gl.VERSION))
-	fmt.Println("OpenGL version:", version)
-
-	shader, err := shader.New("internal/assets/shaders/coordinate-system.vert", "internal/assets/shaders/coordinate-system.frag")
-	if err != nil {
-		panic(err)
+		return err
 	}
 
 	var vertices = []float32{
@@ -101,50 +81,33 @@ func (s Cube) Show() {
 		-0.5, 0.5, 0.5, 0.0, 0.0,
 		-0.5, 0.5, -0.5, 0.0, 1.0,
 	}
+	gl.GenVertexArrays(1, &s.vao)
+	gl.BindVertexArray(s.vao)
 
-	var vao uint32
-	gl.GenVertexArrays(1, &vao)
-	gl.BindVertexArray(vao)
-
-	var vbo uint32
-	gl.GenBuffers(1, &vbo)
-	gl.BindBuffer(gl.ARRAY_BUFFER, vbo)
+	gl.GenBuffers(1, &s.vbo)
+	gl.BindBuffer(gl.ARRAY_BUFFER, s.vbo)
 	gl.BufferData(gl.ARRAY_BUFFER, len(vertices)*floatSize, gl.Ptr(vertices), gl.STATIC_DRAW)
 
-	// Position attribute
-	gl.VertexAttribPointer(0, 3, gl.FLOAT, false, 5*floatSize, nil)
-	gl.EnableVertexAttribArray(0)
+	// Position and texture coord attributes
+	vertex.Floats(3, 2).Apply()
 
-	// Texture Coord attribute
-	gl.VertexAttribPointerWithOffset(1, 2, gl.FLOAT, false, 5*floatSize, 3*floatSize)
-	gl.EnableVertexAttribArray(1)
-
-	texture0, err := texture.New("internal/assets/textures/container.jpg", gl.TEXTURE_2D, gl.TEXTURE0, gl.RGBA, gl.RGBA, gl.UNSIGNED_INT)
+	s.texture0, err = texture.NewFS(assets.FS, "textures/container.jpg", gl.TEXTURE0, texture.DefaultOptions)
 	if err != nil {
-		panic(err)
+		return err
 	}
 
-	texture1, err := texture.New("internal/assets/textures/awesomeface.png", gl.TEXTURE_2D, gl.TEXTURE1, gl.RGBA, gl.RGBA, gl.UNSIGNED_INT)
+	s.texture1, err = texture.NewFS(assets.FS, "textures/awesomeface.png", gl.TEXTURE1, texture.DefaultOptions)
 	if err != nil {
-		panic(err)
+		return err
 	}
 
-	// Clean up all resources
-	defer func() {
-		gl.DeleteVertexArrays(1, &vao)
-		gl.DeleteBuffers(1, &vbo)
-		shader.Delete()
-		texture0.Delete()
-		texture1.Delete()
-	}()
-
-	shader.Use()
+	s.shader.Use()
 
-	texture0.ActiveAndBind()
-	shader.SetInt("texture0", 0)
+	s.texture0.ActiveAndBind()
+	s.shader.SetInt("texture0", 0)
 
-	texture1.ActiveAndBind()
-	shader.SetInt("texture1", 1)
+	s.texture1.ActiveAndBind()
+	s.shader.SetInt("texture1", 1)
 
 	// OpenGL stores all depth information in the z-buffer (depth buffer). The depth is stored within each fragment.
 	// When the fragment wants to output a color, OpenGL compares its depth value with the z-buffer.
@@ -156,42 +119,49 @@ func (s Cube) Show() {
 	// guarantee the order the triangles are rendered, some triangles are drawn on top of each other.
 	gl.Enable(gl.DEPTH_TEST)
 
-	// Main loop
-	for !window.ShouldClose() {
-		processInput(window, s)
+	return nil
+}
+
+func (s *Cube) Update(w *glfw.Window, deltaTime float64) {}
 
-		gl.ClearColor(0.2, 0.3, 0.3, 1.0)
-		gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+func (s *Cube) Render(time float64) {
+	gl.ClearColor(0.2, 0.3, 0.3, 1.0)
+	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
 
-		time := glfw.GetTime()
+	// Transformations to make it 3D
 
-		// Transformations to make it 3D
+	modelMatrix := mgl32.Ident4()
+	angle := float32(time) * mgl32.DegToRad(45.0)
+	rotateX := mgl32.HomogRotate3D(angle, mgl32.Vec3{1.0, 0.0, 0.0})
+	rotateY := mgl32.HomogRotate3D(angle, mgl32.Vec3{0.0, 1.0, 0.0})
+	rotateZ := mgl32.HomogRotate3D(angle, mgl32.Vec3{0.0, 0.0, 1.0})
+	modelMatrix = modelMatrix.Mul4(rotateX)
+	modelMatrix = modelMatrix.Mul4(rotateY)
+	modelMatrix = modelMatrix.Mul4(rotateZ)
 
-		modelMatrix := mgl32.Ident4()
-		angle := float32(time) * mgl32.DegToRad(45.0)
-		rotateX := mgl32.HomogRotate3D(angle, mgl32.Vec3{1.0, 0.0, 0.0})
-		rotateY := mgl32.HomogRotate3D(angle, mgl32.Vec3{0.0, 1.0, 0.0})
-		rotateZ := mgl32.HomogRotate3D(angle, mgl32.Vec3{0.0, 0.0, 1.0})
-		modelMatrix = modelMatrix.Mul4(rotateX)
-		modelMatrix = modelMatrix.Mul4(rotateY)
-		modelMatrix = modelMatrix.Mul4(rotateZ)
+	viewMatrix := mgl32.Ident4()
+	translate := mgl32.Translate3D(0.0, 0.0, -4.0)
+	viewMatrix = viewMatrix.Mul4(translate)
 
-		viewMatrix := mgl32.Ident4()
-		translate := mgl32.Translate3D(0.0, 0.0, -4.0)
-		viewMatrix = viewMatrix.Mul4(translate)
+	projectionMatrix := mgl32.Ident4()
+	perspective := mgl32.Perspective(mgl32.DegToRad(45.0), float32(width)/float32(height), 0.1, 100.0)
+	projectionMatrix = projectionMatrix.Mul4(perspective)
 
-		projectionMatrix := mgl32.Ident4()
-		perspective := mgl32.Perspective(mgl32.DegToRad(45.0), width/height, 0.1, 100.0)
-		projectionMatrix = projectionMatrix.Mul4(perspective)
+	s.shader.SetMat4("model", modelMatrix)
+	s.shader.SetMat4("view", viewMatrix)
+	s.shader.SetMat4("projection", projectionMatrix)
 
-		shader.SetMat4("model", modelMatrix)
-		shader.SetMat4("view", viewMatrix)
-		shader.SetMat4("projection", projectionMatrix)
+	gl.BindVertexArray(s.vao)
+	gl.DrawArrays(gl.TRIANGLES, 0, 36) // 36 vertices to make a cube
+}
 
-		gl.BindVertexArray(vao)
-		gl.DrawArrays(gl.TRIANGLES, 0, 36) // 36 vertices to make a cube
+func (s *Cube) Resize(width, height int) {}
 
-		window.SwapBuffers()
-		glfw.PollEvents()
-	}
+// Destroy cleans up all resources
+func (s *Cube) Destroy() {
+	gl.DeleteVertexArrays(1, &s.vao)
+	gl.DeleteBuffers(1, &s.vbo)
+	s.shader.Delete()
+	s.texture0.Delete()
+	s.texture1.Delete()
 }
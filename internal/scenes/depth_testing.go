@@ -1,35 +1,24 @@
 package scenes
 
 import (
-	"fmt"
-	"log"
-
 	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/glfw/v3.3/glfw"
 	"github.com/go-gl/mathgl/mgl32"
-	"github.com/igoramorim/gopengl/pkg/camera"
+	"github.com/igoramorim/gopengl/internal/assets"
 	"github.com/igoramorim/gopengl/pkg/model"
 	"github.com/igoramorim/gopengl/pkg/shader"
 )
 
-func NewDepthTesting() DepthTesting {
-	return DepthTesting{
-		camera:     camera.New(),
-		firstMouse: true,
-		lastX:      float64(width) / 2,
-		lastY:      float64(height) / 2,
-		deltaTime:  0.0,
-		lastFrame:  0.0,
+func NewDepthTesting() *DepthTesting {
+	return &DepthTesting{
+		cameraControls: newCameraControls(),
 	}
 }
 
 type DepthTesting struct {
-	camera     *camera.Camera
-	firstMouse bool
-	lastX      float64
-	lastY      float64
-	deltaTime  float64 // Time between current frame and last frame
-	lastFrame  float64
+	cameraControls
+	shader  *shader.Shader
+	model3D *model.Model
 }
 
 func (s DepthTesting) Name() string {
@@ -44,39 +33,13 @@ func (s DepthTesting) Height() int {
 	return height
 }
 
-func (s DepthTesting) Show() {
-	if err := glfw.Init(); err != nil {
-		log.Fatalln("initialize glfw:", err)
-	}
-	defer glfw.Terminate()
-
-	glfw.WindowHint(glfw.Resizable, glfw.True)
-	glfw.WindowHint(glfw.ContextVersionMajor, 4)
-	glfw.WindowHint(glfw.ContextVersionMinor, 1)
-	glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
-	glfw.WindowHint(glfw.OpenGLForwardCompatible, glfw.True)
+func (s *DepthTesting) Init(w *glfw.Window) error {
+	s.attach(w)
 
-	window, err := glfw.CreateWindow(s.Width(), s.Height(), s.Name(), nil, nil)
+	var err error
+	s.shader, err = shader.NewFS(assets.FS, "shaders/depth_testing.vert", "shaders/depth_testing.frag")
 	if err != nil {
-		panic(err)
-	}
-	window.MakeContextCurrent()
-
-	window.SetFramebufferSizeCallback(frameBufferSizeCallback)
-	window.SetCursorPosCallback(s.mouseCallback)
-	window.SetScrollCallback(s.mouseScrollCallback)
-	window.SetInputMode(glfw.CursorMode, glfw.CursorDisabled)
-
-	if err := gl.Init(); err != nil {
-		panic(err)
-	}
-
-	version := gl.GoStr(gl.GetString(gl.VERSION))
-	fmt.Println("OpenGL version:", version)
-
-	shader, err := shader.New("internal/assets/shaders/depth_testing.vert", "internal/assets/shaders/depth_testing.frag")
-	if err != nil {
-		panic(err)
+		return err
 	}
 
 	// var cubeVertices = []float32{
@@ -165,110 +128,85 @@ func (s DepthTesting) Show() {
 	// gl.BindVertexArray(0)
 
 	// Textures
-	// cubeTexture, err := texture.New("internal/assets/textures/marble.jpg", gl.TEXTURE_2D, gl.TEXTURE0, gl.RGBA, gl.RGBA, gl.UNSIGNED_INT)
+	// cubeTexture, err := texture.NewFS(assets.FS, "textures/marble.jpg", gl.TEXTURE0, texture.DefaultOptions)
 	// if err != nil {
-	// 	panic(err)
+	// 	return err
 	// }
 
-	// floorTexture, err := texture.New("internal/assets/textures/metal.png", gl.TEXTURE_2D, gl.TEXTURE1, gl.RGBA, gl.RGBA, gl.UNSIGNED_INT)
+	// floorTexture, err := texture.NewFS(assets.FS, "textures/metal.png", gl.TEXTURE1, texture.DefaultOptions)
 	// if err != nil {
-	// 	panic(err)
+	// 	return err
 	// }
 
-	model3D, err := model.New("internal/assets/models/sponza/sponza.obj")
+	s.model3D, err = model.NewFS(assets.FS, "models/sponza/sponza.obj")
 	if err != nil {
-		panic(err)
+		return err
 	}
 
-	// Clean up all resources
-	defer func() {
-		shader.Delete()
-		// cubeTexture.Delete()
-		// floorTexture.Delete()
-	}()
-
 	gl.Enable(gl.DEPTH_TEST)
 	gl.DepthFunc(gl.LESS) // gl.ALWAYS
 
-	// Main loop
-	for !window.ShouldClose() {
-		s.processInput(window)
-
-		gl.ClearColor(0.1, 0.1, 0.1, 1.0)
-		gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
-
-		currentFrame := glfw.GetTime()
-		s.deltaTime = currentFrame - s.lastFrame
-		s.lastFrame = currentFrame
+	return nil
+}
 
-		shader.Use()
+func (s *DepthTesting) Update(w *glfw.Window, deltaTime float64) {
+	s.processInput(w, deltaTime)
+}
 
-		viewMatrix := s.camera.ViewMatrix()
-		projectionMatrix := mgl32.Ident4()
-		projectionMatrix = mgl32.Perspective(mgl32.DegToRad(float32(s.camera.Fov)), width/height, 0.1, 100.0)
-		shader.SetMat4("view", viewMatrix)
-		shader.SetMat4("projection", projectionMatrix)
+func (s *DepthTesting) Render(time float64) {
+	gl.ClearColor(0.1, 0.1, 0.1, 1.0)
+	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
 
-		// // Cube 1
-		// cubeTexture.ActiveAndBind()
-		// shader.SetInt("texture0", 0)
-		// gl.BindVertexArray(cubeVAO)
-		// modelMatrix := mgl32.Ident4()
-		// translate := mgl32.Translate3D(-1.0, 0.0, -1.0)
-		// modelMatrix = modelMatrix.Mul4(translate)
-		// shader.SetMat4("model", modelMatrix)
-		// gl.DrawArrays(gl.TRIANGLES, 0, 36)
-		//
-		// // Cube 2
-		// // gl.BindVertexArray(cubeVAO)
-		// modelMatrix := mgl32.Ident4()
-		// translate = mgl32.Translate3D(2.0, 0.0, 0.0)
-		// modelMatrix = modelMatrix.Mul4(translate)
-		// shader.SetMat4("model", modelMatrix)
-		// gl.DrawArrays(gl.TRIANGLES, 0, 36)
-		//
-		// // Floor
-		// floorTexture.ActiveAndBind()
-		// shader.SetInt("texture0", 1)
-		// gl.BindVertexArray(planeVAO)
-		// shader.SetMat4("model", mgl32.Ident4())
-		// gl.DrawArrays(gl.TRIANGLES, 0, 6)
-		// gl.BindVertexArray(0)
+	s.shader.Use()
 
-		modelMatrix := mgl32.Ident4()
-		translate := mgl32.Translate3D(0, 0, 0)
-		modelMatrix = modelMatrix.Mul4(translate)
-		// Sponza model is large. Scale it down so we can navigate better
-		scale := mgl32.Scale3D(0.01, 0.01, 0.01)
-		modelMatrix = modelMatrix.Mul4(scale)
-		shader.SetMat4("model", modelMatrix)
-		model3D.Draw(shader)
+	viewMatrix := s.camera.ViewMatrix()
+	projectionMatrix := s.camera.ProjectionMatrix()
+	s.shader.SetMat4("view", viewMatrix)
+	s.shader.SetMat4("projection", projectionMatrix)
 
-		window.SwapBuffers()
-		glfw.PollEvents()
-	}
-}
+	// // Cube 1
+	// cubeTexture.ActiveAndBind()
+	// s.shader.SetInt("texture0", 0)
+	// gl.BindVertexArray(cubeVAO)
+	// modelMatrix := mgl32.Ident4()
+	// translate := mgl32.Translate3D(-1.0, 0.0, -1.0)
+	// modelMatrix = modelMatrix.Mul4(translate)
+	// s.shader.SetMat4("model", modelMatrix)
+	// gl.DrawArrays(gl.TRIANGLES, 0, 36)
+	//
+	// // Cube 2
+	// // gl.BindVertexArray(cubeVAO)
+	// modelMatrix := mgl32.Ident4()
+	// translate = mgl32.Translate3D(2.0, 0.0, 0.0)
+	// modelMatrix = modelMatrix.Mul4(translate)
+	// s.shader.SetMat4("model", modelMatrix)
+	// gl.DrawArrays(gl.TRIANGLES, 0, 36)
+	//
+	// // Floor
+	// floorTexture.ActiveAndBind()
+	// s.shader.SetInt("texture0", 1)
+	// gl.BindVertexArray(planeVAO)
+	// s.shader.SetMat4("model", mgl32.Ident4())
+	// gl.DrawArrays(gl.TRIANGLES, 0, 6)
+	// gl.BindVertexArray(0)
 
-func (s *DepthTesting) processInput(w *glfw.Window) {
-	processInput(w, s)
-	processCameraKeyboardInput(w, s.camera, s.deltaTime)
+	modelMatrix := mgl32.Ident4()
+	translate := mgl32.Translate3D(0, 0, 0)
+	modelMatrix = modelMatrix.Mul4(translate)
+	// Sponza model is large. Scale it down so we can navigate better
+	scale := mgl32.Scale3D(0.01, 0.01, 0.01)
+	modelMatrix = modelMatrix.Mul4(scale)
+	s.shader.SetMat4("model", modelMatrix)
+	s.model3D.Draw(s.shader)
 }
 
-func (s *DepthTesting) mouseCallback(w *glfw.Window, xpos, ypos float64) {
-	if s.firstMouse {
-		s.lastX = xpos
-		s.lastY = ypos
-		s.firstMouse = false
-	}
-
-	xoffset := xpos - s.lastX
-	yoffset := s.lastY - ypos
-	s.lastX = xpos
-	s.lastY = ypos
-
-	s.camera.ProcessMouseMovement(xoffset, yoffset, true)
+func (s *DepthTesting) Resize(width, height int) {
+	s.resize(width, height)
 }
 
-func (s *DepthTesting) mouseScrollCallback(w *glfw.Window, xoff, yoff float64) {
-	s.camera.ProcessMouseScroll(yoff)
+// Destroy cleans up all resources
+func (s *DepthTesting) Destroy() {
+	s.shader.Delete()
+	// cubeTexture.Delete()
+	// floorTexture.Delete()
 }
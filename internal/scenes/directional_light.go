@@ -1,35 +1,33 @@
 package scenes
 
 import (
-	"fmt"
-	"log"
-
 	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/glfw/v3.3/glfw"
 	"github.com/go-gl/mathgl/mgl32"
-	"github.com/igoramorim/gopengl/pkg/camera"
+	"github.com/igoramorim/gopengl/internal/assets"
+	"github.com/igoramorim/gopengl/pkg/light"
 	"github.com/igoramorim/gopengl/pkg/shader"
+	"github.com/igoramorim/gopengl/pkg/shadow"
 	"github.com/igoramorim/gopengl/pkg/texture"
+	"github.com/igoramorim/gopengl/pkg/vertex"
 )
 
-func NewDirectionalLight() DirectionalLight {
-	return DirectionalLight{
-		camera:     camera.New(),
-		firstMouse: true,
-		lastX:      float64(width) / 2,
-		lastY:      float64(height) / 2,
-		deltaTime:  0.0,
-		lastFrame:  0.0,
+func NewDirectionalLight() *DirectionalLight {
+	return &DirectionalLight{
+		cameraControls: newCameraControls(),
 	}
 }
 
 type DirectionalLight struct {
-	camera     *camera.Camera
-	firstMouse bool
-	lastX      float64
-	lastY      float64
-	deltaTime  float64 // Time between current frame and last frame
-	lastFrame  float64
+	cameraControls
+	lightingShader *shader.Shader
+	diffuseMapTex  *texture.Texture
+	specularMapTex *texture.Texture
+	cubeVAO        uint32
+	vbo            uint32
+	lightCubeVAO   uint32
+	cubePositions  []mgl32.Vec3
+	light          light.DirectionalLight
 }
 
 func (s DirectionalLight) Name() string {
@@ -44,39 +42,13 @@ func (s DirectionalLight) Height() int {
 	return height
 }
 
-func (s DirectionalLight) Show() {
-	if err := glfw.Init(); err != nil {
-		log.Fatalln("initialize glfw:", err)
-	}
-	defer glfw.Terminate()
-
-	glfw.WindowHint(glfw.Resizable, glfw.True)
-	glfw.WindowHint(glfw.ContextVersionMajor, 4)
-	glfw.WindowHint(glfw.ContextVersionMinor, 1)
-	glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
-	glfw.WindowHint(glfw.OpenGLForwardCompatible, glfw.True)
-
-	window, err := glfw.CreateWindow(s.Width(), s.Height(), s.Name(), nil, nil)
-	if err != nil {
-		panic(err)
-	}
-	window.MakeContextCurrent()
-
-	window.SetFramebufferSizeCallback(frameBufferSizeCallback)
-	window.SetCursorPosCallback(s.mouseCallback)
-	window.SetScrollCallback(s.mouseScrollCallback)
-	window.SetInputMode(glfw.CursorMode, glfw.CursorDisabled)
-
-	if err := gl.Init(); err != nil {
-		panic(err)
-	}
-
-	version := gl.GoStr(gl.GetString(gl.VERSION))
-	fmt.Println("OpenGL version:", version)
+func (s *DirectionalLight) Init(w *glfw.Window) error {
+	s.attach(w)
 
-	lightingShader, err := shader.New("internal/assets/shaders/directional_light.vert", "internal/assets/shaders/directional_light.frag")
+	var err error
+	s.lightingShader, err = shader.NewFS(assets.FS, "shaders/directional_light.vert", "shaders/directional_light.frag")
 	if err != nil {
-		panic(err)
+		return err
 	}
 
 	var vertices = []float32{
@@ -125,62 +97,42 @@ func (s DirectionalLight) Show() {
 	}
 
 	// First, configure the cubes's VAO and VBO
-	var cubeVAO uint32
-	gl.GenVertexArrays(1, &cubeVAO)
+	gl.GenVertexArrays(1, &s.cubeVAO)
 
-	var vbo uint32
-	gl.GenBuffers(1, &vbo)
-	gl.BindBuffer(gl.ARRAY_BUFFER, vbo)
+	gl.GenBuffers(1, &s.vbo)
+	gl.BindBuffer(gl.ARRAY_BUFFER, s.vbo)
 	gl.BufferData(gl.ARRAY_BUFFER, len(vertices)*floatSize, gl.Ptr(vertices), gl.STATIC_DRAW)
 
-	gl.BindVertexArray(cubeVAO)
+	gl.BindVertexArray(s.cubeVAO)
 
-	// Position attribute
-	gl.VertexAttribPointer(0, 3, gl.FLOAT, false, 8*floatSize, nil)
-	gl.EnableVertexAttribArray(0)
-	// Normal attribute
-	gl.VertexAttribPointerWithOffset(1, 3, gl.FLOAT, false, 8*floatSize, 3*floatSize)
-	gl.EnableVertexAttribArray(1)
-	// Texture Coord attribute
-	gl.VertexAttribPointerWithOffset(2, 2, gl.FLOAT, false, 8*floatSize, 6*floatSize)
-	gl.EnableVertexAttribArray(2)
+	// Position, normal and texture coord attributes
+	vertex.Floats(3, 3, 2).Apply()
 
 	// Second, configure the light's VAO
 	// (VBO stays the same. The vertices are the same for the light object wich is also a 3D cube)
-	var lightCubeVAO uint32
-	gl.GenVertexArrays(1, &lightCubeVAO)
-	gl.BindVertexArray(lightCubeVAO)
+	gl.GenVertexArrays(1, &s.lightCubeVAO)
+	gl.BindVertexArray(s.lightCubeVAO)
 
 	// We only need to bind to the VBO (to link it with glVertexAttribPointer), no need to fill it;
 	// The VBO's data already contains all we need (it's already bound, but we do it again for educational purposes)
-	gl.BindBuffer(gl.ARRAY_BUFFER, vbo)
+	gl.BindBuffer(gl.ARRAY_BUFFER, s.vbo)
 
 	// Position attribute
-	gl.VertexAttribPointer(0, 3, gl.FLOAT, false, 8*floatSize, nil)
-	gl.EnableVertexAttribArray(0)
+	vertex.Floats(3, 3, 2).Only(0).Apply()
 
-	diffuseMapTex, err := texture.New("internal/assets/textures/woodbox.png", gl.TEXTURE_2D, gl.TEXTURE0, gl.RGBA, gl.RGBA, gl.UNSIGNED_INT)
+	s.diffuseMapTex, err = texture.NewFS(assets.FS, "textures/woodbox.png", gl.TEXTURE0, texture.DefaultOptions)
 	if err != nil {
-		panic(err)
+		return err
 	}
 
-	specularMapTex, err := texture.New("internal/assets/textures/woodbox_specular.png", gl.TEXTURE_2D, gl.TEXTURE1, gl.RGBA, gl.RGBA, gl.UNSIGNED_INT)
+	s.specularMapTex, err = texture.NewFS(assets.FS, "textures/woodbox_specular.png", gl.TEXTURE1, texture.DefaultOptions)
 	if err != nil {
-		panic(err)
+		return err
 	}
 
-	// Clean up all resources
-	defer func() {
-		gl.DeleteVertexArrays(1, &cubeVAO)
-		gl.DeleteBuffers(1, &vbo)
-		lightingShader.Delete()
-		diffuseMapTex.Delete()
-		specularMapTex.Delete()
-	}()
-
 	gl.Enable(gl.DEPTH_TEST)
 
-	cubePositions := []mgl32.Vec3{
+	s.cubePositions = []mgl32.Vec3{
 		mgl32.Vec3{0.0, 0.0, 0.0},
 		mgl32.Vec3{2.0, 5.0, -15.0},
 		mgl32.Vec3{-1.5, -2.2, -2.5},
@@ -193,84 +145,64 @@ func (s DirectionalLight) Show() {
 		mgl32.Vec3{-1.3, 1.0, -1.5},
 	}
 
-	// Main loop
-	for !window.ShouldClose() {
-		s.processInput(window)
-
-		gl.ClearColor(0.1, 0.1, 0.1, 1.0)
-		gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
-
-		currentFrame := glfw.GetTime()
-		s.deltaTime = currentFrame - s.lastFrame
-		s.lastFrame = currentFrame
-
-		viewMatrix := s.camera.ViewMatrix()
-		projectionMatrix := mgl32.Ident4()
-		projectionMatrix = mgl32.Perspective(mgl32.DegToRad(float32(s.camera.Fov)), width/height, 0.1, 100.0)
+	s.light.Direction = mgl32.Vec3{-0.2, -1.0, -0.3}
+	s.light.Colors = light.NewColors(mgl32.Vec3{1.0, 1.0, 1.0}, 0.2, 0.5)
+	s.light.Shadow, err = shadow.NewMap(shadow.DefaultOptions)
+	if err != nil {
+		return err
+	}
 
-		diffuseMapTex.ActiveAndBind()
-		specularMapTex.ActiveAndBind()
+	return nil
+}
 
-		lightingShader.Use()
-		lightingShader.SetVec3f("light.direction", -0.2, -1.0, -0.3)
-		lightingShader.SetVec3("viewPos", s.camera.Position)
+func (s *DirectionalLight) Update(w *glfw.Window, deltaTime float64) {
+	s.processInput(w, deltaTime)
+}
 
-		lightingShader.SetVec3f("light.ambient", 0.2, 0.2, 0.2)
-		lightingShader.SetVec3f("light.diffuse", 0.5, 0.5, 0.5)
-		lightingShader.SetVec3f("light.specular", 1.0, 1.0, 1.0)
+func (s *DirectionalLight) Render(time float64) {
+	// First, render the depth of the cubes as seen from the light
+	center, radius := cubesBounds(s.cubePositions)
+	s.light.RenderShadow(center, radius, func(depth *shader.Shader) {
+		drawCubes(depth, s.cubeVAO, s.cubePositions, nil)
+	})
 
-		lightingShader.SetInt("material.diffuse", 0)
-		lightingShader.SetInt("material.specular", 1)
-		lightingShader.SetFloat("material.shininess", 32.0)
+	gl.ClearColor(0.1, 0.1, 0.1, 1.0)
+	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
 
-		lightingShader.SetMat4("view", viewMatrix)
-		lightingShader.SetMat4("projection", projectionMatrix)
+	viewMatrix := s.camera.ViewMatrix()
+	projectionMatrix := s.camera.ProjectionMatrix()
 
-		// Render the cubes
-		gl.BindVertexArray(cubeVAO)
-		for i, pos := range cubePositions {
-			modelMatrix := mgl32.Ident4()
+	s.diffuseMapTex.ActiveAndBind()
+	s.specularMapTex.ActiveAndBind()
 
-			translate := mgl32.Translate3D(pos.X(), pos.Y(), pos.Z())
-			angle := mgl32.DegToRad(20.0 * float32(i))
-			rotateX := mgl32.HomogRotate3D(angle, mgl32.Vec3{1.0, 0.0, 0.0})
-			rotateY := mgl32.HomogRotate3D(angle, mgl32.Vec3{0.0, 1.0, 0.0})
-			rotateZ := mgl32.HomogRotate3D(angle, mgl32.Vec3{0.0, 0.0, 1.0})
+	s.lightingShader.Use()
+	s.lightingShader.SetVec3("viewPos", s.camera.Position)
+	s.light.Upload(s.lightingShader, "light")
 
-			modelMatrix = modelMatrix.Mul4(translate)
-			modelMatrix = modelMatrix.Mul4(rotateX)
-			modelMatrix = modelMatrix.Mul4(rotateY)
-			modelMatrix = modelMatrix.Mul4(rotateZ)
+	s.lightingShader.SetInt("material.diffuse", 0)
+	s.lightingShader.SetInt("material.specular", 1)
+	s.lightingShader.SetFloat("material.shininess", 32.0)
 
-			lightingShader.SetMat4("model", modelMatrix)
-			gl.DrawArrays(gl.TRIANGLES, 0, 36)
-		}
+	s.light.Shadow.Bind(s.lightingShader, "shadow", 2)
 
-		window.SwapBuffers()
-		glfw.PollEvents()
-	}
-}
+	s.lightingShader.SetMat4("view", viewMatrix)
+	s.lightingShader.SetMat4("projection", projectionMatrix)
 
-func (s *DirectionalLight) processInput(w *glfw.Window) {
-	processInput(w, s)
-	processCameraKeyboardInput(w, s.camera, s.deltaTime)
+	// Render the cubes
+	drawCubes(s.lightingShader, s.cubeVAO, s.cubePositions, s.cull())
 }
 
-func (s *DirectionalLight) mouseCallback(w *glfw.Window, xpos, ypos float64) {
-	if s.firstMouse {
-		s.lastX = xpos
-		s.lastY = ypos
-		s.firstMouse = false
-	}
-
-	xoffset := xpos - s.lastX
-	yoffset := s.lastY - ypos
-	s.lastX = xpos
-	s.lastY = ypos
-
-	s.camera.ProcessMouseMovement(xoffset, yoffset, true)
+func (s *DirectionalLight) Resize(width, height int) {
+	s.resize(width, height)
 }
 
-func (s *DirectionalLight) mouseScrollCallback(w *glfw.Window, xoff, yoff float64) {
-	s.camera.ProcessMouseScroll(yoff)
+// Destroy cleans up all resources
+func (s *DirectionalLight) Destroy() {
+	gl.DeleteVertexArrays(1, &s.cubeVAO)
+	gl.DeleteVertexArrays(1, &s.lightCubeVAO)
+	gl.DeleteBuffers(1, &s.vbo)
+	s.lightingShader.Delete()
+	s.diffuseMapTex.Delete()
+	s.specularMapTex.Delete()
+	s.light.Shadow.Delete()
 }
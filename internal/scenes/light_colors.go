@@ -1,34 +1,27 @@
 package scenes
 
 import (
-	"fmt"
-	"log"
-
 	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/glfw/v3.3/glfw"
 	"github.com/go-gl/mathgl/mgl32"
-	"github.com/igoramorim/gopengl/pkg/camera"
+	"github.com/igoramorim/gopengl/internal/assets"
 	"github.com/igoramorim/gopengl/pkg/shader"
+	"github.com/igoramorim/gopengl/pkg/vertex"
 )
 
-func NewLightColors() LightColors {
-	return LightColors{
-		camera:     camera.New(),
-		firstMouse: true,
-		lastX:      float64(width) / 2,
-		lastY:      float64(height) / 2,
-		deltaTime:  0.0,
-		lastFrame:  0.0,
+func NewLightColors() *LightColors {
+	return &LightColors{
+		cameraControls: newCameraControls(),
 	}
 }
 
 type LightColors struct {
-	camera     *camera.Camera
-	firstMouse bool
-	lastX      float64
-	lastY      float64
-	deltaTime  float64 // Time between current frame and last frame
-	lastFrame  float64
+	cameraControls
+	lightingShader  *shader.Shader
+	lightCubeShader *shader.Shader
+	cubeVAO         uint32
+	lightCubeVAO    uint32
+	vbo             uint32
 }
 
 func (s LightColors) Name() string {
@@ -43,44 +36,18 @@ func (s LightColors) Height() int {
 	return height
 }
 
-func (s LightColors) Show() {
-	if err := glfw.Init(); err != nil {
-		log.Fatalln("initialize glfw:", err)
-	}
-	defer glfw.Terminate()
-
-	glfw.WindowHint(glfw.Resizable, glfw.True)
-	glfw.WindowHint(glfw.ContextVersionMajor, 4)
-	glfw.WindowHint(glfw.ContextVersionMinor, 1)
-	glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
-	glfw.WindowHint(glfw.OpenGLForwardCompatible, glfw.True)
+func (s *LightColors) Init(w *glfw.Window) error {
+	s.attach(w)
 
-	window, err := glfw.CreateWindow(s.Width(), s.Height(), s.Name(), nil, nil)
+	var err error
+	s.lightingShader, err = shader.NewFS(assets.FS, "shaders/light_colors.vert", "shaders/light_colors.frag")
 	if err != nil {
-		panic(err)
-	}
-	window.MakeContextCurrent()
-
-	window.SetFramebufferSizeCallback(frameBufferSizeCallback)
-	window.SetCursorPosCallback(s.mouseCallback)
-	window.SetScrollCallback(s.mouseScrollCallback)
-	window.SetInputMode(glfw.CursorMode, glfw.CursorDisabled)
-
-	if err := gl.Init(); err != nil {
-		panic(err)
+		return err
 	}
 
-	version := gl.GoStr(gl.GetString(gl.VERSION))
-	fmt.Println("OpenGL version:", version)
-
-	lightingShader, err := shader.New("internal/assets/shaders/light_colors.vert", "internal/assets/shaders/light_colors.frag")
+	s.lightCubeShader, err = shader.NewFS(assets.FS, "shaders/light_colors_cube.vert", "shaders/light_colors_cube.frag")
 	if err != nil {
-		panic(err)
-	}
-
-	lightCubeShader, err := shader.New("internal/assets/shaders/light_colors_cube.vert", "internal/assets/shaders/light_colors_cube.frag")
-	if err != nil {
-		panic(err)
+		return err
 	}
 
 	var vertices = []float32{
@@ -127,118 +94,88 @@ func (s LightColors) Show() {
 		-0.5, 0.5, 0.5,
 		-0.5, 0.5, -0.5,
 	}
-
 	// First, configure the cubes's VAO and VBO
-	var cubeVAO uint32
-	gl.GenVertexArrays(1, &cubeVAO)
+	gl.GenVertexArrays(1, &s.cubeVAO)
 
-	var vbo uint32
-	gl.GenBuffers(1, &vbo)
-	gl.BindBuffer(gl.ARRAY_BUFFER, vbo)
+	gl.GenBuffers(1, &s.vbo)
+	gl.BindBuffer(gl.ARRAY_BUFFER, s.vbo)
 	gl.BufferData(gl.ARRAY_BUFFER, len(vertices)*floatSize, gl.Ptr(vertices), gl.STATIC_DRAW)
 
-	gl.BindVertexArray(cubeVAO)
+	gl.BindVertexArray(s.cubeVAO)
 
 	// Position attribute
-	gl.VertexAttribPointer(0, 3, gl.FLOAT, false, 3*floatSize, nil)
-	gl.EnableVertexAttribArray(0)
+	vertex.Floats(3).Apply()
 
 	// Second, configure the light's VAO
 	// (VBO stays the same. The vertices are the same for the light object wich is also a 3D cube)
-	var lightCubeVAO uint32
-	gl.GenVertexArrays(1, &lightCubeVAO)
-	gl.BindVertexArray(lightCubeVAO)
+	gl.GenVertexArrays(1, &s.lightCubeVAO)
+	gl.BindVertexArray(s.lightCubeVAO)
 
 	// We only need to bind to the VBO (to link it with glVertexAttribPointer), no need to fill it;
 	// The VBO's data already contains all we need (it's already bound, but we do it again for educational purposes)
-	gl.BindBuffer(gl.ARRAY_BUFFER, vbo)
+	gl.BindBuffer(gl.ARRAY_BUFFER, s.vbo)
 
 	// Position attribute
-	gl.VertexAttribPointer(0, 3, gl.FLOAT, false, 3*floatSize, nil)
-	gl.EnableVertexAttribArray(0)
-
-	// Clean up all resources
-	defer func() {
-		gl.DeleteVertexArrays(1, &cubeVAO)
-		gl.DeleteBuffers(1, &vbo)
-		lightingShader.Delete()
-		lightCubeShader.Delete()
-	}()
+	vertex.Floats(3).Apply()
 
 	gl.Enable(gl.DEPTH_TEST)
 
-	// Main loop
-	for !window.ShouldClose() {
-		s.processInput(window)
-
-		gl.ClearColor(0.2, 0.3, 0.3, 1.0)
-		gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
-
-		currentFrame := glfw.GetTime()
-		s.deltaTime = currentFrame - s.lastFrame
-		s.lastFrame = currentFrame
+	return nil
+}
 
-		// Be sure to activate shader when settings uniforms/drawing objects
-		lightingShader.Use()
-		lightingShader.SetVec3f("objectColor", 1.0, 0.5, 0.31)
-		lightingShader.SetVec3f("lightColor", 1.0, 1.0, 1.0)
+func (s *LightColors) Update(w *glfw.Window, deltaTime float64) {
+	s.processInput(w, deltaTime)
+}
 
-		viewMatrix := s.camera.ViewMatrix()
+func (s *LightColors) Render(time float64) {
+	gl.ClearColor(0.2, 0.3, 0.3, 1.0)
+	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
 
-		projectionMatrix := mgl32.Ident4()
-		projectionMatrix = mgl32.Perspective(mgl32.DegToRad(float32(s.camera.Fov)), width/height, 0.1, 100.0)
+	// Be sure to activate shader when settings uniforms/drawing objects
+	s.lightingShader.Use()
+	s.lightingShader.SetVec3f("objectColor", 1.0, 0.5, 0.31)
+	s.lightingShader.SetVec3f("lightColor", 1.0, 1.0, 1.0)
 
-		lightingShader.SetMat4("view", viewMatrix)
-		lightingShader.SetMat4("projection", projectionMatrix)
+	viewMatrix := s.camera.ViewMatrix()
 
-		modelMatrix := mgl32.Ident4()
-		lightingShader.SetMat4("model", modelMatrix)
+	projectionMatrix := s.camera.ProjectionMatrix()
 
-		// Render the cube
-		gl.BindVertexArray(cubeVAO)
-		gl.DrawArrays(gl.TRIANGLES, 0, 36)
+	s.lightingShader.SetMat4("view", viewMatrix)
+	s.lightingShader.SetMat4("projection", projectionMatrix)
 
-		// Now draw the cube "lamp"
-		lightCubeShader.Use()
-		lightCubeShader.SetMat4("projection", projectionMatrix)
-		lightCubeShader.SetMat4("view", viewMatrix)
-		lightCubeShader.SetVec3f("lightColor", 1.0, 1.0, 1.0)
+	modelMatrix := mgl32.Ident4()
+	s.lightingShader.SetMat4("model", modelMatrix)
 
-		modelMatrix = mgl32.Ident4()
-		translate := mgl32.Translate3D(1.2, 1.2, 0.5)
-		modelMatrix = modelMatrix.Mul4(translate)
-		scale := mgl32.Scale3D(0.2, 0.2, 0.2)
-		modelMatrix = modelMatrix.Mul4(scale)
-		lightCubeShader.SetMat4("model", modelMatrix)
+	// Render the cube
+	gl.BindVertexArray(s.cubeVAO)
+	gl.DrawArrays(gl.TRIANGLES, 0, 36)
 
-		gl.BindVertexArray(lightCubeVAO)
-		gl.DrawArrays(gl.TRIANGLES, 0, 36)
+	// Now draw the cube "lamp"
+	s.lightCubeShader.Use()
+	s.lightCubeShader.SetMat4("projection", projectionMatrix)
+	s.lightCubeShader.SetMat4("view", viewMatrix)
+	s.lightCubeShader.SetVec3f("lightColor", 1.0, 1.0, 1.0)
 
-		window.SwapBuffers()
-		glfw.PollEvents()
-	}
-}
+	modelMatrix = mgl32.Ident4()
+	translate := mgl32.Translate3D(1.2, 1.2, 0.5)
+	modelMatrix = modelMatrix.Mul4(translate)
+	scale := mgl32.Scale3D(0.2, 0.2, 0.2)
+	modelMatrix = modelMatrix.Mul4(scale)
+	s.lightCubeShader.SetMat4("model", modelMatrix)
 
-func (s *LightColors) processInput(w *glfw.Window) {
-	processInput(w, s)
-	processCameraKeyboardInput(w, s.camera, s.deltaTime)
+	gl.BindVertexArray(s.lightCubeVAO)
+	gl.DrawArrays(gl.TRIANGLES, 0, 36)
 }
 
-func (s *LightColors) mouseCallback(w *glfw.Window, xpos, ypos float64) {
-	if s.firstMouse {
-		s.lastX = xpos
-		s.lastY = ypos
-		s.firstMouse = false
-	}
-
-	xoffset := xpos - s.lastX
-	yoffset := s.lastY - ypos
-	s.lastX = xpos
-	s.lastY = ypos
-
-	s.camera.ProcessMouseMovement(xoffset, yoffset, true)
+func (s *LightColors) Resize(width, height int) {
+	s.resize(width, height)
 }
 
-func (s *LightColors) mouseScrollCallback(w *glfw.Window, xoff, yoff float64) {
-	s.camera.ProcessMouseScroll(yoff)
+// Destroy cleans up all resources
+func (s *LightColors) Destroy() {
+	gl.DeleteVertexArrays(1, &s.cubeVAO)
+	gl.DeleteVertexArrays(1, &s.lightCubeVAO)
+	gl.DeleteBuffers(1, &s.vbo)
+	s.lightingShader.Delete()
+	s.lightCubeShader.Delete()
 }
@@ -1,35 +1,33 @@
 package scenes
 
 import (
-	"fmt"
-	"log"
-
 	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/glfw/v3.3/glfw"
 	"github.com/go-gl/mathgl/mgl32"
-	"github.com/igoramorim/gopengl/pkg/camera"
+	"github.com/igoramorim/gopengl/internal/assets"
+	"github.com/igoramorim/gopengl/pkg/material"
 	"github.com/igoramorim/gopengl/pkg/shader"
 	"github.com/igoramorim/gopengl/pkg/texture"
+	"github.com/igoramorim/gopengl/pkg/vertex"
 )
 
-func NewLightMaps() LightMaps {
-	return LightMaps{
-		camera:     camera.New(),
-		firstMouse: true,
-		lastX:      float64(width) / 2,
-		lastY:      float64(height) / 2,
-		deltaTime:  0.0,
-		lastFrame:  0.0,
+func NewLightMaps() *LightMaps {
+	return &LightMaps{
+		cameraControls: newCameraControls(),
 	}
 }
 
 type LightMaps struct {
-	camera     *camera.Camera
-	firstMouse bool
-	lastX      float64
-	lastY      float64
-	deltaTime  float64 // Time between current frame and last frame
-	lastFrame  float64
+	cameraControls
+	lightingShader  *shader.Shader
+	lightCubeShader *shader.Shader
+	diffuseMapTex   *texture.Texture
+	specularMapTex  *texture.Texture
+	emissionMapTex  *texture.Texture
+	material        *material.Material
+	cubeVAO         uint32
+	vbo             uint32
+	lightCubeVAO    uint32
 }
 
 func (s LightMaps) Name() string {
@@ -44,44 +42,18 @@ func (s LightMaps) Height() int {
 	return height
 }
 
-func (s LightMaps) Show() {
-	if err := glfw.Init(); err != nil {
-		log.Fatalln("initialize glfw:", err)
-	}
-	defer glfw.Terminate()
-
-	glfw.WindowHint(glfw.Resizable, glfw.True)
-	glfw.WindowHint(glfw.ContextVersionMajor, 4)
-	glfw.WindowHint(glfw.ContextVersionMinor, 1)
-	glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
-	glfw.WindowHint(glfw.OpenGLForwardCompatible, glfw.True)
+func (s *LightMaps) Init(w *glfw.Window) error {
+	s.attach(w)
 
-	window, err := glfw.CreateWindow(s.Width(), s.Height(), s.Name(), nil, nil)
+	var err error
+	s.lightingShader, err = shader.NewFS(assets.FS, "shaders/light_maps.vert", "shaders/light_maps.frag")
 	if err != nil {
-		panic(err)
-	}
-	window.MakeContextCurrent()
-
-	window.SetFramebufferSizeCallback(frameBufferSizeCallback)
-	window.SetCursorPosCallback(s.mouseCallback)
-	window.SetScrollCallback(s.mouseScrollCallback)
-	window.SetInputMode(glfw.CursorMode, glfw.CursorDisabled)
-
-	if err := gl.Init(); err != nil {
-		panic(err)
+		return err
 	}
 
-	version := gl.GoStr(gl.GetString(gl.VERSION))
-	fmt.Println("OpenGL version:", version)
-
-	lightingShader, err := shader.New("internal/assets/shaders/light_maps.vert", "internal/assets/shaders/light_maps.frag")
+	s.lightCubeShader, err = shader.NewFS(assets.FS, "shaders/light_colors_cube.vert", "shaders/light_colors_cube.frag")
 	if err != nil {
-		panic(err)
-	}
-
-	lightCubeShader, err := shader.New("internal/assets/shaders/light_colors_cube.vert", "internal/assets/shaders/light_colors_cube.frag")
-	if err != nil {
-		panic(err)
+		return err
 	}
 
 	var vertices = []float32{
@@ -130,155 +102,120 @@ func (s LightMaps) Show() {
 	}
 
 	// First, configure the cubes's VAO and VBO
-	var cubeVAO uint32
-	gl.GenVertexArrays(1, &cubeVAO)
+	gl.GenVertexArrays(1, &s.cubeVAO)
 
-	var vbo uint32
-	gl.GenBuffers(1, &vbo)
-	gl.BindBuffer(gl.ARRAY_BUFFER, vbo)
+	gl.GenBuffers(1, &s.vbo)
+	gl.BindBuffer(gl.ARRAY_BUFFER, s.vbo)
 	gl.BufferData(gl.ARRAY_BUFFER, len(vertices)*floatSize, gl.Ptr(vertices), gl.STATIC_DRAW)
 
-	gl.BindVertexArray(cubeVAO)
+	gl.BindVertexArray(s.cubeVAO)
 
-	// Position attribute
-	gl.VertexAttribPointer(0, 3, gl.FLOAT, false, 8*floatSize, nil)
-	gl.EnableVertexAttribArray(0)
-	// Normal attribute
-	gl.VertexAttribPointerWithOffset(1, 3, gl.FLOAT, false, 8*floatSize, 3*floatSize)
-	gl.EnableVertexAttribArray(1)
-	// Texture Coord attribute
-	gl.VertexAttribPointerWithOffset(2, 2, gl.FLOAT, false, 8*floatSize, 6*floatSize)
-	gl.EnableVertexAttribArray(2)
+	// Position, normal and texture coord attributes
+	vertex.Floats(3, 3, 2).Apply()
 
 	// Second, configure the light's VAO
 	// (VBO stays the same. The vertices are the same for the light object wich is also a 3D cube)
-	var lightCubeVAO uint32
-	gl.GenVertexArrays(1, &lightCubeVAO)
-	gl.BindVertexArray(lightCubeVAO)
+	gl.GenVertexArrays(1, &s.lightCubeVAO)
+	gl.BindVertexArray(s.lightCubeVAO)
 
 	// We only need to bind to the VBO (to link it with glVertexAttribPointer), no need to fill it;
 	// The VBO's data already contains all we need (it's already bound, but we do it again for educational purposes)
-	gl.BindBuffer(gl.ARRAY_BUFFER, vbo)
+	gl.BindBuffer(gl.ARRAY_BUFFER, s.vbo)
 
 	// Position attribute
-	gl.VertexAttribPointer(0, 3, gl.FLOAT, false, 8*floatSize, nil)
-	gl.EnableVertexAttribArray(0)
+	vertex.Floats(3, 3, 2).Only(0).Apply()
 
-	diffuseMapTex, err := texture.New("internal/assets/textures/woodbox.png", gl.TEXTURE_2D, gl.TEXTURE0, gl.RGBA, gl.RGBA, gl.UNSIGNED_INT)
+	s.diffuseMapTex, err = texture.NewFS(assets.FS, "textures/woodbox.png", gl.TEXTURE0, texture.DefaultOptions)
 	if err != nil {
-		panic(err)
+		return err
 	}
 
-	specularMapTex, err := texture.New("internal/assets/textures/woodbox_specular.png", gl.TEXTURE_2D, gl.TEXTURE1, gl.RGBA, gl.RGBA, gl.UNSIGNED_INT)
+	s.specularMapTex, err = texture.NewFS(assets.FS, "textures/woodbox_specular.png", gl.TEXTURE1, texture.DefaultOptions)
 	if err != nil {
-		panic(err)
+		return err
 	}
 
-	emissionMapTex, err := texture.New("internal/assets/textures/woodbox_emission.png", gl.TEXTURE_2D, gl.TEXTURE2, gl.RGBA, gl.RGBA, gl.UNSIGNED_INT)
+	s.emissionMapTex, err = texture.NewFS(assets.FS, "textures/woodbox_emission.png", gl.TEXTURE2, texture.DefaultOptions)
 	if err != nil {
-		panic(err)
+		return err
 	}
 
-	// Clean up all resources
-	defer func() {
-		gl.DeleteVertexArrays(1, &cubeVAO)
-		gl.DeleteBuffers(1, &vbo)
-		lightingShader.Delete()
-		lightCubeShader.Delete()
-		diffuseMapTex.Delete()
-		specularMapTex.Delete()
-		emissionMapTex.Delete()
-	}()
+	s.material = material.New("woodbox")
+	s.material.Shininess = 64.0
+	s.material.Textures[material.DiffuseMap] = s.diffuseMapTex
+	s.material.Textures[material.SpecularMap] = s.specularMapTex
+	s.material.Textures[material.EmissiveMap] = s.emissionMapTex
 
 	gl.Enable(gl.DEPTH_TEST)
 
-	// Main loop
-	for !window.ShouldClose() {
-		s.processInput(window)
-
-		gl.ClearColor(0.1, 0.1, 0.1, 1.0)
-		gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
-
-		currentFrame := glfw.GetTime()
-		s.deltaTime = currentFrame - s.lastFrame
-		s.lastFrame = currentFrame
-
-		lightPos := mgl32.Vec3{1.5, 1.0, 1.0}
-		lightColor := mgl32.Vec3{1.0, 1.0, 1.0}
+	return nil
+}
 
-		viewMatrix := s.camera.ViewMatrix()
-		modelMatrix := mgl32.Ident4()
-		projectionMatrix := mgl32.Ident4()
-		projectionMatrix = mgl32.Perspective(mgl32.DegToRad(float32(s.camera.Fov)), width/height, 0.1, 100.0)
+func (s *LightMaps) Update(w *glfw.Window, deltaTime float64) {
+	s.processInput(w, deltaTime)
+}
 
-		diffuseMapTex.ActiveAndBind()
-		specularMapTex.ActiveAndBind()
-		emissionMapTex.ActiveAndBind()
+func (s *LightMaps) Render(time float64) {
+	gl.ClearColor(0.1, 0.1, 0.1, 1.0)
+	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
 
-		lightingShader.Use()
-		lightingShader.SetVec3("light.position", lightPos)
-		lightingShader.SetVec3("viewPos", s.camera.Position)
+	lightPos := mgl32.Vec3{1.5, 1.0, 1.0}
+	lightColor := mgl32.Vec3{1.0, 1.0, 1.0}
 
-		lightingShader.SetVec3f("light.ambient", 0.2, 0.2, 0.2)
-		lightingShader.SetVec3f("light.diffuse", 0.5, 0.5, 0.5)
-		lightingShader.SetVec3f("light.specular", 1.0, 1.0, 1.0)
+	viewMatrix := s.camera.ViewMatrix()
+	modelMatrix := mgl32.Ident4()
+	projectionMatrix := s.camera.ProjectionMatrix()
 
-		lightingShader.SetInt("material.diffuse", 0)
-		lightingShader.SetInt("material.specular", 1)
-		lightingShader.SetInt("material.emission", 2)
-		lightingShader.SetFloat("material.shininess", 64.0)
+	s.lightingShader.Use()
+	s.lightingShader.SetVec3("light.position", lightPos)
+	s.lightingShader.SetVec3("viewPos", s.camera.Position)
 
-		lightingShader.SetMat4("view", viewMatrix)
-		lightingShader.SetMat4("projection", projectionMatrix)
+	s.lightingShader.SetVec3f("light.ambient", 0.2, 0.2, 0.2)
+	s.lightingShader.SetVec3f("light.diffuse", 0.5, 0.5, 0.5)
+	s.lightingShader.SetVec3f("light.specular", 1.0, 1.0, 1.0)
 
-		modelMatrix = mgl32.Ident4()
-		lightingShader.SetMat4("model", modelMatrix)
+	s.material.Bind(s.lightingShader, "material", 0)
 
-		// Render the cube
-		gl.BindVertexArray(cubeVAO)
-		gl.DrawArrays(gl.TRIANGLES, 0, 36)
+	s.lightingShader.SetMat4("view", viewMatrix)
+	s.lightingShader.SetMat4("projection", projectionMatrix)
 
-		// Now draw the cube "lamp"
-		lightCubeShader.Use()
-		lightCubeShader.SetMat4("projection", projectionMatrix)
-		lightCubeShader.SetMat4("view", viewMatrix)
-		lightCubeShader.SetVec3("lightColor", lightColor)
+	modelMatrix = mgl32.Ident4()
+	s.lightingShader.SetMat4("model", modelMatrix)
 
-		modelMatrix = mgl32.Ident4()
-		translate := mgl32.Translate3D(lightPos.X(), lightPos.Y(), lightPos.Z())
-		modelMatrix = modelMatrix.Mul4(translate)
-		scale := mgl32.Scale3D(0.2, 0.2, 0.2)
-		modelMatrix = modelMatrix.Mul4(scale)
-		lightCubeShader.SetMat4("model", modelMatrix)
+	// Render the cube
+	gl.BindVertexArray(s.cubeVAO)
+	gl.DrawArrays(gl.TRIANGLES, 0, 36)
 
-		gl.BindVertexArray(lightCubeVAO)
-		gl.DrawArrays(gl.TRIANGLES, 0, 36)
+	// Now draw the cube "lamp"
+	s.lightCubeShader.Use()
+	s.lightCubeShader.SetMat4("projection", projectionMatrix)
+	s.lightCubeShader.SetMat4("view", viewMatrix)
+	s.lightCubeShader.SetVec3("lightColor", lightColor)
 
-		window.SwapBuffers()
-		glfw.PollEvents()
-	}
-}
+	modelMatrix = mgl32.Ident4()
+	translate := mgl32.Translate3D(lightPos.X(), lightPos.Y(), lightPos.Z())
+	modelMatrix = modelMatrix.Mul4(translate)
+	scale := mgl32.Scale3D(0.2, 0.2, 0.2)
+	modelMatrix = modelMatrix.Mul4(scale)
+	s.lightCubeShader.SetMat4("model", modelMatrix)
 
-func (s *LightMaps) processInput(w *glfw.Window) {
-	processInput(w, s)
-	processCameraKeyboardInput(w, s.camera, s.deltaTime)
+	gl.BindVertexArray(s.lightCubeVAO)
+	gl.DrawArrays(gl.TRIANGLES, 0, 36)
 }
 
-func (s *LightMaps) mouseCallback(w *glfw.Window, xpos, ypos float64) {
-	if s.firstMouse {
-		s.lastX = xpos
-		s.lastY = ypos
-		s.firstMouse = false
-	}
-
-	xoffset := xpos - s.lastX
-	yoffset := s.lastY - ypos
-	s.lastX = xpos
-	s.lastY = ypos
-
-	s.camera.ProcessMouseMovement(xoffset, yoffset, true)
+func (s *LightMaps) Resize(width, height int) {
+	s.resize(width, height)
 }
 
-func (s *LightMaps) mouseScrollCallback(w *glfw.Window, xoff, yoff float64) {
-	s.camera.ProcessMouseScroll(yoff)
+// Destroy cleans up all resources
+func (s *LightMaps) Destroy() {
+	gl.DeleteVertexArrays(1, &s.cubeVAO)
+	gl.DeleteVertexArrays(1, &s.lightCubeVAO)
+	gl.DeleteBuffers(1, &s.vbo)
+	s.lightingShader.Delete()
+	s.lightCubeShader.Delete()
+	s.diffuseMapTex.Delete()
+	s.specularMapTex.Delete()
+	s.emissionMapTex.Delete()
+	s.material.Delete()
 }
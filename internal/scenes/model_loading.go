@@ -2,35 +2,31 @@ package scenes
 
 import (
 	"fmt"
-	"log"
 	"math"
 
 	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/glfw/v3.3/glfw"
 	"github.com/go-gl/mathgl/mgl32"
+	"github.com/igoramorim/gopengl/internal/assets"
 	"github.com/igoramorim/gopengl/pkg/camera"
 	"github.com/igoramorim/gopengl/pkg/model"
 	"github.com/igoramorim/gopengl/pkg/shader"
+	"github.com/igoramorim/gopengl/pkg/vertex"
 )
 
-func NewModelLoading() ModelLoading {
-	return ModelLoading{
-		camera:     camera.New(),
-		firstMouse: true,
-		lastX:      float64(width) / 2,
-		lastY:      float64(height) / 2,
-		deltaTime:  0.0,
-		lastFrame:  0.0,
+func NewModelLoading() *ModelLoading {
+	return &ModelLoading{
+		cameraControls: newCameraControls(),
 	}
 }
 
 type ModelLoading struct {
-	camera     *camera.Camera
-	firstMouse bool
-	lastX      float64
-	lastY      float64
-	deltaTime  float64 // Time between current frame and last frame
-	lastFrame  float64
+	cameraControls
+	modelShader     *shader.Shader
+	model3D         *model.Model
+	lightCubeShader *shader.Shader
+	vbo             uint32
+	lightCubeVAO    uint32
 }
 
 func (s ModelLoading) Name() string {
@@ -45,49 +41,29 @@ func (s ModelLoading) Height() int {
 	return height
 }
 
-func (s ModelLoading) Show() {
-	if err := glfw.Init(); err != nil {
-		log.Fatalln("initialize glfw:", err)
-	}
-	defer glfw.Terminate()
-
-	glfw.WindowHint(glfw.Resizable, glfw.True)
-	glfw.WindowHint(glfw.ContextVersionMajor, 4)
-	glfw.WindowHint(glfw.ContextVersionMinor, 1)
-	glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
-	glfw.WindowHint(glfw.OpenGLForwardCompatible, glfw.True)
+func (s *ModelLoading) Init(w *glfw.Window) error {
+	s.attach(w)
 
-	window, err := glfw.CreateWindow(s.Width(), s.Height(), s.Name(), nil, nil)
+	var err error
+	s.modelShader, err = shader.NewFS(assets.FS, "shaders/model_loading.vert", "shaders/model_loading.frag")
 	if err != nil {
-		panic(err)
-	}
-	window.MakeContextCurrent()
-
-	window.SetFramebufferSizeCallback(frameBufferSizeCallback)
-	window.SetCursorPosCallback(s.mouseCallback)
-	window.SetScrollCallback(s.mouseScrollCallback)
-	window.SetInputMode(glfw.CursorMode, glfw.CursorDisabled)
-
-	if err := gl.Init(); err != nil {
-		panic(err)
+		return err
 	}
 
-	version := gl.GoStr(gl.GetString(gl.VERSION))
-	fmt.Println("OpenGL version:", version)
-
-	modelShader, err := shader.New("internal/assets/shaders/model_loading.vert", "internal/assets/shaders/model_loading.frag")
+	s.model3D, err = model.NewOBJFS(assets.FS, "models/crate/crate.obj")
 	if err != nil {
-		panic(err)
+		return err
 	}
 
-	model3D, err := model.New("internal/assets/models/backpack/backpack.obj")
-	if err != nil {
-		panic(err)
-	}
+	// The orbit camera starts framing the crate
+	orbit := camera.NewOrbit(mgl32.Vec3{}, 3.0)
+	bounds := s.model3D.Bounds()
+	orbit.Frame(bounds.Min, bounds.Max)
+	s.useOrbit(orbit)
 
-	lightCubeShader, err := shader.New("internal/assets/shaders/light_colors_cube.vert", "internal/assets/shaders/light_colors_cube.frag")
+	s.lightCubeShader, err = shader.NewFS(assets.FS, "shaders/light_colors_cube.vert", "shaders/light_colors_cube.frag")
 	if err != nil {
-		panic(err)
+		return err
 	}
 
 	var vertices = []float32{
@@ -135,113 +111,91 @@ func (s ModelLoading) Show() {
 		-0.5, 0.5, -0.5, 0.0, 1.0, 0.0, 0.0, 1.0,
 	}
 
-	var vbo uint32
-	gl.GenBuffers(1, &vbo)
-	gl.BindBuffer(gl.ARRAY_BUFFER, vbo)
+	gl.GenBuffers(1, &s.vbo)
+	gl.BindBuffer(gl.ARRAY_BUFFER, s.vbo)
 	gl.BufferData(gl.ARRAY_BUFFER, len(vertices)*floatSize, gl.Ptr(vertices), gl.STATIC_DRAW)
 
-	var lightCubeVAO uint32
-	gl.GenVertexArrays(1, &lightCubeVAO)
-	gl.BindVertexArray(lightCubeVAO)
-	gl.BindVertexArray(lightCubeVAO)
+	gl.GenVertexArrays(1, &s.lightCubeVAO)
+	gl.BindVertexArray(s.lightCubeVAO)
+	gl.BindVertexArray(s.lightCubeVAO)
 
 	// Position attribute
-	gl.VertexAttribPointer(0, 3, gl.FLOAT, false, 8*floatSize, nil)
-	gl.EnableVertexAttribArray(0)
-
-	// Clean up all resources
-	defer func() {
-		modelShader.Delete()
-		lightCubeShader.Delete()
-	}()
+	vertex.Floats(3, 3, 2).Only(0).Apply()
 
 	gl.Enable(gl.DEPTH_TEST)
 
-	// Main loop
-	for !window.ShouldClose() {
-		s.processInput(window)
+	fmt.Println("model_loading: press O to switch between the fly-through and orbit cameras")
 
-		gl.ClearColor(0.1, 0.1, 0.1, 1.0)
-		gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+	return nil
+}
 
-		currentFrame := glfw.GetTime()
-		s.deltaTime = currentFrame - s.lastFrame
-		s.lastFrame = currentFrame
+func (s *ModelLoading) Update(w *glfw.Window, deltaTime float64) {
+	s.processInput(w, deltaTime)
+}
 
-		// Draw the 3D model
-		modelShader.Use()
+func (s *ModelLoading) Render(time float64) {
+	gl.ClearColor(0.1, 0.1, 0.1, 1.0)
+	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
 
-		viewMatrix := s.camera.ViewMatrix()
-		projectionMatrix := mgl32.Ident4()
-		projectionMatrix = mgl32.Perspective(mgl32.DegToRad(float32(s.camera.Fov)), width/height, 0.1, 100.0)
-		modelShader.SetMat4("view", viewMatrix)
-		modelShader.SetMat4("projection", projectionMatrix)
+	// Draw the 3D model
+	s.modelShader.Use()
 
-		modelMatrix := mgl32.Ident4()
-		modelShader.SetMat4("model", modelMatrix)
+	view := s.view()
+	viewMatrix := view.ViewMatrix()
+	projectionMatrix := view.ProjectionMatrix()
+	s.modelShader.SetMat4("view", viewMatrix)
+	s.modelShader.SetMat4("projection", projectionMatrix)
 
-		lightPos := mgl32.Vec3{
-			-1.0 + float32(math.Sin(glfw.GetTime())*3.0),
-			0.6,
-			float32(math.Cos(glfw.GetTime()) * 3.0),
-			// 0.0, 2.0, 1.0,
-		}
+	modelMatrix := mgl32.Ident4()
+	s.modelShader.SetMat4("model", modelMatrix)
 
-		modelShader.SetVec3("light.position", lightPos)
-		modelShader.SetVec3("viewPos", s.camera.Position)
+	lightPos := mgl32.Vec3{
+		-1.0 + float32(math.Sin(time)*3.0),
+		0.6,
+		float32(math.Cos(time) * 3.0),
+		// 0.0, 2.0, 1.0,
+	}
 
-		modelShader.SetVec3f("light.ambient", 0.2, 0.2, 0.2)
-		modelShader.SetVec3f("light.diffuse", 0.9, 0.6, 0.4)
-		modelShader.SetVec3f("light.specular", 1.0, 1.0, 1.0)
-		modelShader.SetFloat("light.constant", 1.0)
-		modelShader.SetFloat("light.linear", 0.09)
-		modelShader.SetFloat("light.quadratic", 0.032)
+	s.modelShader.SetVec3("light.position", lightPos)
+	s.modelShader.SetVec3("viewPos", view.Eye())
 
-		model3D.Draw(modelShader)
+	s.modelShader.SetVec3f("light.ambient", 0.2, 0.2, 0.2)
+	s.modelShader.SetVec3f("light.diffuse", 0.9, 0.6, 0.4)
+	s.modelShader.SetVec3f("light.specular", 1.0, 1.0, 1.0)
+	s.modelShader.SetFloat("light.constant", 1.0)
+	s.modelShader.SetFloat("light.linear", 0.09)
+	s.modelShader.SetFloat("light.quadratic", 0.032)
 
-		// Draw the lamp
-		lightCubeShader.Use()
+	s.model3D.DrawCulled(s.modelShader, s.cull(), modelMatrix)
 
-		lightCubeShader.SetMat4("view", viewMatrix)
-		lightCubeShader.SetMat4("projection", projectionMatrix)
+	// Draw the lamp
+	s.lightCubeShader.Use()
 
-		modelMatrix = mgl32.Ident4()
-		translate := mgl32.Translate3D(lightPos.X(), lightPos.Y(), lightPos.Z())
-		modelMatrix = modelMatrix.Mul4(translate)
-		scale := mgl32.Scale3D(0.2, 0.2, 0.2)
-		modelMatrix = modelMatrix.Mul4(scale)
-		lightCubeShader.SetMat4("model", modelMatrix)
+	s.lightCubeShader.SetMat4("view", viewMatrix)
+	s.lightCubeShader.SetMat4("projection", projectionMatrix)
 
-		lightCubeShader.SetVec3("lightColor", mgl32.Vec3{1.0, 1.0, 1.0})
+	modelMatrix = mgl32.Ident4()
+	translate := mgl32.Translate3D(lightPos.X(), lightPos.Y(), lightPos.Z())
+	modelMatrix = modelMatrix.Mul4(translate)
+	scale := mgl32.Scale3D(0.2, 0.2, 0.2)
+	modelMatrix = modelMatrix.Mul4(scale)
+	s.lightCubeShader.SetMat4("model", modelMatrix)
 
-		gl.BindVertexArray(lightCubeVAO)
-		gl.DrawArrays(gl.TRIANGLES, 0, 36)
+	s.lightCubeShader.SetVec3("lightColor", mgl32.Vec3{1.0, 1.0, 1.0})
 
-		window.SwapBuffers()
-		glfw.PollEvents()
-	}
+	gl.BindVertexArray(s.lightCubeVAO)
+	gl.DrawArrays(gl.TRIANGLES, 0, 36)
 }
 
-func (s *ModelLoading) processInput(w *glfw.Window) {
-	processInput(w, s)
-	processCameraKeyboardInput(w, s.camera, s.deltaTime)
-}
-
-func (s *ModelLoading) mouseCallback(w *glfw.Window, xpos, ypos float64) {
-	if s.firstMouse {
-		s.lastX = xpos
-		s.lastY = ypos
-		s.firstMouse = false
-	}
-
-	xoffset := xpos - s.lastX
-	yoffset := s.lastY - ypos
-	s.lastX = xpos
-	s.lastY = ypos
-
-	s.camera.ProcessMouseMovement(xoffset, yoffset, true)
+func (s *ModelLoading) Resize(width, height int) {
+	s.resize(width, height)
 }
 
-func (s *ModelLoading) mouseScrollCallback(w *glfw.Window, xoff, yoff float64) {
-	s.camera.ProcessMouseScroll(yoff)
+// Destroy cleans up all resources
+func (s *ModelLoading) Destroy() {
+	gl.DeleteVertexArrays(1, &s.lightCubeVAO)
+	gl.DeleteBuffers(1, &s.vbo)
+	s.modelShader.Delete()
+	s.model3D.Delete()
+	s.lightCubeShader.Delete()
 }
@@ -0,0 +1,373 @@
+package scenes
+
+import (
+	"fmt"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/igoramorim/gopengl/internal/assets"
+	"github.com/igoramorim/gopengl/pkg/light"
+	"github.com/igoramorim/gopengl/pkg/shader"
+	"github.com/igoramorim/gopengl/pkg/texture"
+	"github.com/igoramorim/gopengl/pkg/vertex"
+)
+
+// multipleLightsColors are the colors cycled through with the C key.
+var multipleLightsColors = []mgl32.Vec3{
+	{1.0, 1.0, 1.0},
+	{1.0, 0.2, 0.2},
+	{0.2, 1.0, 0.2},
+	{0.2, 0.2, 1.0},
+	{1.0, 0.8, 0.3},
+}
+
+// multipleLightsSelect are the keys that select the light edited at runtime: 1 to 4 the
+// point lights, 5 the flashlight and 0 the directional light.
+var multipleLightsSelect = []glfw.Key{
+	glfw.Key0, glfw.Key1, glfw.Key2, glfw.Key3, glfw.Key4, glfw.Key5,
+}
+
+// multipleLightsBinding is the uniform buffer binding point of the lights.
+const multipleLightsBinding = 0
+
+func NewMultipleLights() *MultipleLights {
+	return &MultipleLights{
+		cameraControls: newCameraControls(),
+	}
+}
+
+type MultipleLights struct {
+	cameraControls
+	lightingShader *shader.Shader
+	// blockShader reads the lights from lightsBuffer instead of uniforms, see useBuffer
+	blockShader     *shader.Shader
+	lightsBuffer    *light.UniformBuffer
+	useBuffer       bool
+	lightCubeShader *shader.Shader
+	diffuseMapTex   *texture.Texture
+	specularMapTex  *texture.Texture
+	cubeVAO         uint32
+	vbo             uint32
+	lightCubeVAO    uint32
+	cubePositions   []mgl32.Vec3
+	dirLight        light.DirectionalLight
+	pointLights     []*light.PointLight
+	spotLight       light.SpotLight
+	// selected is the light edited at runtime, the index of its key in multipleLightsSelect
+	selected int
+	// colors are the index in multipleLightsColors of the color of each light, in the
+	// order of multipleLightsSelect
+	colors []int
+}
+
+func (s MultipleLights) Name() string {
+	return "multiple_lights"
+}
+
+func (s MultipleLights) Width() int {
+	return width
+}
+
+func (s MultipleLights) Height() int {
+	return height
+}
+
+func (s *MultipleLights) Init(w *glfw.Window) error {
+	s.attach(w)
+
+	s.dirLight = light.DirectionalLight{
+		Direction: mgl32.Vec3{-0.2, -1.0, -0.3},
+		Colors:    light.NewColors(mgl32.Vec3{1.0, 1.0, 1.0}, 0.05, 0.4),
+	}
+
+	for _, pos := range []mgl32.Vec3{
+		{0.7, 0.2, 2.0},
+		{2.3, -3.3, -4.0},
+		{-4.0, 2.0, -12.0},
+		{0.0, 0.0, -3.0},
+	} {
+		s.pointLights = append(s.pointLights, &light.PointLight{
+			Position:    pos,
+			Colors:      light.NewColors(mgl32.Vec3{1.0, 1.0, 1.0}, 0.05, 0.8),
+			Attenuation: light.Range(50),
+		})
+	}
+
+	s.spotLight = light.SpotLight{
+		CutOff:      12.5,
+		OuterCutOff: 15.0,
+		Colors:      light.NewColors(mgl32.Vec3{1.0, 1.0, 1.0}, 0.0, 1.0),
+		Attenuation: light.Range(50),
+	}
+
+	s.colors = make([]int, len(multipleLightsSelect))
+
+	var err error
+	s.lightingShader, err = shader.NewProgramFS(assets.FS,
+		shader.Vertex("shaders/multiple_lights.vert"),
+		shader.Fragment("shaders/multiple_lights.frag").Define("NR_POINT_LIGHTS", len(s.pointLights)),
+	)
+	if err != nil {
+		return err
+	}
+
+	s.blockShader, err = shader.NewProgramFS(assets.FS,
+		shader.Vertex("shaders/multiple_lights.vert"),
+		shader.Fragment("shaders/multiple_lights.frag").
+			Define("LIGHTS_BLOCK", 1).
+			Define("NR_POINT_LIGHTS", len(s.pointLights)).
+			Define("NR_SPOT_LIGHTS", 1),
+	)
+	if err != nil {
+		return err
+	}
+
+	s.lightsBuffer, err = light.NewUniformBuffer(multipleLightsBinding, len(s.pointLights), 1)
+	if err != nil {
+		return err
+	}
+
+	s.lightCubeShader, err = shader.NewFS(assets.FS, "shaders/light_colors_cube.vert", "shaders/light_colors_cube.frag")
+	if err != nil {
+		return err
+	}
+
+	var vertices = []float32{
+		// x y z          normals         texture coords
+		-0.5, -0.5, -0.5, 0.0, 0.0, -1.0, 0.0, 0.0,
+		0.5, -0.5, -0.5, 0.0, 0.0, -1.0, 1.0, 0.0,
+		0.5, 0.5, -0.5, 0.0, 0.0, -1.0, 1.0, 1.0,
+		0.5, 0.5, -0.5, 0.0, 0.0, -1.0, 1.0, 1.0,
+		-0.5, 0.5, -0.5, 0.0, 0.0, -1.0, 0.0, 1.0,
+		-0.5, -0.5, -0.5, 0.0, 0.0, -1.0, 0.0, 0.0,
+
+		-0.5, -0.5, 0.5, 0.0, 0.0, 1.0, 0.0, 0.0,
+		0.5, -0.5, 0.5, 0.0, 0.0, 1.0, 1.0, 0.0,
+		0.5, 0.5, 0.5, 0.0, 0.0, 1.0, 1.0, 1.0,
+		0.5, 0.5, 0.5, 0.0, 0.0, 1.0, 1.0, 1.0,
+		-0.5, 0.5, 0.5, 0.0, 0.0, 1.0, 0.0, 1.0,
+		-0.5, -0.5, 0.5, 0.0, 0.0, 1.0, 0.0, 0.0,
+
+		-0.5, 0.5, 0.5, -1.0, 0.0, 0.0, 1.0, 0.0,
+		-0.5, 0.5, -0.5, -1.0, 0.0, 0.0, 1.0, 1.0,
+		-0.5, -0.5, -0.5, -1.0, 0.0, 0.0, 0.0, 1.0,
+		-0.5, -0.5, -0.5, -1.0, 0.0, 0.0, 0.0, 1.0,
+		-0.5, -0.5, 0.5, -1.0, 0.0, 0.0, 0.0, 0.0,
+		-0.5, 0.5, 0.5, -1.0, 0.0, 0.0, 1.0, 0.0,
+
+		0.5, 0.5, 0.5, 1.0, 0.0, 0.0, 1.0, 0.0,
+		0.5, 0.5, -0.5, 1.0, 0.0, 0.0, 1.0, 1.0,
+		0.5, -0.5, -0.5, 1.0, 0.0, 0.0, 0.0, 1.0,
+		0.5, -0.5, -0.5, 1.0, 0.0, 0.0, 0.0, 1.0,
+		0.5, -0.5, 0.5, 1.0, 0.0, 0.0, 0.0, 0.0,
+		0.5, 0.5, 0.5, 1.0, 0.0, 0.0, 1.0, 0.0,
+
+		-0.5, -0.5, -0.5, 0.0, -1.0, 0.0, 0.0, 1.0,
+		0.5, -0.5, -0.5, 0.0, -1.0, 0.0, 1.0, 1.0,
+		0.5, -0.5, 0.5, 0.0, -1.0, 0.0, 1.0, 0.0,
+		0.5, -0.5, 0.5, 0.0, -1.0, 0.0, 1.0, 0.0,
+		-0.5, -0.5, 0.5, 0.0, -1.0, 0.0, 0.0, 0.0,
+		-0.5, -0.5, -0.5, 0.0, -1.0, 0.0, 0.0, 1.0,
+
+		-0.5, 0.5, -0.5, 0.0, 1.0, 0.0, 0.0, 1.0,
+		0.5, 0.5, -0.5, 0.0, 1.0, 0.0, 1.0, 1.0,
+		0.5, 0.5, 0.5, 0.0, 1.0, 0.0, 1.0, 0.0,
+		0.5, 0.5, 0.5, 0.0, 1.0, 0.0, 1.0, 0.0,
+		-0.5, 0.5, 0.5, 0.0, 1.0, 0.0, 0.0, 0.0,
+		-0.5, 0.5, -0.5, 0.0, 1.0, 0.0, 0.0, 1.0,
+	}
+
+	// First, configure the cubes's VAO and VBO
+	gl.GenVertexArrays(1, &s.cubeVAO)
+
+	gl.GenBuffers(1, &s.vbo)
+	gl.BindBuffer(gl.ARRAY_BUFFER, s.vbo)
+	gl.BufferData(gl.ARRAY_BUFFER, len(vertices)*floatSize, gl.Ptr(vertices), gl.STATIC_DRAW)
+
+	gl.BindVertexArray(s.cubeVAO)
+
+	// Position, normal and texture coord attributes
+	vertex.Floats(3, 3, 2).Apply()
+
+	// Second, configure the light's VAO
+	// (VBO stays the same. The vertices are the same for the light object wich is also a 3D cube)
+	gl.GenVertexArrays(1, &s.lightCubeVAO)
+	gl.BindVertexArray(s.lightCubeVAO)
+
+	// We only need to bind to the VBO (to link it with glVertexAttribPointer), no need to fill it;
+	// The VBO's data already contains all we need (it's already bound, but we do it again for educational purposes)
+	gl.BindBuffer(gl.ARRAY_BUFFER, s.vbo)
+
+	// Position attribute
+	vertex.Floats(3, 3, 2).Only(0).Apply()
+
+	s.diffuseMapTex, err = texture.NewFS(assets.FS, "textures/woodbox.png", gl.TEXTURE0, texture.DefaultOptions)
+	if err != nil {
+		return err
+	}
+
+	s.specularMapTex, err = texture.NewFS(assets.FS, "textures/woodbox_specular.png", gl.TEXTURE1, texture.DefaultOptions)
+	if err != nil {
+		return err
+	}
+
+	gl.Enable(gl.DEPTH_TEST)
+
+	s.cubePositions = []mgl32.Vec3{
+		mgl32.Vec3{0.0, 0.0, 0.0},
+		mgl32.Vec3{2.0, 5.0, -15.0},
+		mgl32.Vec3{-1.5, -2.2, -2.5},
+		mgl32.Vec3{-3.8, -2.0, -12.3},
+		mgl32.Vec3{2.4, -0.4, -3.5},
+		mgl32.Vec3{-1.7, 3.0, -7.5},
+		mgl32.Vec3{1.3, -2.0, -2.5},
+		mgl32.Vec3{1.5, 2.0, -2.5},
+		mgl32.Vec3{1.5, 0.2, -1.5},
+		mgl32.Vec3{-1.3, 1.0, -1.5},
+	}
+
+	fmt.Println("multiple_lights: press 0 to 5 to select a light, C to change its color and + and - its intensity")
+	fmt.Println("multiple_lights: press U to switch between uploading the lights as uniforms and in a uniform buffer")
+
+	return nil
+}
+
+// selectedColors returns the colors of the light edited at runtime.
+func (s *MultipleLights) selectedColors() *light.Colors {
+	switch s.selected {
+	case 0:
+		return &s.dirLight.Colors
+	case len(multipleLightsSelect) - 1:
+		return &s.spotLight.Colors
+	default:
+		return &s.pointLights[s.selected-1].Colors
+	}
+}
+
+func (s *MultipleLights) Update(w *glfw.Window, deltaTime float64) {
+	s.processInput(w, deltaTime)
+
+	for i, key := range multipleLightsSelect {
+		if s.justPressed(w, key) {
+			s.selected = i
+			fmt.Printf("multiple_lights: light %d selected\n", i)
+		}
+	}
+
+	colors := s.selectedColors()
+
+	if s.justPressed(w, glfw.KeyC) {
+		s.colors[s.selected] = (s.colors[s.selected] + 1) % len(multipleLightsColors)
+		color := multipleLightsColors[s.colors[s.selected]]
+
+		// Keeps the share of the color that is ambient and diffuse, and the intensity
+		ambient := colors.Ambient.Len() / colors.Specular.Len()
+		diffuse := colors.Diffuse.Len() / colors.Specular.Len()
+		intensity := colors.Intensity
+		*colors = light.NewColors(color, ambient, diffuse)
+		colors.Intensity = intensity
+	}
+
+	// The arrows move the camera, = is the + key without shift
+	if w.GetKey(glfw.KeyEqual) == glfw.Press || w.GetKey(glfw.KeyKPAdd) == glfw.Press {
+		colors.Intensity *= 1 + float32(deltaTime)
+	}
+	if w.GetKey(glfw.KeyMinus) == glfw.Press || w.GetKey(glfw.KeyKPSubtract) == glfw.Press {
+		colors.Intensity /= 1 + float32(deltaTime)
+	}
+
+	if s.justPressed(w, glfw.KeyU) {
+		s.useBuffer = !s.useBuffer
+		if s.useBuffer {
+			fmt.Println("multiple_lights: lights in a uniform buffer")
+		} else {
+			fmt.Println("multiple_lights: lights as uniforms")
+		}
+	}
+}
+
+// uploadLights sets the lights of the shader returned, either as its uniforms or in the
+// uniform buffer.
+func (s *MultipleLights) uploadLights() *shader.Shader {
+	if !s.useBuffer {
+		s.lightingShader.Use()
+		s.dirLight.Upload(s.lightingShader, "dirLight")
+		light.UploadPointLights(s.lightingShader, "pointLights", s.pointLights)
+		s.spotLight.Upload(s.lightingShader, "spotLight")
+		return s.lightingShader
+	}
+
+	if err := s.lightsBuffer.Update(&s.dirLight, s.pointLights, []*light.SpotLight{&s.spotLight}); err != nil {
+		fmt.Println(err.Error())
+	}
+
+	// Bound every frame as the hot reload of the shader drops the binding
+	if err := s.lightsBuffer.Bind(s.blockShader, "Lights"); err != nil {
+		fmt.Println(err.Error())
+	}
+
+	s.blockShader.Use()
+	return s.blockShader
+}
+
+func (s *MultipleLights) Render(time float64) {
+	gl.ClearColor(0.1, 0.1, 0.1, 1.0)
+	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+
+	// The spotlight is a flashlight held by the camera
+	s.spotLight.Position = s.camera.Position
+	s.spotLight.Direction = s.camera.Front
+
+	viewMatrix := s.camera.ViewMatrix()
+	projectionMatrix := s.camera.ProjectionMatrix()
+
+	s.diffuseMapTex.ActiveAndBind()
+	s.specularMapTex.ActiveAndBind()
+
+	lightingShader := s.uploadLights()
+	lightingShader.SetVec3("viewPos", s.camera.Position)
+
+	lightingShader.SetInt("material.diffuse", 0)
+	lightingShader.SetInt("material.specular", 1)
+	lightingShader.SetFloat("material.shininess", 32.0)
+
+	lightingShader.SetMat4("view", viewMatrix)
+	lightingShader.SetMat4("projection", projectionMatrix)
+
+	// Render the cubes
+	drawCubes(lightingShader, s.cubeVAO, s.cubePositions, s.cull())
+
+	// Now draw a lamp for each point light, of its color
+	s.lightCubeShader.Use()
+	s.lightCubeShader.SetMat4("projection", projectionMatrix)
+	s.lightCubeShader.SetMat4("view", viewMatrix)
+
+	gl.BindVertexArray(s.lightCubeVAO)
+	for _, l := range s.pointLights {
+		s.lightCubeShader.SetVec3("lightColor", l.Specular.Mul(min(l.Intensity, 1.0)))
+
+		modelMatrix := mgl32.Translate3D(l.Position.X(), l.Position.Y(), l.Position.Z())
+		modelMatrix = modelMatrix.Mul4(mgl32.Scale3D(0.2, 0.2, 0.2))
+		s.lightCubeShader.SetMat4("model", modelMatrix)
+
+		gl.DrawArrays(gl.TRIANGLES, 0, 36)
+	}
+}
+
+func (s *MultipleLights) Resize(width, height int) {
+	s.resize(width, height)
+}
+
+// Destroy cleans up all resources
+func (s *MultipleLights) Destroy() {
+	gl.DeleteVertexArrays(1, &s.cubeVAO)
+	gl.DeleteVertexArrays(1, &s.lightCubeVAO)
+	gl.DeleteBuffers(1, &s.vbo)
+	s.lightingShader.Delete()
+	s.blockShader.Delete()
+	s.lightsBuffer.Delete()
+	s.lightCubeShader.Delete()
+	s.diffuseMapTex.Delete()
+	s.specularMapTex.Delete()
+}
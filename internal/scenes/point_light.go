@@ -1,35 +1,34 @@
 package scenes
 
 import (
-	"fmt"
-	"log"
-
 	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/glfw/v3.3/glfw"
 	"github.com/go-gl/mathgl/mgl32"
-	"github.com/igoramorim/gopengl/pkg/camera"
+	"github.com/igoramorim/gopengl/internal/assets"
+	"github.com/igoramorim/gopengl/pkg/light"
 	"github.com/igoramorim/gopengl/pkg/shader"
+	"github.com/igoramorim/gopengl/pkg/shadow"
 	"github.com/igoramorim/gopengl/pkg/texture"
+	"github.com/igoramorim/gopengl/pkg/vertex"
 )
 
-func NewPointLight() PointLight {
-	return PointLight{
-		camera:     camera.New(),
-		firstMouse: true,
-		lastX:      float64(width) / 2,
-		lastY:      float64(height) / 2,
-		deltaTime:  0.0,
-		lastFrame:  0.0,
+func NewPointLight() *PointLight {
+	return &PointLight{
+		cameraControls: newCameraControls(),
 	}
 }
 
 type PointLight struct {
-	camera     *camera.Camera
-	firstMouse bool
-	lastX      float64
-	lastY      float64
-	deltaTime  float64 // Time between current frame and last frame
-	lastFrame  float64
+	cameraControls
+	lightingShader  *shader.Shader
+	lightCubeShader *shader.Shader
+	diffuseMapTex   *texture.Texture
+	specularMapTex  *texture.Texture
+	cubeVAO         uint32
+	vbo             uint32
+	lightCubeVAO    uint32
+	cubePositions   []mgl32.Vec3
+	light           light.PointLight
 }
 
 func (s PointLight) Name() string {
@@ -44,44 +43,18 @@ func (s PointLight) Height() int {
 	return height
 }
 
-func (s PointLight) Show() {
-	if err := glfw.Init(); err != nil {
-		log.Fatalln("initialize glfw:", err)
-	}
-	defer glfw.Terminate()
-
-	glfw.WindowHint(glfw.Resizable, glfw.True)
-	glfw.WindowHint(glfw.ContextVersionMajor, 4)
-	glfw.WindowHint(glfw.ContextVersionMinor, 1)
-	glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
-	glfw.WindowHint(glfw.OpenGLForwardCompatible, glfw.True)
-
-	window, err := glfw.CreateWindow(s.Width(), s.Height(), s.Name(), nil, nil)
-	if err != nil {
-		panic(err)
-	}
-	window.MakeContextCurrent()
-
-	window.SetFramebufferSizeCallback(frameBufferSizeCallback)
-	window.SetCursorPosCallback(s.mouseCallback)
-	window.SetScrollCallback(s.mouseScrollCallback)
-	window.SetInputMode(glfw.CursorMode, glfw.CursorDisabled)
+func (s *PointLight) Init(w *glfw.Window) error {
+	s.attach(w)
 
-	if err := gl.Init(); err != nil {
-		panic(err)
-	}
-
-	version := gl.GoStr(gl.GetString(gl.VERSION))
-	fmt.Println("OpenGL version:", version)
-
-	lightingShader, err := shader.New("internal/assets/shaders/point_light.vert", "internal/assets/shaders/point_light.frag")
+	var err error
+	s.lightingShader, err = shader.NewFS(assets.FS, "shaders/point_light.vert", "shaders/point_light.frag")
 	if err != nil {
-		panic(err)
+		return err
 	}
 
-	lightCubeShader, err := shader.New("internal/assets/shaders/light_colors_cube.vert", "internal/assets/shaders/light_colors_cube.frag")
+	s.lightCubeShader, err = shader.NewFS(assets.FS, "shaders/light_colors_cube.vert", "shaders/light_colors_cube.frag")
 	if err != nil {
-		panic(err)
+		return err
 	}
 
 	var vertices = []float32{
@@ -130,63 +103,42 @@ func (s PointLight) Show() {
 	}
 
 	// First, configure the cubes's VAO and VBO
-	var cubeVAO uint32
-	gl.GenVertexArrays(1, &cubeVAO)
+	gl.GenVertexArrays(1, &s.cubeVAO)
 
-	var vbo uint32
-	gl.GenBuffers(1, &vbo)
-	gl.BindBuffer(gl.ARRAY_BUFFER, vbo)
+	gl.GenBuffers(1, &s.vbo)
+	gl.BindBuffer(gl.ARRAY_BUFFER, s.vbo)
 	gl.BufferData(gl.ARRAY_BUFFER, len(vertices)*floatSize, gl.Ptr(vertices), gl.STATIC_DRAW)
 
-	gl.BindVertexArray(cubeVAO)
+	gl.BindVertexArray(s.cubeVAO)
 
-	// Position attribute
-	gl.VertexAttribPointer(0, 3, gl.FLOAT, false, 8*floatSize, nil)
-	gl.EnableVertexAttribArray(0)
-	// Normal attribute
-	gl.VertexAttribPointerWithOffset(1, 3, gl.FLOAT, false, 8*floatSize, 3*floatSize)
-	gl.EnableVertexAttribArray(1)
-	// Texture Coord attribute
-	gl.VertexAttribPointerWithOffset(2, 2, gl.FLOAT, false, 8*floatSize, 6*floatSize)
-	gl.EnableVertexAttribArray(2)
+	// Position, normal and texture coord attributes
+	vertex.Floats(3, 3, 2).Apply()
 
 	// Second, configure the light's VAO
 	// (VBO stays the same. The vertices are the same for the light object wich is also a 3D cube)
-	var lightCubeVAO uint32
-	gl.GenVertexArrays(1, &lightCubeVAO)
-	gl.BindVertexArray(lightCubeVAO)
+	gl.GenVertexArrays(1, &s.lightCubeVAO)
+	gl.BindVertexArray(s.lightCubeVAO)
 
 	// We only need to bind to the VBO (to link it with glVertexAttribPointer), no need to fill it;
 	// The VBO's data already contains all we need (it's already bound, but we do it again for educational purposes)
-	gl.BindBuffer(gl.ARRAY_BUFFER, vbo)
+	gl.BindBuffer(gl.ARRAY_BUFFER, s.vbo)
 
 	// Position attribute
-	gl.VertexAttribPointer(0, 3, gl.FLOAT, false, 8*floatSize, nil)
-	gl.EnableVertexAttribArray(0)
+	vertex.Floats(3, 3, 2).Only(0).Apply()
 
-	diffuseMapTex, err := texture.New("internal/assets/textures/woodbox.png", gl.TEXTURE_2D, gl.TEXTURE0, gl.RGBA, gl.RGBA, gl.UNSIGNED_INT)
+	s.diffuseMapTex, err = texture.NewFS(assets.FS, "textures/woodbox.png", gl.TEXTURE0, texture.DefaultOptions)
 	if err != nil {
-		panic(err)
+		return err
 	}
 
-	specularMapTex, err := texture.New("internal/assets/textures/woodbox_specular.png", gl.TEXTURE_2D, gl.TEXTURE1, gl.RGBA, gl.RGBA, gl.UNSIGNED_INT)
+	s.specularMapTex, err = texture.NewFS(assets.FS, "textures/woodbox_specular.png", gl.TEXTURE1, texture.DefaultOptions)
 	if err != nil {
-		panic(err)
+		return err
 	}
 
-	// Clean up all resources
-	defer func() {
-		gl.DeleteVertexArrays(1, &cubeVAO)
-		gl.DeleteBuffers(1, &vbo)
-		lightingShader.Delete()
-		lightCubeShader.Delete()
-		diffuseMapTex.Delete()
-		specularMapTex.Delete()
-	}()
-
 	gl.Enable(gl.DEPTH_TEST)
 
-	cubePositions := []mgl32.Vec3{
+	s.cubePositions = []mgl32.Vec3{
 		mgl32.Vec3{0.0, 0.0, 0.0},
 		mgl32.Vec3{2.0, 5.0, -15.0},
 		mgl32.Vec3{-1.5, -2.2, -2.5},
@@ -199,106 +151,87 @@ func (s PointLight) Show() {
 		mgl32.Vec3{-1.3, 1.0, -1.5},
 	}
 
-	// Main loop
-	for !window.ShouldClose() {
-		s.processInput(window)
-
-		gl.ClearColor(0.1, 0.1, 0.1, 1.0)
-		gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
-
-		currentFrame := glfw.GetTime()
-		s.deltaTime = currentFrame - s.lastFrame
-		s.lastFrame = currentFrame
-
-		lightPos := mgl32.Vec3{1.2, 1.0, 2.0}
-		lightColor := mgl32.Vec3{1.0, 1.0, 1.0}
-
-		viewMatrix := s.camera.ViewMatrix()
-		projectionMatrix := mgl32.Ident4()
-		projectionMatrix = mgl32.Perspective(mgl32.DegToRad(float32(s.camera.Fov)), width/height, 0.1, 100.0)
-
-		diffuseMapTex.ActiveAndBind()
-		specularMapTex.ActiveAndBind()
-
-		lightingShader.Use()
-		lightingShader.SetVec3("light.position", lightPos)
-		lightingShader.SetVec3("viewPos", s.camera.Position)
-
-		lightingShader.SetVec3f("light.ambient", 0.2, 0.2, 0.2)
-		lightingShader.SetVec3f("light.diffuse", 0.5, 0.5, 0.5)
-		lightingShader.SetVec3f("light.specular", 1.0, 1.0, 1.0)
-		lightingShader.SetFloat("light.constant", 1.0)
-		lightingShader.SetFloat("light.linear", 0.09)
-		lightingShader.SetFloat("light.quadratic", 0.032)
-
-		lightingShader.SetInt("material.diffuse", 0)
-		lightingShader.SetInt("material.specular", 1)
-		lightingShader.SetFloat("material.shininess", 32.0)
-
-		lightingShader.SetMat4("view", viewMatrix)
-		lightingShader.SetMat4("projection", projectionMatrix)
-
-		// Render the cubes
-		gl.BindVertexArray(cubeVAO)
-		for i, pos := range cubePositions {
-			modelMatrix := mgl32.Ident4()
-
-			translate := mgl32.Translate3D(pos.X(), pos.Y(), pos.Z())
-			angle := mgl32.DegToRad(20.0 * float32(i))
-			rotateX := mgl32.HomogRotate3D(angle, mgl32.Vec3{1.0, 0.0, 0.0})
-			rotateY := mgl32.HomogRotate3D(angle, mgl32.Vec3{0.0, 1.0, 0.0})
-			rotateZ := mgl32.HomogRotate3D(angle, mgl32.Vec3{0.0, 0.0, 1.0})
-
-			modelMatrix = modelMatrix.Mul4(translate)
-			modelMatrix = modelMatrix.Mul4(rotateX)
-			modelMatrix = modelMatrix.Mul4(rotateY)
-			modelMatrix = modelMatrix.Mul4(rotateZ)
-
-			lightingShader.SetMat4("model", modelMatrix)
-			gl.DrawArrays(gl.TRIANGLES, 0, 36)
-		}
-
-		// Now draw the cube "lamp"
-		lightCubeShader.Use()
-		lightCubeShader.SetMat4("projection", projectionMatrix)
-		lightCubeShader.SetMat4("view", viewMatrix)
-		lightCubeShader.SetVec3("lightColor", lightColor)
-
-		modelMatrix := mgl32.Ident4()
-		translate := mgl32.Translate3D(lightPos.X(), lightPos.Y(), lightPos.Z())
-		modelMatrix = modelMatrix.Mul4(translate)
-		scale := mgl32.Scale3D(0.2, 0.2, 0.2)
-		modelMatrix = modelMatrix.Mul4(scale)
-		lightCubeShader.SetMat4("model", modelMatrix)
-
-		gl.BindVertexArray(lightCubeVAO)
-		gl.DrawArrays(gl.TRIANGLES, 0, 36)
-
-		window.SwapBuffers()
-		glfw.PollEvents()
+	s.light.Position = mgl32.Vec3{1.2, 1.0, 2.0}
+	s.light.Colors = light.NewColors(mgl32.Vec3{1.0, 1.0, 1.0}, 0.2, 0.5)
+	s.light.Attenuation = light.Range(50)
+	shadowOpts := shadow.DefaultOptions
+	// Each face is rendered, so they are smaller than a directional shadow map
+	shadowOpts.Size = 1024
+	s.light.Shadow, err = shadow.NewCubeMap(shadowOpts)
+	if err != nil {
+		return err
 	}
+
+	return nil
 }
 
-func (s *PointLight) processInput(w *glfw.Window) {
-	processInput(w, s)
-	processCameraKeyboardInput(w, s.camera, s.deltaTime)
+func (s *PointLight) Update(w *glfw.Window, deltaTime float64) {
+	s.processInput(w, deltaTime)
 }
 
-func (s *PointLight) mouseCallback(w *glfw.Window, xpos, ypos float64) {
-	if s.firstMouse {
-		s.lastX = xpos
-		s.lastY = ypos
-		s.firstMouse = false
-	}
+func (s *PointLight) Render(time float64) {
+	// First, render the distance from the light to the cubes in every direction
+	s.light.RenderShadow(func(depth *shader.Shader) {
+		drawCubes(depth, s.cubeVAO, s.cubePositions, nil)
+	})
+
+	gl.ClearColor(0.1, 0.1, 0.1, 1.0)
+	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+
+	lightPos := s.light.Position
+	lightColor := s.light.Specular
+
+	viewMatrix := s.camera.ViewMatrix()
+	projectionMatrix := s.camera.ProjectionMatrix()
+
+	s.diffuseMapTex.ActiveAndBind()
+	s.specularMapTex.ActiveAndBind()
+
+	s.lightingShader.Use()
+	s.lightingShader.SetVec3("viewPos", s.camera.Position)
+	s.light.Upload(s.lightingShader, "light")
+
+	s.lightingShader.SetInt("material.diffuse", 0)
+	s.lightingShader.SetInt("material.specular", 1)
+	s.lightingShader.SetFloat("material.shininess", 32.0)
 
-	xoffset := xpos - s.lastX
-	yoffset := s.lastY - ypos
-	s.lastX = xpos
-	s.lastY = ypos
+	s.light.Shadow.Bind(s.lightingShader, "shadow", 2)
+
+	s.lightingShader.SetMat4("view", viewMatrix)
+	s.lightingShader.SetMat4("projection", projectionMatrix)
+
+	// Render the cubes
+	drawCubes(s.lightingShader, s.cubeVAO, s.cubePositions, s.cull())
+
+	// Now draw the cube "lamp"
+	s.lightCubeShader.Use()
+	s.lightCubeShader.SetMat4("projection", projectionMatrix)
+	s.lightCubeShader.SetMat4("view", viewMatrix)
+	s.lightCubeShader.SetVec3("lightColor", lightColor)
+
+	modelMatrix := mgl32.Ident4()
+	translate := mgl32.Translate3D(lightPos.X(), lightPos.Y(), lightPos.Z())
+	modelMatrix = modelMatrix.Mul4(translate)
+	scale := mgl32.Scale3D(0.2, 0.2, 0.2)
+	modelMatrix = modelMatrix.Mul4(scale)
+	s.lightCubeShader.SetMat4("model", modelMatrix)
+
+	gl.BindVertexArray(s.lightCubeVAO)
+	gl.DrawArrays(gl.TRIANGLES, 0, 36)
+}
 
-	s.camera.ProcessMouseMovement(xoffset, yoffset, true)
+func (s *PointLight) Resize(width, height int) {
+	s.resize(width, height)
 }
 
-func (s *PointLight) mouseScrollCallback(w *glfw.Window, xoff, yoff float64) {
-	s.camera.ProcessMouseScroll(yoff)
+// Destroy cleans up all resources
+func (s *PointLight) Destroy() {
+	gl.DeleteVertexArrays(1, &s.cubeVAO)
+	gl.DeleteVertexArrays(1, &s.lightCubeVAO)
+	gl.DeleteBuffers(1, &s.vbo)
+	s.lightingShader.Delete()
+	s.lightCubeShader.Delete()
+	s.diffuseMapTex.Delete()
+	s.specularMapTex.Delete()
+	s.light.Shadow.Delete()
 }
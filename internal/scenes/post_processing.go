@@ -0,0 +1,233 @@
+package scenes
+
+import (
+	"fmt"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/igoramorim/gopengl/internal/assets"
+	"github.com/igoramorim/gopengl/pkg/postprocess"
+	"github.com/igoramorim/gopengl/pkg/shader"
+	"github.com/igoramorim/gopengl/pkg/texture"
+	"github.com/igoramorim/gopengl/pkg/vertex"
+)
+
+// postProcessingKeys toggle the effects of the post_processing scene.
+var postProcessingKeys = []struct {
+	key    glfw.Key
+	effect postprocess.Effect
+}{
+	{glfw.Key1, postprocess.Inversion},
+	{glfw.Key2, postprocess.Grayscale},
+	{glfw.Key3, postprocess.Sharpen},
+	{glfw.Key4, postprocess.Blur},
+	{glfw.Key5, postprocess.EdgeDetection},
+}
+
+func NewPostProcessing() *PostProcessing {
+	return &PostProcessing{
+		cameraControls: newCameraControls(),
+		pressed:        make(map[glfw.Key]bool),
+	}
+}
+
+type PostProcessing struct {
+	cameraControls
+	shader       *shader.Shader
+	chain        *postprocess.Chain
+	cubeTexture  *texture.Texture
+	floorTexture *texture.Texture
+	cubeVAO      uint32
+	cubeVBO      uint32
+	planeVAO     uint32
+	planeVBO     uint32
+	// pressed holds the keys that were down on the last frame, so holding a key
+	// toggles its effect only once
+	pressed map[glfw.Key]bool
+}
+
+func (s PostProcessing) Name() string {
+	return "post_processing"
+}
+
+func (s PostProcessing) Width() int {
+	return width
+}
+
+func (s PostProcessing) Height() int {
+	return height
+}
+
+func (s *PostProcessing) Init(w *glfw.Window) error {
+	s.attach(w)
+
+	var err error
+	s.shader, err = shader.NewFS(assets.FS, "shaders/post_processing.vert", "shaders/post_processing.frag")
+	if err != nil {
+		return err
+	}
+
+	s.chain, err = postprocess.New(width, height)
+	if err != nil {
+		return err
+	}
+
+	var cubeVertices = []float32{
+		// x y z u v (tex coord)
+		-0.5, -0.5, -0.5, 0.0, 0.0,
+		0.5, -0.5, -0.5, 1.0, 0.0,
+		0.5, 0.5, -0.5, 1.0, 1.0,
+		0.5, 0.5, -0.5, 1.0, 1.0,
+		-0.5, 0.5, -0.5, 0.0, 1.0,
+		-0.5, -0.5, -0.5, 0.0, 0.0,
+
+		-0.5, -0.5, 0.5, 0.0, 0.0,
+		0.5, -0.5, 0.5, 1.0, 0.0,
+		0.5, 0.5, 0.5, 1.0, 1.0,
+		0.5, 0.5, 0.5, 1.0, 1.0,
+		-0.5, 0.5, 0.5, 0.0, 1.0,
+		-0.5, -0.5, 0.5, 0.0, 0.0,
+
+		-0.5, 0.5, 0.5, 1.0, 0.0,
+		-0.5, 0.5, -0.5, 1.0, 1.0,
+		-0.5, -0.5, -0.5, 0.0, 1.0,
+		-0.5, -0.5, -0.5, 0.0, 1.0,
+		-0.5, -0.5, 0.5, 0.0, 0.0,
+		-0.5, 0.5, 0.5, 1.0, 0.0,
+
+		0.5, 0.5, 0.5, 1.0, 0.0,
+		0.5, 0.5, -0.5, 1.0, 1.0,
+		0.5, -0.5, -0.5, 0.0, 1.0,
+		0.5, -0.5, -0.5, 0.0, 1.0,
+		0.5, -0.5, 0.5, 0.0, 0.0,
+		0.5, 0.5, 0.5, 1.0, 0.0,
+
+		-0.5, -0.5, -0.5, 0.0, 1.0,
+		0.5, -0.5, -0.5, 1.0, 1.0,
+		0.5, -0.5, 0.5, 1.0, 0.0,
+		0.5, -0.5, 0.5, 1.0, 0.0,
+		-0.5, -0.5, 0.5, 0.0, 0.0,
+		-0.5, -0.5, -0.5, 0.0, 1.0,
+
+		-0.5, 0.5, -0.5, 0.0, 1.0,
+		0.5, 0.5, -0.5, 1.0, 1.0,
+		0.5, 0.5, 0.5, 1.0, 0.0,
+		0.5, 0.5, 0.5, 1.0, 0.0,
+		-0.5, 0.5, 0.5, 0.0, 0.0,
+		-0.5, 0.5, -0.5, 0.0, 1.0,
+	}
+
+	var planeVertices = []float32{
+		// x y z  uv (tex coords) (note we set these higher than 1 (together with GL_REPEAT as texture wrapping mode). this will cause the floor texture to repeat)
+		5.0, -0.5, 5.0, 2.0, 0.0,
+		-5.0, -0.5, 5.0, 0.0, 0.0,
+		-5.0, -0.5, -5.0, 0.0, 2.0,
+		5.0, -0.5, 5.0, 2.0, 0.0,
+		-5.0, -0.5, -5.0, 0.0, 2.0,
+		5.0, -0.5, -5.0, 2.0, 2.0,
+	}
+
+	// Cube
+	gl.GenVertexArrays(1, &s.cubeVAO)
+	gl.GenBuffers(1, &s.cubeVBO)
+	gl.BindVertexArray(s.cubeVAO)
+	gl.BindBuffer(gl.ARRAY_BUFFER, s.cubeVBO)
+	gl.BufferData(gl.ARRAY_BUFFER, len(cubeVertices)*floatSize, gl.Ptr(cubeVertices), gl.STATIC_DRAW)
+	// Position and texture coord attributes
+	vertex.Floats(3, 2).Apply()
+	gl.BindVertexArray(0)
+
+	// Plane
+	gl.GenVertexArrays(1, &s.planeVAO)
+	gl.GenBuffers(1, &s.planeVBO)
+	gl.BindVertexArray(s.planeVAO)
+	gl.BindBuffer(gl.ARRAY_BUFFER, s.planeVBO)
+	gl.BufferData(gl.ARRAY_BUFFER, len(planeVertices)*floatSize, gl.Ptr(planeVertices), gl.STATIC_DRAW)
+	// Position and texture coord attributes
+	vertex.Floats(3, 2).Apply()
+	gl.BindVertexArray(0)
+
+	// Textures
+	s.cubeTexture, err = texture.NewFS(assets.FS, "textures/container.jpg", gl.TEXTURE0, texture.DefaultOptions)
+	if err != nil {
+		return err
+	}
+
+	s.floorTexture, err = texture.NewFS(assets.FS, "textures/metal.png", gl.TEXTURE0, texture.DefaultOptions)
+	if err != nil {
+		return err
+	}
+
+	gl.Enable(gl.DEPTH_TEST)
+
+	fmt.Println("post_processing: press 1 to 5 to toggle inversion, grayscale, sharpen, blur and edge detection")
+
+	return nil
+}
+
+func (s *PostProcessing) Update(w *glfw.Window, deltaTime float64) {
+	s.processInput(w, deltaTime)
+
+	for _, k := range postProcessingKeys {
+		down := w.GetKey(k.key) == glfw.Press
+		if down && !s.pressed[k.key] {
+			s.chain.Toggle(k.effect)
+			fmt.Printf("post_processing: %s %t\n", k.effect, s.chain.Enabled(k.effect))
+		}
+		s.pressed[k.key] = down
+	}
+}
+
+func (s *PostProcessing) Render(time float64) {
+	// The scene is drawn to the chain framebuffer instead of the window
+	s.chain.Begin()
+
+	gl.ClearColor(0.1, 0.1, 0.1, 1.0)
+	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+
+	viewMatrix := s.camera.ViewMatrix()
+	projectionMatrix := s.camera.ProjectionMatrix()
+
+	s.shader.Use()
+	s.shader.SetMat4("view", viewMatrix)
+	s.shader.SetMat4("projection", projectionMatrix)
+	s.shader.SetInt("texture0", 0)
+
+	// Floor
+	s.floorTexture.ActiveAndBind()
+	gl.BindVertexArray(s.planeVAO)
+	s.shader.SetMat4("model", mgl32.Ident4())
+	gl.DrawArrays(gl.TRIANGLES, 0, 6)
+
+	// Cubes
+	s.cubeTexture.ActiveAndBind()
+	gl.BindVertexArray(s.cubeVAO)
+	s.shader.SetMat4("model", mgl32.Translate3D(-1.0, 0.0, -1.0))
+	gl.DrawArrays(gl.TRIANGLES, 0, 36)
+	s.shader.SetMat4("model", mgl32.Translate3D(2.0, 0.0, 0.0))
+	gl.DrawArrays(gl.TRIANGLES, 0, 36)
+	gl.BindVertexArray(0)
+
+	// Applies the enabled effects and draws the result to the window
+	s.chain.End()
+}
+
+func (s *PostProcessing) Resize(width, height int) {
+	s.resize(width, height)
+	if err := s.chain.Resize(width, height); err != nil {
+		fmt.Println(err.Error())
+	}
+}
+
+// Destroy cleans up all resources
+func (s *PostProcessing) Destroy() {
+	gl.DeleteVertexArrays(1, &s.cubeVAO)
+	gl.DeleteBuffers(1, &s.cubeVBO)
+	gl.DeleteVertexArrays(1, &s.planeVAO)
+	gl.DeleteBuffers(1, &s.planeVBO)
+	s.shader.Delete()
+	s.chain.Delete()
+	s.cubeTexture.Delete()
+	s.floorTexture.Delete()
+}
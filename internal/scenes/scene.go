@@ -1,8 +1,9 @@
 package scenes
 
+import "github.com/igoramorim/gopengl/internal/app"
+
+// Scene is implemented by every scene of this package. The window, the OpenGL
+// context and the main loop are owned by app.Run, which drives the scene lifecycle.
 type Scene interface {
-	Name() string
-	Width() int
-	Height() int
-	Show()
+	app.Scene
 }
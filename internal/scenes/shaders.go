@@ -2,15 +2,19 @@ package scenes
 
 import (
 	"fmt"
-	"log"
 	"math"
 	"strings"
 
 	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/igoramorim/gopengl/pkg/vertex"
 )
 
-type Shaders struct{}
+type Shaders struct {
+	shaderProgram uint32
+	vao           uint32
+	vbo           uint32
+}
 
 func (s Shaders) Name() string {
 	return "shaders_uniforms"
@@ -24,33 +28,7 @@ func (s Shaders) Height() int {
 	return height
 }
 
-func (s Shaders) Show() {
-	if err := glfw.Init(); err != nil {
-		log.Fatalln("initialize glfw:", err)
-	}
-	defer glfw.Terminate()
-
-	glfw.WindowHint(glfw.Resizable, glfw.True)
-	glfw.WindowHint(glfw.ContextVersionMajor, 4)
-	glfw.WindowHint(glfw.ContextVersionMinor, 1)
-	glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
-	glfw.WindowHint(glfw.OpenGLForwardCompatible, glfw.True)
-
-	window, err := glfw.CreateWindow(s.Width(), s.Height(), s.Name(), nil, nil)
-	if err != nil {
-		panic(err)
-	}
-	window.MakeContextCurrent()
-
-	window.SetFramebufferSizeCallback(frameBufferSizeCallback)
-
-	if err := gl.Init(); err != nil {
-		panic(err)
-	}
-
-	version := gl.GoStr(gl.GetString(gl.VERSION))
-	fmt.Println("OpenGL version:", version)
-
+func (s *Shaders) Init(w *glfw.Window) error {
 	vertexShader := gl.CreateShader(gl.VERTEX_SHADER)
 	vertexShaderCSource, free := gl.Strs(s.vertexShaderSource())
 	gl.ShaderSource(vertexShader, 1, vertexShaderCSource, nil)
@@ -66,7 +44,7 @@ func (s Shaders) Show() {
 		log := strings.Repeat("\x00", int(logLength+1))
 		gl.GetShaderInfoLog(vertexShader, logLength, nil, gl.Str(log))
 
-		panic(fmt.Sprintf("compile shader source %s\n %s\n", s.vertexShaderSource(), log))
+		return fmt.Errorf("compile shader source %s\n %s\n", s.vertexShaderSource(), log)
 	}
 
 	fragmentShader := gl.CreateShader(gl.FRAGMENT_SHADER)
@@ -83,23 +61,23 @@ func (s Shaders) Show() {
 		log := strings.Repeat("\x00", int(logLength+1))
 		gl.GetShaderInfoLog(fragmentShader, logLength, nil, gl.Str(log))
 
-		panic(fmt.Sprintf("compile shader source %s\n %s\n", s.fragmentShaderSource(), log))
+		return fmt.Errorf("compile shader source %s\n %s\n", s.fragmentShaderSource(), log)
 	}
 
-	shaderProgram := gl.CreateProgram()
-	gl.AttachShader(shaderProgram, vertexShader)
-	gl.AttachShader(shaderProgram, fragmentShader)
-	gl.LinkProgram(shaderProgram)
+	s.shaderProgram = gl.CreateProgram()
+	gl.AttachShader(s.shaderProgram, vertexShader)
+	gl.AttachShader(s.shaderProgram, fragmentShader)
+	gl.LinkProgram(s.shaderProgram)
 
-	gl.GetProgramiv(shaderProgram, gl.LINK_STATUS, &status)
+	gl.GetProgramiv(s.shaderProgram, gl.LINK_STATUS, &status)
 	if status == gl.FALSE {
 		var logLength int32
-		gl.GetProgramiv(shaderProgram, gl.INFO_LOG_LENGTH, &logLength)
+		gl.GetProgramiv(s.shaderProgram, gl.INFO_LOG_LENGTH, &logLength)
 
 		log := strings.Repeat("\x00", int(logLength+1))
-		gl.GetProgramInfoLog(shaderProgram, logLength, nil, gl.Str(log))
+		gl.GetProgramInfoLog(s.shaderProgram, logLength, nil, gl.Str(log))
 
-		panic(fmt.Sprintf("linking shader program %v\n", log))
+		return fmt.Errorf("linking shader program %v\n", log)
 	}
 
 	gl.DeleteShader(vertexShader)
@@ -112,48 +90,44 @@ func (s Shaders) Show() {
 		0.0, 0.5, 0.0, // top
 	}
 
-	var vao uint32
-	gl.GenVertexArrays(1, &vao)
-	gl.BindVertexArray(vao)
+	gl.GenVertexArrays(1, &s.vao)
+	gl.BindVertexArray(s.vao)
 
-	var vbo uint32
-	gl.GenBuffers(1, &vbo)
-	gl.BindBuffer(gl.ARRAY_BUFFER, vbo)
+	gl.GenBuffers(1, &s.vbo)
+	gl.BindBuffer(gl.ARRAY_BUFFER, s.vbo)
 	gl.BufferData(gl.ARRAY_BUFFER, len(vertices)*floatSize, gl.Ptr(vertices), gl.STATIC_DRAW)
 
 	// Position attribute
-	gl.VertexAttribPointer(0, 3, gl.FLOAT, false, 3*floatSize, nil)
-	gl.EnableVertexAttribArray(0)
+	vertex.Floats(3).Apply()
 
-	// Clean up all resources
-	defer func() {
-		gl.DeleteVertexArrays(1, &vao)
-		gl.DeleteBuffers(1, &vbo)
-		gl.DeleteProgram(shaderProgram)
-	}()
+	return nil
+}
 
-	// Main loop
-	for !window.ShouldClose() {
-		processInput(window, s)
+func (s *Shaders) Update(w *glfw.Window, deltaTime float64) {}
 
-		gl.ClearColor(0.2, 0.3, 0.3, 1.0)
-		gl.Clear(gl.COLOR_BUFFER_BIT)
+func (s *Shaders) Render(time float64) {
+	gl.ClearColor(0.2, 0.3, 0.3, 1.0)
+	gl.Clear(gl.COLOR_BUFFER_BIT)
 
-		gl.UseProgram(shaderProgram)
+	gl.UseProgram(s.shaderProgram)
 
-		t := glfw.GetTime()
-		green := math.Sin(t)/2.0 + 0.5
+	green := math.Sin(time)/2.0 + 0.5
 
-		// Send the value to the shader uniform variable named 'color'
-		uniformLocation := gl.GetUniformLocation(shaderProgram, gl.Str("color\x00"))
-		gl.Uniform3f(uniformLocation, 0.0, float32(green), 0.0)
+	// Send the value to the shader uniform variable named 'color'
+	uniformLocation := gl.GetUniformLocation(s.shaderProgram, gl.Str("color\x00"))
+	gl.Uniform3f(uniformLocation, 0.0, float32(green), 0.0)
 
-		gl.BindVertexArray(vao)
-		gl.DrawArrays(gl.TRIANGLES, 0, 3)
+	gl.BindVertexArray(s.vao)
+	gl.DrawArrays(gl.TRIANGLES, 0, 3)
+}
 
-		window.SwapBuffers()
-		glfw.PollEvents()
-	}
+func (s *Shaders) Resize(width, height int) {}
+
+// Destroy cleans up all resources
+func (s *Shaders) Destroy() {
+	gl.DeleteVertexArrays(1, &s.vao)
+	gl.DeleteBuffers(1, &s.vbo)
+	gl.DeleteProgram(s.shaderProgram)
 }
 
 func (d Shaders) vertexShaderSource() string {
@@ -0,0 +1,152 @@
+package scenes
+
+import (
+	"fmt"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/igoramorim/gopengl/internal/assets"
+	"github.com/igoramorim/gopengl/pkg/animation"
+	"github.com/igoramorim/gopengl/pkg/model"
+	"github.com/igoramorim/gopengl/pkg/shader"
+)
+
+// skeletalAnimationFade is how long, in seconds, the clips blend into the next one.
+const skeletalAnimationFade = 0.5
+
+// skeletalAnimationSelect are the keys that play the clips of the model, 0 the rest pose
+// and 1 onwards the clips in order.
+var skeletalAnimationSelect = []glfw.Key{
+	glfw.Key0, glfw.Key1, glfw.Key2, glfw.Key3, glfw.Key4,
+	glfw.Key5, glfw.Key6, glfw.Key7, glfw.Key8, glfw.Key9,
+}
+
+func NewSkeletalAnimation() *SkeletalAnimation {
+	return &SkeletalAnimation{
+		cameraControls: newCameraControls(),
+	}
+}
+
+type SkeletalAnimation struct {
+	cameraControls
+	shader   *shader.Shader
+	model3D  *model.Model
+	animator *animation.Animator
+}
+
+func (s SkeletalAnimation) Name() string {
+	return "skeletal_animation"
+}
+
+func (s SkeletalAnimation) Width() int {
+	return width
+}
+
+func (s SkeletalAnimation) Height() int {
+	return height
+}
+
+func (s *SkeletalAnimation) Init(w *glfw.Window) error {
+	s.attach(w)
+	s.camera.Position = mgl32.Vec3{0.0, 0.0, 6.0}
+
+	var err error
+	s.model3D, err = model.NewGLTFFS(assets.FS, "models/skinned_tube/skinned_tube.gltf")
+	if err != nil {
+		return err
+	}
+
+	skeleton := s.model3D.Skeleton()
+	if skeleton == nil {
+		return fmt.Errorf("skeletal_animation: the model has no skeleton")
+	}
+	s.animator = animation.NewAnimator(skeleton)
+
+	// The array of bone matrices is as long as the skeleton needs
+	s.shader, err = shader.NewProgramFS(assets.FS,
+		shader.Vertex("shaders/skeletal_animation.vert").Define("MAX_BONES", max(len(skeleton.Bones), 1)),
+		shader.Fragment("shaders/skeletal_animation.frag"),
+	)
+	if err != nil {
+		return err
+	}
+
+	// The shader must take every bone, or the model would be drawn torn apart
+	s.shader.Use()
+	if err := s.animator.Upload(s.shader, "bones"); err != nil {
+		return fmt.Errorf("skeletal_animation: %w", err)
+	}
+
+	if clips := s.model3D.Clips(); len(clips) > 0 {
+		s.animator.Play(clips[0], 0)
+	}
+
+	gl.Enable(gl.DEPTH_TEST)
+
+	fmt.Printf("skeletal_animation: press 1 to %d to play a clip, 0 for the rest pose\n", len(s.model3D.Clips()))
+
+	return nil
+}
+
+func (s *SkeletalAnimation) Update(w *glfw.Window, deltaTime float64) {
+	s.processInput(w, deltaTime)
+
+	clips := s.model3D.Clips()
+	for i, key := range skeletalAnimationSelect {
+		if s.justPressed(w, key) && i <= len(clips) {
+			var clip *animation.Clip
+			if i > 0 {
+				clip = clips[i-1]
+			}
+			s.animator.Play(clip, skeletalAnimationFade)
+
+			if clip != nil {
+				fmt.Printf("skeletal_animation: playing %q\n", clip.Name)
+			} else {
+				fmt.Println("skeletal_animation: rest pose")
+			}
+		}
+	}
+
+	s.animator.Update(deltaTime)
+}
+
+func (s *SkeletalAnimation) Render(time float64) {
+	gl.ClearColor(0.1, 0.1, 0.1, 1.0)
+	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+
+	s.shader.Use()
+
+	viewMatrix := s.camera.ViewMatrix()
+	projectionMatrix := s.camera.ProjectionMatrix()
+	s.shader.SetMat4("view", viewMatrix)
+	s.shader.SetMat4("projection", projectionMatrix)
+
+	// The tube stands on the origin, it is lowered to the center of the screen
+	modelMatrix := mgl32.Translate3D(0.0, -2.25, 0.0)
+	s.shader.SetMat4("model", modelMatrix)
+
+	s.shader.SetVec3("viewPos", s.camera.Position)
+	s.shader.SetVec3f("light.direction", -0.5, -0.3, -1.0)
+	s.shader.SetVec3f("light.ambient", 0.2, 0.2, 0.2)
+	s.shader.SetVec3f("light.diffuse", 0.8, 0.8, 0.8)
+	s.shader.SetVec3f("light.specular", 1.0, 1.0, 1.0)
+
+	if err := s.animator.Upload(s.shader, "bones"); err != nil {
+		// A reloaded shader without the bones, printed once by the shader
+		return
+	}
+
+	s.model3D.Draw(s.shader)
+}
+
+func (s *SkeletalAnimation) Resize(width, height int) {
+	s.resize(width, height)
+}
+
+// Destroy cleans up all resources
+func (s *SkeletalAnimation) Destroy() {
+	s.shader.Delete()
+	s.model3D.Delete()
+}
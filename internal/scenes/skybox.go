@@ -0,0 +1,196 @@
+package scenes
+
+import (
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/igoramorim/gopengl/internal/assets"
+	"github.com/igoramorim/gopengl/pkg/shader"
+	"github.com/igoramorim/gopengl/pkg/skybox"
+	"github.com/igoramorim/gopengl/pkg/texture"
+	"github.com/igoramorim/gopengl/pkg/vertex"
+)
+
+// glassRefraction is the ratio between the refractive indices of air and glass.
+const glassRefraction = 1.00 / 1.52
+
+func NewSkybox() *Skybox {
+	return &Skybox{
+		cameraControls: newCameraControls(),
+	}
+}
+
+type Skybox struct {
+	cameraControls
+	shader        *shader.Shader
+	cubemap       *texture.Texture
+	skybox        *skybox.Skybox
+	vao           uint32
+	vbo           uint32
+	cubePositions []mgl32.Vec3
+}
+
+func (s Skybox) Name() string {
+	return "skybox"
+}
+
+func (s Skybox) Width() int {
+	return width
+}
+
+func (s Skybox) Height() int {
+	return height
+}
+
+func (s *Skybox) Init(w *glfw.Window) error {
+	s.attach(w)
+
+	var err error
+	s.shader, err = shader.NewFS(assets.FS, "shaders/environment_mapping.vert", "shaders/environment_mapping.frag")
+	if err != nil {
+		return err
+	}
+
+	s.cubemap, err = texture.NewCubemapFS(assets.FS, [6]string{
+		"textures/skybox/right.jpg",
+		"textures/skybox/left.jpg",
+		"textures/skybox/top.jpg",
+		"textures/skybox/bottom.jpg",
+		"textures/skybox/front.jpg",
+		"textures/skybox/back.jpg",
+	}, gl.TEXTURE0, texture.CubemapOptions)
+	if err != nil {
+		return err
+	}
+
+	s.skybox, err = skybox.New(s.cubemap)
+	if err != nil {
+		return err
+	}
+
+	var vertices = []float32{
+		// x y z          normals
+		-0.5, -0.5, -0.5, 0.0, 0.0, -1.0,
+		0.5, -0.5, -0.5, 0.0, 0.0, -1.0,
+		0.5, 0.5, -0.5, 0.0, 0.0, -1.0,
+		0.5, 0.5, -0.5, 0.0, 0.0, -1.0,
+		-0.5, 0.5, -0.5, 0.0, 0.0, -1.0,
+		-0.5, -0.5, -0.5, 0.0, 0.0, -1.0,
+
+		-0.5, -0.5, 0.5, 0.0, 0.0, 1.0,
+		0.5, -0.5, 0.5, 0.0, 0.0, 1.0,
+		0.5, 0.5, 0.5, 0.0, 0.0, 1.0,
+		0.5, 0.5, 0.5, 0.0, 0.0, 1.0,
+		-0.5, 0.5, 0.5, 0.0, 0.0, 1.0,
+		-0.5, -0.5, 0.5, 0.0, 0.0, 1.0,
+
+		-0.5, 0.5, 0.5, -1.0, 0.0, 0.0,
+		-0.5, 0.5, -0.5, -1.0, 0.0, 0.0,
+		-0.5, -0.5, -0.5, -1.0, 0.0, 0.0,
+		-0.5, -0.5, -0.5, -1.0, 0.0, 0.0,
+		-0.5, -0.5, 0.5, -1.0, 0.0, 0.0,
+		-0.5, 0.5, 0.5, -1.0, 0.0, 0.0,
+
+		0.5, 0.5, 0.5, 1.0, 0.0, 0.0,
+		0.5, 0.5, -0.5, 1.0, 0.0, 0.0,
+		0.5, -0.5, -0.5, 1.0, 0.0, 0.0,
+		0.5, -0.5, -0.5, 1.0, 0.0, 0.0,
+		0.5, -0.5, 0.5, 1.0, 0.0, 0.0,
+		0.5, 0.5, 0.5, 1.0, 0.0, 0.0,
+
+		-0.5, -0.5, -0.5, 0.0, -1.0, 0.0,
+		0.5, -0.5, -0.5, 0.0, -1.0, 0.0,
+		0.5, -0.5, 0.5, 0.0, -1.0, 0.0,
+		0.5, -0.5, 0.5, 0.0, -1.0, 0.0,
+		-0.5, -0.5, 0.5, 0.0, -1.0, 0.0,
+		-0.5, -0.5, -0.5, 0.0, -1.0, 0.0,
+
+		-0.5, 0.5, -0.5, 0.0, 1.0, 0.0,
+		0.5, 0.5, -0.5, 0.0, 1.0, 0.0,
+		0.5, 0.5, 0.5, 0.0, 1.0, 0.0,
+		0.5, 0.5, 0.5, 0.0, 1.0, 0.0,
+		-0.5, 0.5, 0.5, 0.0, 1.0, 0.0,
+		-0.5, 0.5, -0.5, 0.0, 1.0, 0.0,
+	}
+
+	gl.GenVertexArrays(1, &s.vao)
+
+	gl.GenBuffers(1, &s.vbo)
+	gl.BindBuffer(gl.ARRAY_BUFFER, s.vbo)
+	gl.BufferData(gl.ARRAY_BUFFER, len(vertices)*floatSize, gl.Ptr(vertices), gl.STATIC_DRAW)
+
+	gl.BindVertexArray(s.vao)
+
+	// Position and normal attributes
+	vertex.Floats(3, 3).Apply()
+
+	gl.Enable(gl.DEPTH_TEST)
+
+	// Same cubes of the camera scene
+	s.cubePositions = []mgl32.Vec3{
+		mgl32.Vec3{0.0, 0.0, 0.0},
+		mgl32.Vec3{2.0, 5.0, -15.0},
+		mgl32.Vec3{-2.0, 5.0, -15.0},
+		mgl32.Vec3{2.0, -5.0, -15.0},
+		mgl32.Vec3{-2.0, -5.0, -15.0},
+		mgl32.Vec3{2.0, 2.5, -5.0},
+		mgl32.Vec3{-2.0, 2.5, -5.0},
+		mgl32.Vec3{2.0, -2.5, -5.0},
+		mgl32.Vec3{-2.0, -2.5, -5.0},
+	}
+
+	return nil
+}
+
+func (s *Skybox) Update(w *glfw.Window, deltaTime float64) {
+	s.processInput(w, deltaTime)
+}
+
+func (s *Skybox) Render(time float64) {
+	gl.ClearColor(0.1, 0.1, 0.1, 1.0)
+	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+
+	viewMatrix := s.camera.ViewMatrix()
+	projectionMatrix := s.camera.ProjectionMatrix()
+
+	s.shader.Use()
+	s.shader.SetMat4("view", viewMatrix)
+	s.shader.SetMat4("projection", projectionMatrix)
+	s.shader.SetVec3("viewPos", s.camera.Position)
+	s.shader.SetInt("skybox", 0)
+
+	s.cubemap.ActiveAndBind()
+	gl.BindVertexArray(s.vao)
+
+	for i, pos := range s.cubePositions {
+		modelMatrix := mgl32.Translate3D(pos.X(), pos.Y(), pos.Z())
+		angle := float32(time) * mgl32.DegToRad(20.0*float32(i+1))
+		modelMatrix = modelMatrix.Mul4(mgl32.HomogRotate3D(angle, mgl32.Vec3{1.0, 0.3, 0.5}.Normalize()))
+		s.shader.SetMat4("model", modelMatrix)
+
+		// Even cubes are mirrors and odd cubes are made of glass
+		var refraction float32
+		if i%2 == 1 {
+			refraction = glassRefraction
+		}
+		s.shader.SetFloat("refraction", refraction)
+
+		gl.DrawArrays(gl.TRIANGLES, 0, 36)
+	}
+
+	// The skybox is drawn last, only where the cubes are not
+	s.skybox.Draw(viewMatrix, projectionMatrix)
+}
+
+func (s *Skybox) Resize(width, height int) {
+	s.resize(width, height)
+}
+
+// Destroy cleans up all resources
+func (s *Skybox) Destroy() {
+	gl.DeleteVertexArrays(1, &s.vao)
+	gl.DeleteBuffers(1, &s.vbo)
+	s.skybox.Delete()
+	s.cubemap.Delete()
+	s.shader.Delete()
+}
@@ -1,36 +1,33 @@
 package scenes
 
 import (
-	"fmt"
-	"log"
-	"math"
-
 	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/glfw/v3.3/glfw"
 	"github.com/go-gl/mathgl/mgl32"
-	"github.com/igoramorim/gopengl/pkg/camera"
+	"github.com/igoramorim/gopengl/internal/assets"
+	"github.com/igoramorim/gopengl/pkg/light"
 	"github.com/igoramorim/gopengl/pkg/shader"
+	"github.com/igoramorim/gopengl/pkg/shadow"
 	"github.com/igoramorim/gopengl/pkg/texture"
+	"github.com/igoramorim/gopengl/pkg/vertex"
 )
 
-func NewSpotLight() SpotLight {
-	return SpotLight{
-		camera:     camera.New(),
-		firstMouse: true,
-		lastX:      float64(width) / 2,
-		lastY:      float64(height) / 2,
-		deltaTime:  0.0,
-		lastFrame:  0.0,
+func NewSpotLight() *SpotLight {
+	return &SpotLight{
+		cameraControls: newCameraControls(),
 	}
 }
 
 type SpotLight struct {
-	camera     *camera.Camera
-	firstMouse bool
-	lastX      float64
-	lastY      float64
-	deltaTime  float64 // Time between current frame and last frame
-	lastFrame  float64
+	cameraControls
+	lightingShader *shader.Shader
+	diffuseMapTex  *texture.Texture
+	specularMapTex *texture.Texture
+	cubeVAO        uint32
+	vbo            uint32
+	lightCubeVAO   uint32
+	cubePositions  []mgl32.Vec3
+	light          light.SpotLight
 }
 
 func (s SpotLight) Name() string {
@@ -45,39 +42,13 @@ func (s SpotLight) Height() int {
 	return height
 }
 
-func (s SpotLight) Show() {
-	if err := glfw.Init(); err != nil {
-		log.Fatalln("initialize glfw:", err)
-	}
-	defer glfw.Terminate()
-
-	glfw.WindowHint(glfw.Resizable, glfw.True)
-	glfw.WindowHint(glfw.ContextVersionMajor, 4)
-	glfw.WindowHint(glfw.ContextVersionMinor, 1)
-	glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
-	glfw.WindowHint(glfw.OpenGLForwardCompatible, glfw.True)
-
-	window, err := glfw.CreateWindow(s.Width(), s.Height(), s.Name(), nil, nil)
-	if err != nil {
-		panic(err)
-	}
-	window.MakeContextCurrent()
-
-	window.SetFramebufferSizeCallback(frameBufferSizeCallback)
-	window.SetCursorPosCallback(s.mouseCallback)
-	window.SetScrollCallback(s.mouseScrollCallback)
-	window.SetInputMode(glfw.CursorMode, glfw.CursorDisabled)
-
-	if err := gl.Init(); err != nil {
-		panic(err)
-	}
-
-	version := gl.GoStr(gl.GetString(gl.VERSION))
-	fmt.Println("OpenGL version:", version)
+func (s *SpotLight) Init(w *glfw.Window) error {
+	s.attach(w)
 
-	lightingShader, err := shader.New("internal/assets/shaders/spotlight.vert", "internal/assets/shaders/spotlight.frag")
+	var err error
+	s.lightingShader, err = shader.NewFS(assets.FS, "shaders/spotlight.vert", "shaders/spotlight.frag")
 	if err != nil {
-		panic(err)
+		return err
 	}
 
 	var vertices = []float32{
@@ -126,62 +97,42 @@ func (s SpotLight) Show() {
 	}
 
 	// First, configure the cubes's VAO and VBO
-	var cubeVAO uint32
-	gl.GenVertexArrays(1, &cubeVAO)
+	gl.GenVertexArrays(1, &s.cubeVAO)
 
-	var vbo uint32
-	gl.GenBuffers(1, &vbo)
-	gl.BindBuffer(gl.ARRAY_BUFFER, vbo)
+	gl.GenBuffers(1, &s.vbo)
+	gl.BindBuffer(gl.ARRAY_BUFFER, s.vbo)
 	gl.BufferData(gl.ARRAY_BUFFER, len(vertices)*floatSize, gl.Ptr(vertices), gl.STATIC_DRAW)
 
-	gl.BindVertexArray(cubeVAO)
+	gl.BindVertexArray(s.cubeVAO)
 
-	// Position attribute
-	gl.VertexAttribPointer(0, 3, gl.FLOAT, false, 8*floatSize, nil)
-	gl.EnableVertexAttribArray(0)
-	// Normal attribute
-	gl.VertexAttribPointerWithOffset(1, 3, gl.FLOAT, false, 8*floatSize, 3*floatSize)
-	gl.EnableVertexAttribArray(1)
-	// Texture Coord attribute
-	gl.VertexAttribPointerWithOffset(2, 2, gl.FLOAT, false, 8*floatSize, 6*floatSize)
-	gl.EnableVertexAttribArray(2)
+	// Position, normal and texture coord attributes
+	vertex.Floats(3, 3, 2).Apply()
 
 	// Second, configure the light's VAO
 	// (VBO stays the same. The vertices are the same for the light object wich is also a 3D cube)
-	var lightCubeVAO uint32
-	gl.GenVertexArrays(1, &lightCubeVAO)
-	gl.BindVertexArray(lightCubeVAO)
+	gl.GenVertexArrays(1, &s.lightCubeVAO)
+	gl.BindVertexArray(s.lightCubeVAO)
 
 	// We only need to bind to the VBO (to link it with glVertexAttribPointer), no need to fill it;
 	// The VBO's data already contains all we need (it's already bound, but we do it again for educational purposes)
-	gl.BindBuffer(gl.ARRAY_BUFFER, vbo)
+	gl.BindBuffer(gl.ARRAY_BUFFER, s.vbo)
 
 	// Position attribute
-	gl.VertexAttribPointer(0, 3, gl.FLOAT, false, 8*floatSize, nil)
-	gl.EnableVertexAttribArray(0)
+	vertex.Floats(3, 3, 2).Only(0).Apply()
 
-	diffuseMapTex, err := texture.New("internal/assets/textures/woodbox.png", gl.TEXTURE_2D, gl.TEXTURE0, gl.RGBA, gl.RGBA, gl.UNSIGNED_INT)
+	s.diffuseMapTex, err = texture.NewFS(assets.FS, "textures/woodbox.png", gl.TEXTURE0, texture.DefaultOptions)
 	if err != nil {
-		panic(err)
+		return err
 	}
 
-	specularMapTex, err := texture.New("internal/assets/textures/woodbox_specular.png", gl.TEXTURE_2D, gl.TEXTURE1, gl.RGBA, gl.RGBA, gl.UNSIGNED_INT)
+	s.specularMapTex, err = texture.NewFS(assets.FS, "textures/woodbox_specular.png", gl.TEXTURE1, texture.DefaultOptions)
 	if err != nil {
-		panic(err)
+		return err
 	}
 
-	// Clean up all resources
-	defer func() {
-		gl.DeleteVertexArrays(1, &cubeVAO)
-		gl.DeleteBuffers(1, &vbo)
-		lightingShader.Delete()
-		diffuseMapTex.Delete()
-		specularMapTex.Delete()
-	}()
-
 	gl.Enable(gl.DEPTH_TEST)
 
-	cubePositions := []mgl32.Vec3{
+	s.cubePositions = []mgl32.Vec3{
 		mgl32.Vec3{0.0, 0.0, 0.0},
 		mgl32.Vec3{2.0, 5.0, -15.0},
 		mgl32.Vec3{-1.5, -2.2, -2.5},
@@ -194,93 +145,69 @@ func (s SpotLight) Show() {
 		mgl32.Vec3{-1.3, 1.0, -1.5},
 	}
 
-	// Main loop
-	for !window.ShouldClose() {
-		s.processInput(window)
-
-		gl.ClearColor(0.1, 0.1, 0.1, 1.0)
-		gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
-
-		currentFrame := glfw.GetTime()
-		s.deltaTime = currentFrame - s.lastFrame
-		s.lastFrame = currentFrame
-
-		diffuseMapTex.ActiveAndBind()
-		specularMapTex.ActiveAndBind()
-
-		lightingShader.Use()
-		lightingShader.SetVec3("light.position", s.camera.Position)
-		lightingShader.SetVec3("light.direction", s.camera.Front)
-		cutOff := float32(math.Cos(float64(mgl32.DegToRad(12.5))))
-		lightingShader.SetFloat("light.cutOff", cutOff)
-		outerCutOff := float32(math.Cos(float64(mgl32.DegToRad(15.5))))
-		lightingShader.SetFloat("light.outerCutOff", outerCutOff)
-		lightingShader.SetVec3("viewPos", s.camera.Position)
-
-		lightingShader.SetVec3f("light.ambient", 0.1, 0.1, 0.1)
-		lightingShader.SetVec3f("light.diffuse", 0.8, 0.8, 0.8)
-		lightingShader.SetVec3f("light.specular", 1.0, 1.0, 1.0)
-		lightingShader.SetFloat("light.constant", 1.0)
-		lightingShader.SetFloat("light.linear", 0.09)
-		lightingShader.SetFloat("light.quadratic", 0.032)
-
-		lightingShader.SetInt("material.diffuse", 0)
-		lightingShader.SetInt("material.specular", 1)
-		lightingShader.SetFloat("material.shininess", 32.0)
-
-		viewMatrix := s.camera.ViewMatrix()
-		projectionMatrix := mgl32.Ident4()
-		projectionMatrix = mgl32.Perspective(mgl32.DegToRad(float32(s.camera.Fov)), width/height, 0.1, 100.0)
-		lightingShader.SetMat4("view", viewMatrix)
-		lightingShader.SetMat4("projection", projectionMatrix)
-
-		modelMatrix := mgl32.Ident4()
-		lightingShader.SetMat4("model", modelMatrix)
-
-		// Render the cubes
-		gl.BindVertexArray(cubeVAO)
-		for i, pos := range cubePositions {
-			modelMatrix = mgl32.Ident4()
-			translate := mgl32.Translate3D(pos.X(), pos.Y(), pos.Z())
-			angle := mgl32.DegToRad(20.0 * float32(i))
-			rotateX := mgl32.HomogRotate3D(angle, mgl32.Vec3{1.0, 0.0, 0.0})
-			rotateY := mgl32.HomogRotate3D(angle, mgl32.Vec3{0.0, 1.0, 0.0})
-			rotateZ := mgl32.HomogRotate3D(angle, mgl32.Vec3{0.0, 0.0, 1.0})
-
-			modelMatrix = modelMatrix.Mul4(translate)
-			modelMatrix = modelMatrix.Mul4(rotateX)
-			modelMatrix = modelMatrix.Mul4(rotateY)
-			modelMatrix = modelMatrix.Mul4(rotateZ)
-
-			lightingShader.SetMat4("model", modelMatrix)
-			gl.DrawArrays(gl.TRIANGLES, 0, 36)
-		}
-
-		window.SwapBuffers()
-		glfw.PollEvents()
+	s.light.CutOff = 12.5
+	s.light.OuterCutOff = 15.5
+	s.light.Colors = light.NewColors(mgl32.Vec3{1.0, 1.0, 1.0}, 0.1, 0.8)
+	s.light.Attenuation = light.Range(50)
+	s.light.Shadow, err = shadow.NewMap(shadow.DefaultOptions)
+	if err != nil {
+		return err
 	}
+
+	return nil
 }
 
-func (s *SpotLight) processInput(w *glfw.Window) {
-	processInput(w, s)
-	processCameraKeyboardInput(w, s.camera, s.deltaTime)
+func (s *SpotLight) Update(w *glfw.Window, deltaTime float64) {
+	s.processInput(w, deltaTime)
 }
 
-func (s *SpotLight) mouseCallback(w *glfw.Window, xpos, ypos float64) {
-	if s.firstMouse {
-		s.lastX = xpos
-		s.lastY = ypos
-		s.firstMouse = false
-	}
+func (s *SpotLight) Render(time float64) {
+	// The light is held like a flashlight, a bit to the right of and below the eyes,
+	// otherwise the shadows would be hidden right behind what casts them
+	s.light.Position = s.camera.Position.Add(s.camera.Right.Mul(0.3)).Sub(s.camera.Up.Mul(0.2))
+	s.light.Direction = s.camera.Front
+
+	// First, render the depth of the cubes as seen from the light
+	s.light.RenderShadow(func(depth *shader.Shader) {
+		drawCubes(depth, s.cubeVAO, s.cubePositions, nil)
+	})
+
+	gl.ClearColor(0.1, 0.1, 0.1, 1.0)
+	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+
+	s.diffuseMapTex.ActiveAndBind()
+	s.specularMapTex.ActiveAndBind()
 
-	xoffset := xpos - s.lastX
-	yoffset := s.lastY - ypos
-	s.lastX = xpos
-	s.lastY = ypos
+	s.lightingShader.Use()
+	s.lightingShader.SetVec3("viewPos", s.camera.Position)
+	s.light.Upload(s.lightingShader, "light")
+
+	s.lightingShader.SetInt("material.diffuse", 0)
+	s.lightingShader.SetInt("material.specular", 1)
+	s.lightingShader.SetFloat("material.shininess", 32.0)
+
+	s.light.Shadow.Bind(s.lightingShader, "shadow", 2)
+
+	viewMatrix := s.camera.ViewMatrix()
+	projectionMatrix := s.camera.ProjectionMatrix()
+	s.lightingShader.SetMat4("view", viewMatrix)
+	s.lightingShader.SetMat4("projection", projectionMatrix)
+
+	// Render the cubes
+	drawCubes(s.lightingShader, s.cubeVAO, s.cubePositions, s.cull())
+}
 
-	s.camera.ProcessMouseMovement(xoffset, yoffset, true)
+func (s *SpotLight) Resize(width, height int) {
+	s.resize(width, height)
 }
 
-func (s *SpotLight) mouseScrollCallback(w *glfw.Window, xoff, yoff float64) {
-	s.camera.ProcessMouseScroll(yoff)
+// Destroy cleans up all resources
+func (s *SpotLight) Destroy() {
+	gl.DeleteVertexArrays(1, &s.cubeVAO)
+	gl.DeleteVertexArrays(1, &s.lightCubeVAO)
+	gl.DeleteBuffers(1, &s.vbo)
+	s.lightingShader.Delete()
+	s.diffuseMapTex.Delete()
+	s.specularMapTex.Delete()
+	s.light.Shadow.Delete()
 }
@@ -1,20 +1,22 @@
 package scenes
 
 import (
-	"fmt"
-	"image"
-	"image/draw"
-	_ "image/jpeg"
-	_ "image/png"
-	"log"
-	"os"
-
 	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/igoramorim/gopengl/internal/assets"
+	"github.com/igoramorim/gopengl/pkg/imageutil"
 	"github.com/igoramorim/gopengl/pkg/shader"
+	"github.com/igoramorim/gopengl/pkg/vertex"
 )
 
-type Textures struct{}
+type Textures struct {
+	shader   *shader.Shader
+	vao      uint32
+	vbo      uint32
+	ebo      uint32
+	texture0 uint32
+	texture1 uint32
+}
 
 func (s Textures) Name() string {
 	return "textures"
@@ -28,36 +30,11 @@ func (s Textures) Height() int {
 	return height
 }
 
-func (s Textures) Show() {
-	if err := glfw.Init(); err != nil {
-		log.Fatalln("initialize glfw:", err)
-	}
-	defer glfw.Terminate()
-
-	glfw.WindowHint(glfw.Resizable, glfw.True)
-	glfw.WindowHint(glfw.ContextVersionMajor, 4)
-	glfw.WindowHint(glfw.ContextVersionMinor, 1)
-	glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
-	glfw.WindowHint(glfw.OpenGLForwardCompatible, glfw.True)
-
-	window, err := glfw.CreateWindow(s.Width(), s.Height(), s.Name(), nil, nil)
-	if err != nil {
-		panic(err)
-	}
-	window.MakeContextCurrent()
-
-	window.SetFramebufferSizeCallback(frameBufferSizeCallback)
-
-	if err := gl.Init(); err != nil {
-		panic(err)
-	}
-
-	version := gl.GoStr(gl.GetString(gl.VERSION))
-	fmt.Println("OpenGL version:", version)
-
-	shader, err := shader.New("internal/assets/shaders/texture.vert", "internal/assets/shaders/texture.frag")
+func (s *Textures) Init(w *glfw.Window) error {
+	var err error
+	s.shader, err = shader.NewFS(assets.FS, "shaders/texture.vert", "shaders/texture.frag")
 	if err != nil {
-		panic(err)
+		return err
 	}
 
 	var vertices = []float32{
@@ -75,41 +52,33 @@ func (s Textures) Show() {
 		1, 2, 3, // second triangle
 	}
 
-	var vao uint32
-	gl.GenVertexArrays(1, &vao)
-	gl.BindVertexArray(vao)
+	gl.GenVertexArrays(1, &s.vao)
+	gl.BindVertexArray(s.vao)
 
-	var vbo uint32
-	gl.GenBuffers(1, &vbo)
-	gl.BindBuffer(gl.ARRAY_BUFFER, vbo)
+	gl.GenBuffers(1, &s.vbo)
+	gl.BindBuffer(gl.ARRAY_BUFFER, s.vbo)
 	gl.BufferData(gl.ARRAY_BUFFER, len(vertices)*floatSize, gl.Ptr(vertices), gl.STATIC_DRAW)
 
 	// Element Buffer Object
-	var ebo uint32
-	gl.GenBuffers(1, &ebo)
-	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, ebo)
+	gl.GenBuffers(1, &s.ebo)
+	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, s.ebo)
 	gl.BufferData(gl.ELEMENT_ARRAY_BUFFER, len(indices)*uint32Size, gl.Ptr(indices), gl.STATIC_DRAW)
 
-	// Position attribute
-	gl.VertexAttribPointer(0, 3, gl.FLOAT, false, 5*floatSize, nil)
-	gl.EnableVertexAttribArray(0)
+	// Position and texture coord attributes
+	vertex.Floats(3, 2).Apply()
 
-	// Texture Coord attribute
-	gl.VertexAttribPointerWithOffset(1, 2, gl.FLOAT, false, 5*floatSize, 3*floatSize)
-	gl.EnableVertexAttribArray(1)
-
-	// Load first image
-	imageData0, err := s.loadImage("internal/assets/textures/container.jpg")
+	// Load first image. OpenGL expects the first row to be the bottom of the image,
+	// so it is flipped to not be drawn upside-down
+	imageData0, err := imageutil.Load(assets.FS, "textures/container.jpg", true)
 	if err != nil {
-		panic(err)
+		return err
 	}
 
 	// Generate the first texture
-	var texture0 uint32
 	// Generate one texture
-	gl.GenTextures(1, &texture0)
+	gl.GenTextures(1, &s.texture0)
 	// Bind the texture BEFORE setting the gl.TEXTURE_2D configurations
-	gl.BindTexture(gl.TEXTURE_2D, texture0)
+	gl.BindTexture(gl.TEXTURE_2D, s.texture0)
 	// Set the texture filtering mode when downscaling
 	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
 	// Set the texture filtering mode when upscaling
@@ -129,18 +98,18 @@ func (s Textures) Show() {
 		gl.UNSIGNED_BYTE,                // Datatype wich the image was loaded in
 		gl.Ptr(imageData0.Pix),          // The image data
 	)
-	// gl.GenerateTextureMipmap(texture) // FIXME: Está gerando panic
+	// Generate the mipmaps of the texture currently bound
+	gl.GenerateMipmap(gl.TEXTURE_2D)
 
 	// Load second image
-	imageData1, err := s.loadImage("internal/assets/textures/awesomeface.png")
+	imageData1, err := imageutil.Load(assets.FS, "textures/awesomeface.png", true)
 	if err != nil {
-		panic(err)
+		return err
 	}
 
 	// Generate the second texture
-	var texture1 uint32
-	gl.GenTextures(1, &texture1)
-	gl.BindTexture(gl.TEXTURE_2D, texture1)
+	gl.GenTextures(1, &s.texture1)
+	gl.BindTexture(gl.TEXTURE_2D, s.texture1)
 	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
 	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
 	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
@@ -156,64 +125,44 @@ func (s Textures) Show() {
 		gl.UNSIGNED_BYTE,
 		gl.Ptr(imageData1.Pix),
 	)
-	// gl.GenerateTextureMipmap(texture) // FIXME: Está gerando panic
-
-	// Clean up all resources
-	defer func() {
-		gl.DeleteVertexArrays(1, &vao)
-		gl.DeleteBuffers(1, &vbo)
-		gl.DeleteBuffers(1, &ebo)
-		shader.Delete()
-	}()
-
-	// Main loop
-	for !window.ShouldClose() {
-		processInput(window, s)
-
-		gl.ClearColor(0.2, 0.3, 0.3, 1.0)
-		gl.Clear(gl.COLOR_BUFFER_BIT)
-
-		// Need to activate the shader before setting the texture uniform
-		shader.Use()
-
-		// Activate, bind and set the first texture uniform in the shader
-		gl.ActiveTexture(gl.TEXTURE0)
-		gl.BindTexture(gl.TEXTURE_2D, texture0)
-		shader.SetInt("texture0", 0)
-
-		// Activate, bind and set the second texture uniform in the shader
-		gl.ActiveTexture(gl.TEXTURE1)
-		gl.BindTexture(gl.TEXTURE_2D, texture1)
-		shader.SetInt("texture1", 1)
-
-		gl.BindVertexArray(vao)
-		// Draw the rectangle using the ebo
-		gl.DrawElements(gl.TRIANGLES, 6, gl.UNSIGNED_INT, nil)
-
-		window.SwapBuffers()
-		glfw.PollEvents()
-	}
+	// Generate the mipmaps of the texture currently bound
+	gl.GenerateMipmap(gl.TEXTURE_2D)
+
+	return nil
 }
 
-func (d Textures) loadImage(file string) (*image.RGBA, error) {
-	imgFile, err := os.Open(file)
-	if err != nil {
-		return nil, fmt.Errorf("texture %q not found on disk: %v", file, err)
-	}
-	defer imgFile.Close()
+func (s *Textures) Update(w *glfw.Window, deltaTime float64) {}
 
-	img, _, err := image.Decode(imgFile)
-	if err != nil {
-		return nil, err
-	}
+func (s *Textures) Render(time float64) {
+	gl.ClearColor(0.2, 0.3, 0.3, 1.0)
+	gl.Clear(gl.COLOR_BUFFER_BIT)
 
-	rgba := image.NewRGBA(img.Bounds())
-	if rgba.Stride != rgba.Rect.Size().X*4 {
-		return nil, fmt.Errorf("unsupported stride")
-	}
+	// Need to activate the shader before setting the texture uniform
+	s.shader.Use()
+
+	// Activate, bind and set the first texture uniform in the shader
+	gl.ActiveTexture(gl.TEXTURE0)
+	gl.BindTexture(gl.TEXTURE_2D, s.texture0)
+	s.shader.SetInt("texture0", 0)
+
+	// Activate, bind and set the second texture uniform in the shader
+	gl.ActiveTexture(gl.TEXTURE1)
+	gl.BindTexture(gl.TEXTURE_2D, s.texture1)
+	s.shader.SetInt("texture1", 1)
+
+	gl.BindVertexArray(s.vao)
+	// Draw the rectangle using the ebo
+	gl.DrawElements(gl.TRIANGLES, 6, gl.UNSIGNED_INT, nil)
+}
 
-	// FIXME: Images are being loaded upside-down
-	draw.Draw(rgba, rgba.Bounds(), img, image.Point{0, 0}, draw.Src)
+func (s *Textures) Resize(width, height int) {}
 
-	return rgba, nil
+// Destroy cleans up all resources
+func (s *Textures) Destroy() {
+	gl.DeleteVertexArrays(1, &s.vao)
+	gl.DeleteBuffers(1, &s.vbo)
+	gl.DeleteBuffers(1, &s.ebo)
+	gl.DeleteTextures(1, &s.texture0)
+	gl.DeleteTextures(1, &s.texture1)
+	s.shader.Delete()
 }
@@ -2,14 +2,17 @@ package scenes
 
 import (
 	"fmt"
-	"log"
 	"strings"
 
 	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/glfw/v3.3/glfw"
 )
 
-type Triangle struct{}
+type Triangle struct {
+	shaderProgram uint32
+	vao           uint32
+	vbo           uint32
+}
 
 func (s Triangle) Name() string {
 	return "triangle"
@@ -23,35 +26,7 @@ func (s Triangle) Height() int {
 	return height
 }
 
-func (s Triangle) Show() {
-	if err := glfw.Init(); err != nil {
-		log.Fatalln("initialize glfw:", err)
-	}
-	defer glfw.Terminate()
-
-	glfw.WindowHint(glfw.Resizable, glfw.True)
-	glfw.WindowHint(glfw.ContextVersionMajor, 4)
-	glfw.WindowHint(glfw.ContextVersionMinor, 1)
-	glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
-	glfw.WindowHint(glfw.OpenGLForwardCompatible, glfw.True)
-
-	window, err := glfw.CreateWindow(s.Width(), s.Height(), s.Name(), nil, nil)
-	if err != nil {
-		panic(err)
-	}
-	window.MakeContextCurrent()
-
-	// Handles window resize. Calls frameBufferSizeCallback func whenever the window changes in size
-	window.SetFramebufferSizeCallback(frameBufferSizeCallback)
-
-	// Initialize Glow
-	if err := gl.Init(); err != nil {
-		panic(err)
-	}
-
-	version := gl.GoStr(gl.GetString(gl.VERSION))
-	fmt.Println("OpenGL version:", version)
-
+func (s *Triangle) Init(w *glfw.Window) error {
 	// Vertex shader
 	vertexShader := gl.CreateShader(gl.VERTEX_SHADER)
 	vertexShaderCSource, free := gl.Strs(s.vertexShaderSource())
@@ -70,7 +45,7 @@ func (s Triangle) Show() {
 		log := strings.Repeat("\x00", int(logLength+1))
 		gl.GetShaderInfoLog(vertexShader, logLength, nil, gl.Str(log))
 
-		panic(fmt.Sprintf("compile shader source %s\n %s\n", s.vertexShaderSource(), log))
+		return fmt.Errorf("compile shader source %s\n %s\n", s.vertexShaderSource(), log)
 	}
 
 	// Fragment shader
@@ -88,25 +63,25 @@ func (s Triangle) Show() {
 		log := strings.Repeat("\x00", int(logLength+1))
 		gl.GetShaderInfoLog(fragmentShader, logLength, nil, gl.Str(log))
 
-		panic(fmt.Sprintf("compile shader source %s\n %s\n", s.fragmentShaderSource(), log))
+		return fmt.Errorf("compile shader source %s\n %s\n", s.fragmentShaderSource(), log)
 	}
 
 	// Shader program. Link vertex and fragment shaders into one obeject
-	shaderProgram := gl.CreateProgram()
-	gl.AttachShader(shaderProgram, vertexShader)
-	gl.AttachShader(shaderProgram, fragmentShader)
-	gl.LinkProgram(shaderProgram)
+	s.shaderProgram = gl.CreateProgram()
+	gl.AttachShader(s.shaderProgram, vertexShader)
+	gl.AttachShader(s.shaderProgram, fragmentShader)
+	gl.LinkProgram(s.shaderProgram)
 
 	// Checks if linking failed
-	gl.GetProgramiv(shaderProgram, gl.LINK_STATUS, &status)
+	gl.GetProgramiv(s.shaderProgram, gl.LINK_STATUS, &status)
 	if status == gl.FALSE {
 		var logLength int32
-		gl.GetProgramiv(shaderProgram, gl.INFO_LOG_LENGTH, &logLength)
+		gl.GetProgramiv(s.shaderProgram, gl.INFO_LOG_LENGTH, &logLength)
 
 		log := strings.Repeat("\x00", int(logLength+1))
-		gl.GetProgramInfoLog(shaderProgram, logLength, nil, gl.Str(log))
+		gl.GetProgramInfoLog(s.shaderProgram, logLength, nil, gl.Str(log))
 
-		panic(fmt.Sprintf("linking shader program %v\n", log))
+		return fmt.Errorf("linking shader program %v\n", log)
 	}
 
 	// Once the linking is done, we do not need the shader objects anymore
@@ -122,17 +97,15 @@ func (s Triangle) Show() {
 	}
 
 	// Vertex Array Object. Used to make it easy to switch between vertex buffers / attributes
-	var vao uint32
 	// Generate a vertex array ID
-	gl.GenVertexArrays(1, &vao)
+	gl.GenVertexArrays(1, &s.vao)
 	// Bind the vertex array before the vertex buffer(s)
-	gl.BindVertexArray(vao)
+	gl.BindVertexArray(s.vao)
 
 	// Vertex Buffer Object. Used to store the vertices in the GPU's memory
-	var vbo uint32
 	// Generate a vertex buffer ID
-	gl.GenBuffers(1, &vbo)
-	gl.BindBuffer(gl.ARRAY_BUFFER, vbo)
+	gl.GenBuffers(1, &s.vbo)
+	gl.BindBuffer(gl.ARRAY_BUFFER, s.vbo)
 	// Copy user data (vertices) into the currently bound buffer (VBO wich was binded to GL_ARRAY_BUFFER)
 	// Now we have vertex data stored in the GPU memory managed by a vertex buffer object (VBO)
 	gl.BufferData(gl.ARRAY_BUFFER, len(vertices)*floatSize, gl.Ptr(vertices), gl.STATIC_DRAW)
@@ -154,35 +127,33 @@ func (s Triangle) Show() {
 	gl.VertexAttribPointerWithOffset(1, 3, gl.FLOAT, false, 6*floatSize, 3*floatSize)
 	gl.EnableVertexAttribArray(1)
 
-	// Clean up all resources
-	defer func() {
-		gl.DeleteVertexArrays(1, &vao)
-		gl.DeleteBuffers(1, &vbo)
-		gl.DeleteProgram(shaderProgram)
-	}()
-
-	// Main loop
-	for !window.ShouldClose() {
-		processInput(window, s)
-
-		gl.ClearColor(0.2, 0.3, 0.3, 1.0)
-		gl.Clear(gl.COLOR_BUFFER_BIT)
-
-		// Sets what shader program the render calls will use
-		gl.UseProgram(shaderProgram)
-		// Draws the triangle using the data from the vao
-		gl.BindVertexArray(vao)
-		gl.DrawArrays(
-			gl.TRIANGLES, // Mode we want to draw
-			0,            // Start index of the vertex array we want to draw
-			3,            // How many vertices we want to draw
-		)
-
-		// Swap the front buffer and the back buffer
-		window.SwapBuffers()
-		// Checks if any events are triggered (like keyboard)
-		glfw.PollEvents()
-	}
+	return nil
+}
+
+func (s *Triangle) Update(w *glfw.Window, deltaTime float64) {}
+
+func (s *Triangle) Render(time float64) {
+	gl.ClearColor(0.2, 0.3, 0.3, 1.0)
+	gl.Clear(gl.COLOR_BUFFER_BIT)
+
+	// Sets what shader program the render calls will use
+	gl.UseProgram(s.shaderProgram)
+	// Draws the triangle using the data from the vao
+	gl.BindVertexArray(s.vao)
+	gl.DrawArrays(
+		gl.TRIANGLES, // Mode we want to draw
+		0,            // Start index of the vertex array we want to draw
+		3,            // How many vertices we want to draw
+	)
+}
+
+func (s *Triangle) Resize(width, height int) {}
+
+// Destroy cleans up all resources
+func (s *Triangle) Destroy() {
+	gl.DeleteVertexArrays(1, &s.vao)
+	gl.DeleteBuffers(1, &s.vbo)
+	gl.DeleteProgram(s.shaderProgram)
 }
 
 func (s Triangle) vertexShaderSource() string {
@@ -2,7 +2,6 @@ package sshot
 
 import (
 	"fmt"
-	"image"
 	"image/gif"
 	"image/png"
 	"os"
@@ -10,6 +9,7 @@ import (
 
 	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/igoramorim/gopengl/pkg/imageutil"
 )
 
 func NewScreenShoter(filename string, width, height int) *ScreenShoter {
@@ -32,21 +32,33 @@ type ScreenShoter struct {
 }
 
 func (ss *ScreenShoter) TakeOne() {
+	if _, err := ss.SaveOne(filepath.Join(".", "images")); err != nil {
+		panic(err)
+	}
+}
+
+// SaveOne reads the pixels of the framebuffer currently bound and saves them as a png
+// named after the scene in dir. It returns the path of the saved file.
+func (ss *ScreenShoter) SaveOne(dir string) (string, error) {
 	pixels := make([]uint8, 4*ss.width*ss.height) // 4 = R G B A
 	gl.ReadPixels(0, 0, int32(ss.width), int32(ss.height), gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(&pixels[0]))
 
-	f, err := os.Create(fmt.Sprintf("%s/%s%s", filepath.Join(".", "images"), ss.filename, ".png"))
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return "", err
+	}
+
+	path := fmt.Sprintf("%s/%s%s", dir, ss.filename, ".png")
+	f, err := os.Create(path)
 	if err != nil {
-		panic(err)
+		return "", err
 	}
 	defer f.Close()
 
-	img := image.NewRGBA(image.Rect(0, 0, ss.width, ss.height))
-	img.Pix = pixels
-
-	if err := png.Encode(f, img); err != nil {
-		panic(err)
+	if err := png.Encode(f, imageutil.FromGL(pixels, ss.width, ss.height)); err != nil {
+		return "", err
 	}
+
+	return path, nil
 }
 
 func (ss *ScreenShoter) Take() {
@@ -81,7 +93,6 @@ const tpmImgExtension = ".gif"
 
 var tmpdir = filepath.Join(".", "images", "tmp")
 
-// FIXME: The iamges are being saved upside down
 func (ss *ScreenShoter) save(idx int) {
 	fmt.Printf("sshot: saving tmp img idx %d\n", idx)
 
@@ -96,10 +107,7 @@ func (ss *ScreenShoter) save(idx int) {
 	}
 	defer f.Close()
 
-	img := image.NewRGBA(image.Rect(0, 0, ss.width, ss.height))
-	img.Pix = ss.data[idx]
-
-	if err := gif.Encode(f, img, nil); err != nil {
+	if err := gif.Encode(f, imageutil.FromGL(ss.data[idx], ss.width, ss.height), nil); err != nil {
 		panic(err)
 	}
 }
@@ -0,0 +1,138 @@
+package animation
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/igoramorim/gopengl/pkg/shader"
+)
+
+// NewAnimator returns an animator of skeleton in its rest pose.
+func NewAnimator(skeleton *Skeleton) *Animator {
+	a := &Animator{
+		Speed:    1.0,
+		Loop:     true,
+		skeleton: skeleton,
+		pose:     skeleton.RestPose(),
+		fadePose: make([]Transform, len(skeleton.Joints)),
+		globals:  make([]mgl32.Mat4, len(skeleton.Joints)),
+		bones:    make([]mgl32.Mat4, len(skeleton.Bones)),
+	}
+	a.skeleton.BoneMatrices(a.pose, a.globals, a.bones)
+	return a
+}
+
+// Animator plays clips on a skeleton and computes its bone matrices, fading from one
+// clip to the next.
+type Animator struct {
+	// Speed scales the time of the clips, 1 plays them at their pace.
+	Speed float32
+	// Loop repeats the clips, otherwise they stop at their last keyframe.
+	Loop bool
+
+	skeleton *Skeleton
+	current  playback
+	// previous is the clip faded out, when fadeDuration is not 0
+	previous     playback
+	fadeTime     float32
+	fadeDuration float32
+
+	pose     []Transform
+	fadePose []Transform
+	globals  []mgl32.Mat4
+	bones    []mgl32.Mat4
+}
+
+// playback is a clip and its time.
+type playback struct {
+	clip *Clip
+	time float32
+}
+
+// Play starts clip from its beginning, blending from the pose of the clip playing over
+// fade seconds. Playing nil returns to the rest pose.
+func (a *Animator) Play(clip *Clip, fade float32) {
+	if fade > 0 && a.current.clip != nil {
+		a.previous = a.current
+		a.fadeTime = 0
+		a.fadeDuration = fade
+	} else {
+		a.fadeDuration = 0
+	}
+
+	a.current = playback{clip: clip}
+}
+
+// Clip returns the clip playing, nil in the rest pose.
+func (a *Animator) Clip() *Clip {
+	return a.current.clip
+}
+
+// Update advances the clips deltaTime seconds and computes the bone matrices.
+func (a *Animator) Update(deltaTime float64) {
+	dt := float32(deltaTime) * a.Speed
+	a.advance(&a.current, dt)
+
+	for i, j := range a.skeleton.Joints {
+		a.pose[i] = j.Rest
+	}
+	if a.current.clip != nil {
+		a.current.clip.Sample(a.current.time, a.pose)
+	}
+
+	if a.fadeDuration > 0 {
+		a.advance(&a.previous, dt)
+		a.fadeTime += float32(deltaTime)
+
+		if a.fadeTime >= a.fadeDuration {
+			a.fadeDuration = 0
+		} else {
+			for i, j := range a.skeleton.Joints {
+				a.fadePose[i] = j.Rest
+			}
+			if a.previous.clip != nil {
+				a.previous.clip.Sample(a.previous.time, a.fadePose)
+			}
+
+			weight := a.fadeTime / a.fadeDuration
+			for i := range a.pose {
+				a.pose[i] = blend(a.fadePose[i], a.pose[i], weight)
+			}
+		}
+	}
+
+	a.skeleton.BoneMatrices(a.pose, a.globals, a.bones)
+}
+
+func (a *Animator) advance(p *playback, dt float32) {
+	if p.clip == nil {
+		return
+	}
+
+	p.time += dt
+	if p.clip.Duration <= 0 {
+		p.time = 0
+		return
+	}
+
+	if a.Loop {
+		p.time = float32(math.Mod(float64(p.time), float64(p.clip.Duration)))
+		if p.time < 0 {
+			// Played backwards
+			p.time += p.clip.Duration
+		}
+		return
+	}
+	p.time = mgl32.Clamp(p.time, 0, p.clip.Duration)
+}
+
+// BoneMatrices returns the matrices of the bones computed by the last Update, from the
+// space of the mesh in the bind pose to the one of the animated mesh.
+func (a *Animator) BoneMatrices() []mgl32.Mat4 {
+	return a.bones
+}
+
+// Upload sets the mat4 array uniform called name to the bone matrices. s must be in use.
+func (a *Animator) Upload(s *shader.Shader, name string) error {
+	return s.SetMat4Array(name, a.bones)
+}
@@ -0,0 +1,102 @@
+package animation
+
+import (
+	"sort"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Interpolation is how the values between two keyframes are computed.
+type Interpolation int
+
+const (
+	// Linear interpolates the values, spherically for the rotations.
+	Linear Interpolation = iota
+	// Step keeps the value of the previous keyframe.
+	Step
+)
+
+// Vec3Track are the keyframes of a translation or scale. Times are in seconds and
+// ascending, one per value.
+type Vec3Track struct {
+	Interpolation Interpolation
+	Times         []float32
+	Values        []mgl32.Vec3
+}
+
+// QuatTrack are the keyframes of a rotation. Times are in seconds and ascending, one per
+// value.
+type QuatTrack struct {
+	Interpolation Interpolation
+	Times         []float32
+	Values        []mgl32.Quat
+}
+
+// Channel animates a joint. Tracks without keyframes keep the rest transform.
+type Channel struct {
+	// Joint is the index of the joint in Skeleton.Joints.
+	Joint       int
+	Translation Vec3Track
+	Rotation    QuatTrack
+	Scale       Vec3Track
+}
+
+// Clip is a keyframed animation of a skeleton, like a walk or a jump.
+type Clip struct {
+	Name string
+	// Duration in seconds, usually the time of the last keyframe.
+	Duration float32
+	Channels []Channel
+}
+
+// Sample writes the local transforms of the joints time seconds into the clip to pose,
+// which must have one per joint. The joints the clip does not animate are not changed.
+func (c *Clip) Sample(time float32, pose []Transform) {
+	for _, ch := range c.Channels {
+		t := &pose[ch.Joint]
+		if len(ch.Translation.Times) > 0 {
+			t.Translation = ch.Translation.sample(time)
+		}
+		if len(ch.Rotation.Times) > 0 {
+			t.Rotation = ch.Rotation.sample(time)
+		}
+		if len(ch.Scale.Times) > 0 {
+			t.Scale = ch.Scale.sample(time)
+		}
+	}
+}
+
+func (tr Vec3Track) sample(time float32) mgl32.Vec3 {
+	i, t := keyframe(tr.Times, time, tr.Interpolation)
+	if t == 0 {
+		return tr.Values[i]
+	}
+	return lerp(tr.Values[i], tr.Values[i+1], t)
+}
+
+func (tr QuatTrack) sample(time float32) mgl32.Quat {
+	i, t := keyframe(tr.Times, time, tr.Interpolation)
+	if t == 0 {
+		return tr.Values[i]
+	}
+	return nlerp(tr.Values[i], tr.Values[i+1], t)
+}
+
+// keyframe returns the last keyframe at or before time, or the first one, and how far
+// time is to the next one from 0 to 1.
+func keyframe(times []float32, time float32, interpolation Interpolation) (int, float32) {
+	// The first keyframe after time
+	next := sort.Search(len(times), func(i int) bool { return times[i] > time })
+
+	switch {
+	case next == 0:
+		return 0, 0
+	case next == len(times):
+		return len(times) - 1, 0
+	case interpolation == Step:
+		return next - 1, 0
+	}
+
+	i := next - 1
+	return i, (time - times[i]) / (times[next] - times[i])
+}
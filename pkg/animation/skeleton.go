@@ -0,0 +1,162 @@
+// Package animation plays keyframed clips on the skeletons of models and computes the
+// bone matrices of skinning vertex shaders.
+package animation
+
+import (
+	"fmt"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Transform is a translation, rotation and scale, applied in the reverse order.
+type Transform struct {
+	Translation mgl32.Vec3
+	Rotation    mgl32.Quat
+	Scale       mgl32.Vec3
+}
+
+// Identity is the transform that changes nothing.
+var Identity = Transform{
+	Rotation: mgl32.QuatIdent(),
+	Scale:    mgl32.Vec3{1, 1, 1},
+}
+
+func (t Transform) Mat4() mgl32.Mat4 {
+	translate := mgl32.Translate3D(t.Translation.X(), t.Translation.Y(), t.Translation.Z())
+	scale := mgl32.Scale3D(t.Scale.X(), t.Scale.Y(), t.Scale.Z())
+	return translate.Mul4(t.Rotation.Mat4()).Mul4(scale)
+}
+
+// Decompose returns the transform of m, which must have no shear nor projection.
+func Decompose(m mgl32.Mat4) Transform {
+	t := Transform{Translation: m.Col(3).Vec3()}
+
+	x, y, z := m.Col(0).Vec3(), m.Col(1).Vec3(), m.Col(2).Vec3()
+	t.Scale = mgl32.Vec3{x.Len(), y.Len(), z.Len()}
+	// A mirror flips an axis, here the x one
+	if x.Cross(y).Dot(z) < 0 {
+		t.Scale[0] = -t.Scale[0]
+	}
+
+	var rotation mgl32.Mat3
+	for i, axis := range []mgl32.Vec3{x, y, z} {
+		if t.Scale[i] != 0 {
+			axis = axis.Mul(1 / t.Scale[i])
+		}
+		rotation.SetCol(i, axis)
+	}
+	t.Rotation = mgl32.Mat4ToQuat(rotation.Mat4()).Normalize()
+
+	return t
+}
+
+// blend interpolates from a to b, which must be unit quaternions in the rotations.
+func blend(a, b Transform, weight float32) Transform {
+	return Transform{
+		Translation: lerp(a.Translation, b.Translation, weight),
+		Rotation:    nlerp(a.Rotation, b.Rotation, weight),
+		Scale:       lerp(a.Scale, b.Scale, weight),
+	}
+}
+
+func lerp(a, b mgl32.Vec3, t float32) mgl32.Vec3 {
+	return a.Add(b.Sub(a).Mul(t))
+}
+
+// nlerp interpolates the rotations along the shortest path. It is cheaper than
+// QuatSlerp and close enough between the near keyframes of clips.
+func nlerp(a, b mgl32.Quat, t float32) mgl32.Quat {
+	if a.Dot(b) < 0 {
+		b = b.Scale(-1)
+	}
+	q := mgl32.Quat{
+		W: a.W + (b.W-a.W)*t,
+		V: lerp(a.V, b.V, t),
+	}
+	if q.Len() == 0 {
+		return a
+	}
+	return q.Normalize()
+}
+
+// Joint is a node of the hierarchy of a skeleton.
+type Joint struct {
+	Name string
+	// Parent is the index of the parent joint, -1 for the roots.
+	Parent int
+	// Rest is the transform to the parent when no clip animates the joint.
+	Rest Transform
+}
+
+// Bone is a joint that moves vertices.
+type Bone struct {
+	// Joint is the index of the joint in Skeleton.Joints.
+	Joint int
+	// InverseBind transforms from the space of the mesh to the one of the joint in the
+	// pose the mesh was modeled in.
+	InverseBind mgl32.Mat4
+}
+
+// Skeleton is the joint hierarchy of a model and the bones the vertices are bound to.
+// The bone IDs of the vertices are indices in Bones.
+type Skeleton struct {
+	// Joints are sorted so the parents come before their children.
+	Joints []Joint
+	Bones  []Bone
+}
+
+// Validate checks the joints come after their parents, or are roots, and the bones have
+// joints.
+func (s *Skeleton) Validate() error {
+	for i, j := range s.Joints {
+		if j.Parent < -1 {
+			return fmt.Errorf("animation: joint %d %q: parent %d out of range, -1 is a root", i, j.Name, j.Parent)
+		}
+		if j.Parent >= i {
+			return fmt.Errorf("animation: joint %d %q comes before its parent %d", i, j.Name, j.Parent)
+		}
+	}
+	for i, b := range s.Bones {
+		if b.Joint < 0 || b.Joint >= len(s.Joints) {
+			return fmt.Errorf("animation: bone %d: joint %d out of range, %d are defined", i, b.Joint, len(s.Joints))
+		}
+	}
+	return nil
+}
+
+// Joint returns the index of the joint called name, or -1.
+func (s *Skeleton) Joint(name string) int {
+	for i, j := range s.Joints {
+		if j.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// RestPose returns the rest transforms of the joints.
+func (s *Skeleton) RestPose() []Transform {
+	pose := make([]Transform, len(s.Joints))
+	for i, j := range s.Joints {
+		pose[i] = j.Rest
+	}
+	return pose
+}
+
+// BoneMatrices writes to bones, one per bone, the transforms from the space of the mesh
+// in the bind pose to the one of the mesh in pose, the local transforms of the joints.
+// globals is scratch space of one matrix per joint.
+func (s *Skeleton) BoneMatrices(pose []Transform, globals, bones []mgl32.Mat4) {
+	for i, j := range s.Joints {
+		local := pose[i].Mat4()
+		if j.Parent < 0 {
+			globals[i] = local
+		} else {
+			globals[i] = globals[j.Parent].Mul4(local)
+		}
+	}
+
+	for i, b := range s.Bones {
+		bones[i] = globals[b.Joint].Mul4(b.InverseBind)
+	}
+}
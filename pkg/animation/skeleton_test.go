@@ -0,0 +1,55 @@
+package animation
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSkeletonValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		skeleton Skeleton
+		// want is part of the error, empty when there is none
+		want string
+	}{
+		{
+			name:     "valid",
+			skeleton: Skeleton{Joints: []Joint{{Name: "root", Parent: -1}, {Name: "arm", Parent: 0}}, Bones: []Bone{{Joint: 1}}},
+		},
+		{
+			name:     "parent after the child",
+			skeleton: Skeleton{Joints: []Joint{{Name: "arm", Parent: 1}, {Name: "root", Parent: -1}}},
+			want:     `joint 0 "arm" comes before its parent 1`,
+		},
+		{
+			name:     "own parent",
+			skeleton: Skeleton{Joints: []Joint{{Name: "root", Parent: 0}}},
+			want:     `joint 0 "root" comes before its parent 0`,
+		},
+		{
+			name:     "negative parent",
+			skeleton: Skeleton{Joints: []Joint{{Name: "root", Parent: -1}, {Name: "arm", Parent: -5}}},
+			want:     `joint 1 "arm": parent -5 out of range`,
+		},
+		{
+			name:     "bone without joint",
+			skeleton: Skeleton{Joints: []Joint{{Name: "root", Parent: -1}}, Bones: []Bone{{Joint: 1}}},
+			want:     "bone 0: joint 1 out of range, 1 are defined",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.skeleton.Validate()
+			if tt.want == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Validate() = %v, want an error with %q", err, tt.want)
+			}
+		})
+	}
+}
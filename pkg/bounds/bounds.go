@@ -0,0 +1,109 @@
+// Package bounds has the volumes bounding meshes and objects, and the frustum tests that
+// skip drawing the ones out of the view.
+package bounds
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// AABB is an axis aligned box. The zero AABB is the point at the origin.
+type AABB struct {
+	Min mgl32.Vec3
+	Max mgl32.Vec3
+}
+
+// AABBOf returns the box around the points, a zero box when there are none.
+func AABBOf(points []mgl32.Vec3) AABB {
+	if len(points) == 0 {
+		return AABB{}
+	}
+
+	b := AABB{Min: points[0], Max: points[0]}
+	for _, p := range points[1:] {
+		b = b.Extend(p)
+	}
+	return b
+}
+
+// Extend returns the box grown to hold p.
+func (b AABB) Extend(p mgl32.Vec3) AABB {
+	for i := range 3 {
+		b.Min[i] = min(b.Min[i], p[i])
+		b.Max[i] = max(b.Max[i], p[i])
+	}
+	return b
+}
+
+// Union returns the box around b and o.
+func (b AABB) Union(o AABB) AABB {
+	return b.Extend(o.Min).Extend(o.Max)
+}
+
+func (b AABB) Center() mgl32.Vec3 {
+	return b.Min.Add(b.Max).Mul(0.5)
+}
+
+// Extents returns the half sizes of the box along the axes.
+func (b AABB) Extents() mgl32.Vec3 {
+	return b.Max.Sub(b.Min).Mul(0.5)
+}
+
+// Transform returns the box around b transformed by m, e.g. a model matrix. It is larger
+// than b when m rotates.
+func (b AABB) Transform(m mgl32.Mat4) AABB {
+	center := m.Mul4x1(b.Center().Vec4(1.0)).Vec3()
+	extents := b.Extents()
+
+	// Each axis of the new box spans the extents projected on it
+	var e mgl32.Vec3
+	for row := range 3 {
+		for col := range 3 {
+			e[row] += float32(math.Abs(float64(m.At(row, col)))) * extents[col]
+		}
+	}
+
+	return AABB{Min: center.Sub(e), Max: center.Add(e)}
+}
+
+// Sphere returns the sphere around the box.
+func (b AABB) Sphere() Sphere {
+	return Sphere{Center: b.Center(), Radius: b.Extents().Len()}
+}
+
+// Sphere is a bounding sphere.
+type Sphere struct {
+	Center mgl32.Vec3
+	Radius float32
+}
+
+// SphereOf returns a sphere around the points, centered on their box. It is tighter than
+// the sphere of the box, not the smallest one.
+func SphereOf(points []mgl32.Vec3) Sphere {
+	s := Sphere{Center: AABBOf(points).Center()}
+	for _, p := range points {
+		s = s.Extend(p)
+	}
+	return s
+}
+
+// Extend returns the sphere grown to hold p, keeping its center.
+func (s Sphere) Extend(p mgl32.Vec3) Sphere {
+	s.Radius = max(s.Radius, p.Sub(s.Center).Len())
+	return s
+}
+
+// Transform returns the sphere around s transformed by m, scaled by the largest scale of
+// m.
+func (s Sphere) Transform(m mgl32.Mat4) Sphere {
+	var scale float32
+	for col := range 3 {
+		scale = max(scale, m.Col(col).Vec3().Len())
+	}
+
+	return Sphere{
+		Center: m.Mul4x1(s.Center.Vec4(1.0)).Vec3(),
+		Radius: s.Radius * scale,
+	}
+}
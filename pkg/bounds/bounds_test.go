@@ -0,0 +1,108 @@
+package bounds
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const epsilon = 1e-4
+
+// near reports whether a and b are less than epsilon apart.
+func near(a, b mgl32.Vec3) bool {
+	return a.Sub(b).Len() < epsilon
+}
+
+// nearAABB reports whether the corners of a and b are less than epsilon apart.
+func nearAABB(a, b AABB) bool {
+	return near(a.Min, b.Min) && near(a.Max, b.Max)
+}
+
+func TestAABBOf(t *testing.T) {
+	points := []mgl32.Vec3{{1, -2, 0}, {-1, 3, 0.5}, {0, 0, -4}}
+
+	want := AABB{Min: mgl32.Vec3{-1, -2, -4}, Max: mgl32.Vec3{1, 3, 0.5}}
+	if got := AABBOf(points); got != want {
+		t.Errorf("AABBOf() = %v, want %v", got, want)
+	}
+	if got := AABBOf(nil); got != (AABB{}) {
+		t.Errorf("AABBOf(nil) = %v, want the zero box", got)
+	}
+}
+
+func TestAABBTransform(t *testing.T) {
+	cube := AABB{Min: mgl32.Vec3{-1, -1, -1}, Max: mgl32.Vec3{1, 1, 1}}
+	long := AABB{Min: mgl32.Vec3{0, 0, 0}, Max: mgl32.Vec3{4, 2, 2}}
+	diagonal := float32(math.Sqrt2)
+
+	tests := []struct {
+		name string
+		box  AABB
+		m    mgl32.Mat4
+		want AABB
+	}{
+		{
+			name: "translation",
+			box:  cube,
+			m:    mgl32.Translate3D(1, 2, 3),
+			want: AABB{Min: mgl32.Vec3{0, 1, 2}, Max: mgl32.Vec3{2, 3, 4}},
+		},
+		{
+			// The corners of the cube end on the axes, the box grows to hold them
+			name: "45 degrees",
+			box:  cube,
+			m:    mgl32.HomogRotate3DY(mgl32.DegToRad(45)),
+			want: AABB{Min: mgl32.Vec3{-diagonal, -1, -diagonal}, Max: mgl32.Vec3{diagonal, 1, diagonal}},
+		},
+		{
+			// The long side turns from x to z, around the center moved by the rotation
+			name: "90 degrees",
+			box:  long,
+			m:    mgl32.HomogRotate3DY(mgl32.DegToRad(90)),
+			want: AABB{Min: mgl32.Vec3{0, 0, -4}, Max: mgl32.Vec3{2, 2, 0}},
+		},
+		{
+			name: "scaled and moved",
+			box:  long,
+			m:    mgl32.Translate3D(0, -1, 0).Mul4(mgl32.Scale3D(0.5, 2, 1)),
+			want: AABB{Min: mgl32.Vec3{0, -1, 0}, Max: mgl32.Vec3{2, 3, 2}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.box.Transform(tt.m); !nearAABB(got, tt.want) {
+				t.Errorf("Transform() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSphereTransform(t *testing.T) {
+	s := Sphere{Center: mgl32.Vec3{1, 0, 0}, Radius: 2}
+
+	// The largest scale is kept, whatever the rotation
+	m := mgl32.Translate3D(0, 1, 0).Mul4(mgl32.HomogRotate3DZ(mgl32.DegToRad(90))).Mul4(mgl32.Scale3D(1, 3, 2))
+	got := s.Transform(m)
+
+	if !near(got.Center, mgl32.Vec3{0, 2, 0}) || math.Abs(float64(got.Radius-6)) > epsilon {
+		t.Errorf("Transform() = %v, want a sphere of radius 6 at (0, 2, 0)", got)
+	}
+}
+
+func TestSphereOf(t *testing.T) {
+	points := []mgl32.Vec3{{-2, 0, 0}, {2, 0, 0}, {0, 1, 0}}
+
+	// Centered on the box, tighter than its sphere
+	s := SphereOf(points)
+	if s.Center != (mgl32.Vec3{0, 0.5, 0}) {
+		t.Errorf("center = %v, want (0, 0.5, 0)", s.Center)
+	}
+	if want := (mgl32.Vec3{2, 0.5, 0}).Len(); s.Radius != want {
+		t.Errorf("radius = %v, want %v", s.Radius, want)
+	}
+	if box := AABBOf(points).Sphere(); s.Radius > box.Radius {
+		t.Errorf("radius %v larger than the sphere of the box %v", s.Radius, box.Radius)
+	}
+}
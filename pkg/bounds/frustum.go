@@ -0,0 +1,146 @@
+package bounds
+
+import "github.com/go-gl/mathgl/mgl32"
+
+// Plane is the plane of the points p where Normal.Dot(p) + D is 0. Normal is a unit
+// vector pointing to the inside.
+type Plane struct {
+	Normal mgl32.Vec3
+	D      float32
+}
+
+// Distance returns the signed distance from the plane to p, positive on the inside.
+func (p Plane) Distance(point mgl32.Vec3) float32 {
+	return p.Normal.Dot(point) + p.D
+}
+
+// The planes of a Frustum, by index.
+const (
+	Left = iota
+	Right
+	Bottom
+	Top
+	Near
+	Far
+)
+
+// Frustum is the volume seen by a camera, bounded by six planes facing inwards.
+type Frustum struct {
+	Planes [6]Plane
+}
+
+// FrustumOf extracts the frustum of the view-projection matrix m, the volume of the
+// world that ends up in the clip space. With a projection matrix alone it is in the view
+// space, with a model-view-projection matrix in the space of the model.
+func FrustumOf(m mgl32.Mat4) Frustum {
+	// A point is inside when -w <= x, y, z <= w in the clip space, each of these is a
+	// plane combining the rows of m
+	rows := [4]mgl32.Vec4{m.Row(0), m.Row(1), m.Row(2), m.Row(3)}
+
+	var f Frustum
+	f.Planes[Left] = plane(rows[3].Add(rows[0]))
+	f.Planes[Right] = plane(rows[3].Sub(rows[0]))
+	f.Planes[Bottom] = plane(rows[3].Add(rows[1]))
+	f.Planes[Top] = plane(rows[3].Sub(rows[1]))
+	f.Planes[Near] = plane(rows[3].Add(rows[2]))
+	f.Planes[Far] = plane(rows[3].Sub(rows[2]))
+	return f
+}
+
+// plane returns the normalized plane of the coefficients a b c d.
+func plane(v mgl32.Vec4) Plane {
+	normal := v.Vec3()
+	length := normal.Len()
+	if length == 0 {
+		return Plane{}
+	}
+	return Plane{Normal: normal.Mul(1.0 / length), D: v.W() / length}
+}
+
+// ContainsPoint reports whether p is inside the frustum.
+func (f Frustum) ContainsPoint(p mgl32.Vec3) bool {
+	for _, plane := range f.Planes {
+		if plane.Distance(p) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// IntersectsSphere reports whether s is inside the frustum, even partly. Spheres near
+// the corners may pass while out of the view, which is safe to draw.
+func (f Frustum) IntersectsSphere(s Sphere) bool {
+	for _, plane := range f.Planes {
+		if plane.Distance(s.Center) < -s.Radius {
+			return false
+		}
+	}
+	return true
+}
+
+// IntersectsAABB reports whether b is inside the frustum, even partly. Like
+// IntersectsSphere it may pass boxes near the corners.
+func (f Frustum) IntersectsAABB(b AABB) bool {
+	for _, plane := range f.Planes {
+		// The corner the farthest along the normal is the last one to leave the plane
+		corner := b.Min
+		for i := range 3 {
+			if plane.Normal[i] >= 0 {
+				corner[i] = b.Max[i]
+			}
+		}
+		if plane.Distance(corner) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// CullStats counts the objects tested against a frustum, e.g. for a debug overlay.
+type CullStats struct {
+	Tested int
+	Culled int
+}
+
+// Visible returns how many of the tested objects were not culled.
+func (s CullStats) Visible() int {
+	return s.Tested - s.Culled
+}
+
+// Culler tests the objects of a frame against the frustum of a camera, counting them.
+type Culler struct {
+	Frustum Frustum
+	Stats   CullStats
+	// Disabled lets every object pass, still counted as tested.
+	Disabled bool
+}
+
+// Reset starts a frame: it extracts the frustum of viewProjection and zeroes the stats.
+func (c *Culler) Reset(viewProjection mgl32.Mat4) {
+	c.Frustum = FrustumOf(viewProjection)
+	c.Stats = CullStats{}
+}
+
+// VisibleAABB reports whether b is to be drawn, counting it.
+func (c *Culler) VisibleAABB(b AABB) bool {
+	return c.count(c.Disabled || c.Frustum.IntersectsAABB(b))
+}
+
+// VisibleSphere reports whether s is to be drawn, counting it.
+func (c *Culler) VisibleSphere(s Sphere) bool {
+	return c.count(c.Disabled || c.Frustum.IntersectsSphere(s))
+}
+
+// Visible reports whether an object bounded by both s and b is to be drawn, counting it
+// once. The sphere is cheaper to test and rejects most objects, the box is tighter.
+func (c *Culler) Visible(s Sphere, b AABB) bool {
+	return c.count(c.Disabled || c.Frustum.IntersectsSphere(s) && c.Frustum.IntersectsAABB(b))
+}
+
+func (c *Culler) count(visible bool) bool {
+	c.Stats.Tested++
+	if !visible {
+		c.Stats.Culled++
+	}
+	return visible
+}
@@ -0,0 +1,142 @@
+package bounds
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// testViewProjection is a camera at (0, 0, 5) looking down -z, with a 90 degrees square
+// view from 1 to 10 in front of it. The frustum spans z in [-5, 4] in the world and
+// widens by 1 to each side per unit away from the camera.
+func testViewProjection() mgl32.Mat4 {
+	projection := mgl32.Perspective(mgl32.DegToRad(90), 1, 1, 10)
+	view := mgl32.LookAtV(mgl32.Vec3{0, 0, 5}, mgl32.Vec3{0, 0, 0}, mgl32.Vec3{0, 1, 0})
+	return projection.Mul4(view)
+}
+
+func TestFrustumOf(t *testing.T) {
+	diagonal := float32(math.Sqrt2 / 2)
+
+	f := FrustumOf(testViewProjection())
+
+	want := [6]Plane{
+		Left:   {Normal: mgl32.Vec3{diagonal, 0, -diagonal}, D: 5 * diagonal},
+		Right:  {Normal: mgl32.Vec3{-diagonal, 0, -diagonal}, D: 5 * diagonal},
+		Bottom: {Normal: mgl32.Vec3{0, diagonal, -diagonal}, D: 5 * diagonal},
+		Top:    {Normal: mgl32.Vec3{0, -diagonal, -diagonal}, D: 5 * diagonal},
+		Near:   {Normal: mgl32.Vec3{0, 0, -1}, D: 4},
+		Far:    {Normal: mgl32.Vec3{0, 0, 1}, D: 5},
+	}
+	for i, plane := range f.Planes {
+		if !near(plane.Normal, want[i].Normal) || math.Abs(float64(plane.D-want[i].D)) > epsilon {
+			t.Errorf("plane %d = %v, want %v", i, plane, want[i])
+		}
+	}
+
+	tests := []struct {
+		point mgl32.Vec3
+		want  bool
+	}{
+		{point: mgl32.Vec3{0, 0, 0}, want: true},
+		{point: mgl32.Vec3{0, 0, 4.5}, want: false},
+		{point: mgl32.Vec3{0, 0, -6}, want: false},
+		{point: mgl32.Vec3{-4.5, 0, 0}, want: true},
+		{point: mgl32.Vec3{0, 5.5, 0}, want: false},
+	}
+	for _, tt := range tests {
+		if got := f.ContainsPoint(tt.point); got != tt.want {
+			t.Errorf("ContainsPoint(%v) = %v, want %v", tt.point, got, tt.want)
+		}
+	}
+}
+
+func TestIntersectsSphere(t *testing.T) {
+	f := FrustumOf(testViewProjection())
+
+	tests := []struct {
+		name   string
+		sphere Sphere
+		want   bool
+	}{
+		{name: "inside", sphere: Sphere{Center: mgl32.Vec3{0, 0, 0}, Radius: 1}, want: true},
+		{name: "behind the camera", sphere: Sphere{Center: mgl32.Vec3{0, 0, 8}, Radius: 1}, want: false},
+		{name: "straddling the far plane", sphere: Sphere{Center: mgl32.Vec3{0, 0, -5.5}, Radius: 1}, want: true},
+		{name: "past the far plane", sphere: Sphere{Center: mgl32.Vec3{0, 0, -7}, Radius: 1}, want: false},
+		// 5 / sqrt(2) from the left plane
+		{name: "left", sphere: Sphere{Center: mgl32.Vec3{-10, 0, 0}, Radius: 3}, want: false},
+		{name: "straddling the left plane", sphere: Sphere{Center: mgl32.Vec3{-10, 0, 0}, Radius: 4}, want: true},
+		{name: "around the frustum", sphere: Sphere{Center: mgl32.Vec3{0, 0, 0}, Radius: 100}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := f.IntersectsSphere(tt.sphere); got != tt.want {
+				t.Errorf("IntersectsSphere(%v) = %v, want %v", tt.sphere, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIntersectsAABB(t *testing.T) {
+	f := FrustumOf(testViewProjection())
+
+	tests := []struct {
+		name string
+		box  AABB
+		want bool
+	}{
+		{name: "inside", box: AABB{Min: mgl32.Vec3{-1, -1, -1}, Max: mgl32.Vec3{1, 1, 1}}, want: true},
+		{name: "straddling the near plane", box: AABB{Min: mgl32.Vec3{-1, -1, 3}, Max: mgl32.Vec3{1, 1, 6}}, want: true},
+		{name: "behind the camera", box: AABB{Min: mgl32.Vec3{-1, -1, 6}, Max: mgl32.Vec3{1, 1, 8}}, want: false},
+		{name: "past the far plane", box: AABB{Min: mgl32.Vec3{-1, -1, -8}, Max: mgl32.Vec3{1, 1, -6}}, want: false},
+		// Only the corner the farthest to the right reaches the view
+		{name: "straddling the left plane", box: AABB{Min: mgl32.Vec3{-6, -1, -1}, Max: mgl32.Vec3{-4, 1, 1}}, want: true},
+		{name: "left", box: AABB{Min: mgl32.Vec3{-8, -1, -1}, Max: mgl32.Vec3{-7, 1, 1}}, want: false},
+		{name: "above", box: AABB{Min: mgl32.Vec3{-1, 7, -1}, Max: mgl32.Vec3{1, 8, 1}}, want: false},
+		{name: "around the frustum", box: AABB{Min: mgl32.Vec3{-100, -100, -100}, Max: mgl32.Vec3{100, 100, 100}}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := f.IntersectsAABB(tt.box); got != tt.want {
+				t.Errorf("IntersectsAABB(%v) = %v, want %v", tt.box, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCuller(t *testing.T) {
+	inside := AABB{Min: mgl32.Vec3{-1, -1, -1}, Max: mgl32.Vec3{1, 1, 1}}
+	behind := AABB{Min: mgl32.Vec3{-1, -1, 6}, Max: mgl32.Vec3{1, 1, 8}}
+
+	var c Culler
+	c.Reset(testViewProjection())
+
+	c.VisibleAABB(inside)
+	c.VisibleAABB(behind)
+	c.VisibleSphere(behind.Sphere())
+	// Counted once for both volumes
+	if !c.Visible(inside.Sphere(), inside) {
+		t.Error("Visible() of a box inside = false")
+	}
+	if c.Visible(behind.Sphere(), behind) {
+		t.Error("Visible() of a box behind the camera = true")
+	}
+
+	want := CullStats{Tested: 5, Culled: 3}
+	if c.Stats != want || c.Stats.Visible() != 2 {
+		t.Errorf("stats = %+v with %d visible, want %+v with 2", c.Stats, c.Stats.Visible(), want)
+	}
+
+	// Disabled everything passes, still counted
+	c.Disabled = true
+	c.Reset(testViewProjection())
+	if !c.VisibleAABB(behind) || !c.VisibleSphere(behind.Sphere()) {
+		t.Error("a disabled culler culled a box")
+	}
+	if want := (CullStats{Tested: 2}); c.Stats != want {
+		t.Errorf("stats disabled = %+v, want %+v", c.Stats, want)
+	}
+}
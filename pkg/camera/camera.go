@@ -5,8 +5,24 @@ import (
 
 	"github.com/go-gl/mathgl/mgl32"
 	"github.com/go-gl/mathgl/mgl64"
+	"github.com/igoramorim/gopengl/pkg/bounds"
 )
 
+// Viewer is what the scenes need from a camera to draw with it, so they can switch
+// between Camera and Orbit.
+type Viewer interface {
+	ViewMatrix() mgl32.Mat4
+	ProjectionMatrix() mgl32.Mat4
+	// ViewProjection returns the projection matrix times the view matrix.
+	ViewProjection() mgl32.Mat4
+	// Frustum returns the six planes bounding what the camera sees, in the world space.
+	Frustum() bounds.Frustum
+	// Eye returns the position of the camera, e.g. for the specular lighting.
+	Eye() mgl32.Vec3
+	// SetAspect follows the size of the framebuffer, see Projection.SetAspect.
+	SetAspect(width, height int)
+}
+
 type Direction string
 
 const (
@@ -18,9 +34,59 @@ const (
 	Down     = "DOWN"
 )
 
-// TODO: Add optionals to receive custom values for position, front, up, fov etc.
-func New() *Camera {
+// Option sets up a camera created by New.
+type Option func(*Camera)
+
+// WithPosition places the camera at position, instead of 3 units along +Z.
+func WithPosition(position mgl32.Vec3) Option {
+	return func(c *Camera) {
+		c.Position = position
+	}
+}
+
+// WithYawPitch points the camera, in degrees, instead of along -Z.
+func WithYawPitch(yaw, pitch float64) Option {
+	return func(c *Camera) {
+		c.Yaw = yaw
+		c.Pitch = pitch
+	}
+}
+
+// WithFov sets the vertical field of view, in degrees, instead of 45.
+func WithFov(fov float64) Option {
+	return func(c *Camera) {
+		c.Fov = fov
+	}
+}
+
+// WithSpeed sets how fast the camera moves, in units by second, instead of 10.
+func WithSpeed(speed float64) Option {
+	return func(c *Camera) {
+		c.MovementSpeed = speed
+	}
+}
+
+// WithOrientationMode turns the camera by Yaw and Pitch, the default, or by quaternions
+// for free rotations with roll.
+func WithOrientationMode(mode OrientationMode) Option {
+	return func(c *Camera) {
+		c.OrientationMode = mode
+	}
+}
+
+// WithAspect sets the aspect ratio to the one of a framebuffer of width by height pixels,
+// until SetAspect changes it.
+func WithAspect(width, height int) Option {
+	return func(c *Camera) {
+		c.SetAspect(width, height)
+	}
+}
+
+// New returns a fly-through camera at (0, 0, 3) looking along -Z, with a perspective
+// projection, unless opts say otherwise.
+func New(opts ...Option) *Camera {
 	camera := &Camera{
+		Projection:       defaultProjection(),
 		Position:         mgl32.Vec3{0.0, 0.0, 3.0},
 		Front:            mgl32.Vec3{0.0, 0.0, -1.0},
 		WorldUp:          mgl32.Vec3{0.0, 1.0, 0.0},
@@ -28,15 +94,20 @@ func New() *Camera {
 		Pitch:            0.0,
 		MovementSpeed:    10.0,
 		MouseSensitivity: 0.1,
-		Fov:              45.0,
 	}
 
+	for _, opt := range opts {
+		opt(camera)
+	}
+
+	camera.Orientation = YawPitch(camera.Yaw, camera.Pitch)
 	camera.updateVectors()
 
 	return camera
 }
 
 type Camera struct {
+	Projection
 	Position         mgl32.Vec3
 	Front            mgl32.Vec3
 	Up               mgl32.Vec3
@@ -46,11 +117,26 @@ type Camera struct {
 	Pitch            float64
 	MovementSpeed    float64
 	MouseSensitivity float64
-	Fov              float64
+	// OrientationMode turns the camera by Yaw and Pitch, or by Orientation, see
+	// SetOrientationMode.
+	OrientationMode OrientationMode
+	// Orientation rotates the view space to the world. In the Euler mode it follows Yaw and
+	// Pitch, in the Quaternion mode they follow it.
+	Orientation mgl32.Quat
 }
 
-// updateVectors calculates the fron vector from the camera's (updated) euler angles.
+// updateVectors calculates the fron vector from the camera's (updated) euler angles, or
+// its orientation in the Quaternion mode.
 func (c *Camera) updateVectors() {
+	if c.OrientationMode == Quaternion {
+		c.Orientation = c.Orientation.Normalize()
+		c.Front = c.Orientation.Rotate(viewFront)
+		c.Up = c.Orientation.Rotate(viewUp)
+		c.Right = c.Orientation.Rotate(viewRight)
+		c.Yaw, c.Pitch = EulerAngles(c.Orientation)
+		return
+	}
+
 	// Normalize the vectors, because their length gets closer to 0 the more you look up or
 	// down which results in slower movement.
 
@@ -69,6 +155,8 @@ func (c *Camera) updateVectors() {
 	up := c.Right.Cross(c.Front)
 	c.Up = up.Normalize()
 
+	c.Orientation = YawPitch(c.Yaw, c.Pitch)
+
 	// fmt.Printf("after update vectores:\ncamera vectors: front: %v right: %v up: %v\n", c.Front, c.Right, c.Up)
 }
 
@@ -76,6 +164,19 @@ func (c *Camera) ViewMatrix() mgl32.Mat4 {
 	return mgl32.LookAtV(c.Position, c.Position.Add(c.Front), c.Up)
 }
 
+func (c *Camera) ViewProjection() mgl32.Mat4 {
+	return c.ProjectionMatrix().Mul4(c.ViewMatrix())
+}
+
+func (c *Camera) Frustum() bounds.Frustum {
+	return bounds.FrustumOf(c.ViewProjection())
+}
+
+// Eye returns the position of the camera.
+func (c *Camera) Eye() mgl32.Vec3 {
+	return c.Position
+}
+
 func (c *Camera) ProcessKeyboard(direction Direction, deltaTime float64) {
 	velocity := c.MovementSpeed * deltaTime
 
@@ -97,10 +198,58 @@ func (c *Camera) ProcessKeyboard(direction Direction, deltaTime float64) {
 	// fmt.Printf("camera position: %v\n", c.Position)
 }
 
+// SetOrientationMode switches between the Euler and Quaternion modes keeping the
+// direction of the view. Back to the Euler mode the roll is lost and the pitch clamped.
+func (c *Camera) SetOrientationMode(mode OrientationMode) {
+	c.OrientationMode = mode
+	if mode == Euler {
+		c.Pitch = mgl64.Clamp(c.Pitch, -89.0, 89.0)
+	}
+	c.updateVectors()
+}
+
+// SetOrientation turns the camera to orientation. In the Euler mode its roll is dropped.
+func (c *Camera) SetOrientation(orientation mgl32.Quat) {
+	if c.OrientationMode == Quaternion {
+		c.Orientation = orientation
+	} else {
+		c.Yaw, c.Pitch = EulerAngles(orientation)
+		c.Pitch = mgl64.Clamp(c.Pitch, -89.0, 89.0)
+	}
+	c.updateVectors()
+}
+
+// RotateTowards turns the camera smoothly towards orientation along the shortest arc,
+// to be called every frame. The higher sharpness the faster it gets there, whatever the
+// frame rate.
+func (c *Camera) RotateTowards(orientation mgl32.Quat, sharpness, deltaTime float64) {
+	c.SetOrientation(mgl32.QuatSlerp(c.Orientation, orientation, smoothing(sharpness, deltaTime)))
+}
+
+// Roll turns the camera angle degrees clockwise around the direction of the view. Only
+// the Quaternion mode rolls.
+func (c *Camera) Roll(angle float64) {
+	if c.OrientationMode != Quaternion {
+		return
+	}
+	c.Orientation = turn(c.Orientation, viewFront, angle)
+	c.updateVectors()
+}
+
+// ProcessMouseMovement turns the camera right by xoffset and up by yoffset. In the
+// Quaternion mode it turns around its own axes and constrainPitch is ignored, so it can
+// loop.
 func (c *Camera) ProcessMouseMovement(xoffset, yoffset float64, constrainPitch bool) {
 	xoffset *= c.MouseSensitivity
 	yoffset *= c.MouseSensitivity
 
+	if c.OrientationMode == Quaternion {
+		c.Orientation = turn(c.Orientation, viewUp, -xoffset)
+		c.Orientation = turn(c.Orientation, viewRight, yoffset)
+		c.updateVectors()
+		return
+	}
+
 	c.Yaw += xoffset
 	c.Pitch += yoffset
 
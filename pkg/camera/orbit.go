@@ -0,0 +1,132 @@
+package camera
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/igoramorim/gopengl/pkg/bounds"
+)
+
+// NewOrbit returns an orbit camera looking at target from distance, along -Z like New.
+func NewOrbit(target mgl32.Vec3, distance float32) *Orbit {
+	return &Orbit{
+		Projection:        defaultProjection(),
+		Target:            target,
+		Distance:          distance,
+		Yaw:               -90.0,
+		Pitch:             0.0,
+		WorldUp:           mgl32.Vec3{0.0, 1.0, 0.0},
+		MinDistance:       0.1,
+		MaxDistance:       100.0,
+		RotateSensitivity: 0.3,
+		PanSensitivity:    0.002,
+		ZoomSpeed:         0.1,
+	}
+}
+
+// Orbit is a camera that rotates around a target, to inspect a model. It looks along
+// the direction given by Yaw and Pitch, like Camera, from Distance away.
+type Orbit struct {
+	Projection
+	Target   mgl32.Vec3
+	Distance float32
+	Yaw      float64
+	Pitch    float64
+	WorldUp  mgl32.Vec3
+	// MinDistance and MaxDistance bound the zoom.
+	MinDistance float32
+	MaxDistance float32
+	// RotateSensitivity is in degrees by unit of Rotate, e.g. a pixel of the mouse.
+	RotateSensitivity float64
+	// PanSensitivity is the share of Distance moved by unit of Pan.
+	PanSensitivity float32
+	// ZoomSpeed is the share of Distance moved by unit of Zoom.
+	ZoomSpeed float64
+}
+
+// front returns the direction the camera looks at.
+func (o *Orbit) front() mgl32.Vec3 {
+	return mgl32.Vec3{
+		float32(math.Cos(mgl64.DegToRad(o.Yaw)) * math.Cos(mgl64.DegToRad(o.Pitch))),
+		float32(math.Sin(mgl64.DegToRad(o.Pitch))),
+		float32(math.Sin(mgl64.DegToRad(o.Yaw)) * math.Cos(mgl64.DegToRad(o.Pitch))),
+	}.Normalize()
+}
+
+// axes returns the right and up directions of the view.
+func (o *Orbit) axes() (mgl32.Vec3, mgl32.Vec3) {
+	front := o.front()
+	right := front.Cross(o.WorldUp).Normalize()
+	up := right.Cross(front).Normalize()
+	return right, up
+}
+
+// Eye returns the position of the camera.
+func (o *Orbit) Eye() mgl32.Vec3 {
+	return o.Target.Sub(o.front().Mul(o.Distance))
+}
+
+func (o *Orbit) ViewMatrix() mgl32.Mat4 {
+	_, up := o.axes()
+	return mgl32.LookAtV(o.Eye(), o.Target, up)
+}
+
+func (o *Orbit) ViewProjection() mgl32.Mat4 {
+	return o.ProjectionMatrix().Mul4(o.ViewMatrix())
+}
+
+func (o *Orbit) Frustum() bounds.Frustum {
+	return bounds.FrustumOf(o.ViewProjection())
+}
+
+// Rotate turns the camera around the target, by xoffset to the right and yoffset up,
+// scaled by RotateSensitivity. The pitch stays within 89 degrees, so the view does not
+// flip over the poles.
+func (o *Orbit) Rotate(xoffset, yoffset float64) {
+	o.Yaw += xoffset * o.RotateSensitivity
+	o.Pitch = mgl64.Clamp(o.Pitch+yoffset*o.RotateSensitivity, -89.0, 89.0)
+}
+
+// Pan moves the target in the plane of the view, so the scene follows a drag by xoffset
+// to the right and yoffset up. It moves more the farther the camera is.
+func (o *Orbit) Pan(xoffset, yoffset float64) {
+	right, up := o.axes()
+	scale := o.Distance * o.PanSensitivity
+	o.Target = o.Target.
+		Sub(right.Mul(float32(xoffset) * scale)).
+		Sub(up.Mul(float32(yoffset) * scale))
+}
+
+// Zoom moves the camera closer to the target, or away when amount is negative, by a
+// share of the distance so it slows down near the target. The orthographic view shrinks
+// as much.
+func (o *Orbit) Zoom(amount float64) {
+	distance := float64(o.Distance) * math.Pow(1.0-o.ZoomSpeed, amount)
+	distance32 := mgl32.Clamp(float32(distance), o.MinDistance, o.MaxDistance)
+
+	if o.Distance > 0 {
+		o.OrthoHeight *= distance32 / o.Distance
+	}
+	o.Distance = distance32
+}
+
+// Frame targets the center of the box from lower to upper, from the distance where it
+// fills the height of the view, and fits the zoom range and the clipping planes to its
+// size. The direction of the view is kept.
+func (o *Orbit) Frame(lower, upper mgl32.Vec3) {
+	radius := upper.Sub(lower).Len() / 2.0
+	if radius == 0 {
+		radius = 1.0
+	}
+
+	o.Target = lower.Add(upper).Mul(0.5)
+	halfFov := mgl64.DegToRad(o.Fov) / 2.0
+	o.Distance = radius / float32(math.Sin(halfFov))
+	o.OrthoHeight = radius * 2.0
+
+	o.MinDistance = radius * 0.1
+	o.MaxDistance = radius * 50.0
+	o.Near = radius * 0.01
+	o.Far = o.MaxDistance + radius*2.0
+}
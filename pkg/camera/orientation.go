@@ -0,0 +1,72 @@
+package camera
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/go-gl/mathgl/mgl64"
+)
+
+type OrientationMode int
+
+const (
+	// Euler orients the camera by Yaw and Pitch. The pitch is clamped, so the view never
+	// turns upside down, and it cannot roll.
+	Euler OrientationMode = iota
+	// Quaternion orients the camera by Orientation, turning around its own axes. It can
+	// loop and roll freely, e.g. for flight-style navigation.
+	Quaternion
+)
+
+// The axes of the view space: the camera looks along -Z with +Y up.
+var (
+	viewFront = mgl32.Vec3{0.0, 0.0, -1.0}
+	viewUp    = mgl32.Vec3{0.0, 1.0, 0.0}
+	viewRight = mgl32.Vec3{1.0, 0.0, 0.0}
+)
+
+// YawPitch returns the orientation looking in the direction of the Euler angles, in
+// degrees, of Camera: a yaw of -90 and a pitch of 0 look along -Z.
+func YawPitch(yaw, pitch float64) mgl32.Quat {
+	// At the identity the camera looks along -Z, which is a yaw of -90
+	turn := mgl32.QuatRotate(float32(mgl64.DegToRad(-(yaw + 90.0))), viewUp)
+	tilt := mgl32.QuatRotate(float32(mgl64.DegToRad(pitch)), viewRight)
+	return turn.Mul(tilt).Normalize()
+}
+
+// EulerAngles returns the yaw and pitch, in degrees, looking in the direction of
+// orientation. Its roll is lost.
+func EulerAngles(orientation mgl32.Quat) (yaw, pitch float64) {
+	front := orientation.Rotate(viewFront)
+	y := mgl64.Clamp(float64(front.Y()), -1.0, 1.0)
+	pitch = mgl64.RadToDeg(math.Asin(y))
+	yaw = mgl64.RadToDeg(math.Atan2(float64(front.Z()), float64(front.X())))
+	return yaw, pitch
+}
+
+// LookRotation returns the orientation looking along front with up as close to up as
+// possible. front and up must not be parallel.
+func LookRotation(front, up mgl32.Vec3) mgl32.Quat {
+	f := front.Normalize()
+	r := f.Cross(up).Normalize()
+	u := r.Cross(f)
+
+	// The columns are where the axes of the view space end up
+	basis := mgl32.Mat3FromCols(r, u, f.Mul(-1.0))
+	return mgl32.Mat4ToQuat(basis.Mat4()).Normalize()
+}
+
+// turn returns orientation rotated by angle degrees around axis, an axis of the view
+// space, so the rotation follows the current orientation.
+func turn(orientation mgl32.Quat, axis mgl32.Vec3, angle float64) mgl32.Quat {
+	rotation := mgl32.QuatRotate(float32(mgl64.DegToRad(angle)), axis)
+	// Renormalized so the errors do not build up over the frames
+	return orientation.Mul(rotation).Normalize()
+}
+
+// smoothing returns the share of the way to a target covered in deltaTime seconds when
+// the remaining way decays exponentially at the rate sharpness. Covering it in two steps
+// gets as far as in one of their total time, so it does not depend on the frame rate.
+func smoothing(sharpness, deltaTime float64) float32 {
+	return float32(1.0 - math.Exp(-sharpness*deltaTime))
+}
@@ -0,0 +1,180 @@
+package camera
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const epsilon = 1e-4
+
+// near reports whether a and b are less than epsilon apart.
+func near(a, b mgl32.Vec3) bool {
+	return a.Sub(b).Len() < epsilon
+}
+
+func TestYawPitch(t *testing.T) {
+	diagonal := float32(math.Sqrt2 / 2)
+
+	tests := []struct {
+		yaw, pitch float64
+		front      mgl32.Vec3
+	}{
+		{yaw: -90, pitch: 0, front: mgl32.Vec3{0, 0, -1}},
+		{yaw: 0, pitch: 0, front: mgl32.Vec3{1, 0, 0}},
+		{yaw: 90, pitch: 0, front: mgl32.Vec3{0, 0, 1}},
+		{yaw: 180, pitch: 0, front: mgl32.Vec3{-1, 0, 0}},
+		{yaw: -90, pitch: 45, front: mgl32.Vec3{0, diagonal, -diagonal}},
+		{yaw: 0, pitch: -90, front: mgl32.Vec3{0, -1, 0}},
+	}
+
+	for _, tt := range tests {
+		q := YawPitch(tt.yaw, tt.pitch)
+
+		if got := q.Rotate(viewFront); !near(got, tt.front) {
+			t.Errorf("YawPitch(%v, %v) looks along %v, want %v", tt.yaw, tt.pitch, got, tt.front)
+		}
+		// Without roll the right of the view stays level
+		if right := q.Rotate(viewRight); math.Abs(float64(right.Y())) > epsilon {
+			t.Errorf("YawPitch(%v, %v) rolls, its right is %v", tt.yaw, tt.pitch, right)
+		}
+	}
+}
+
+func TestYawPitchMatchesEuler(t *testing.T) {
+	// The Euler mode computes its front from the angles, the orientation must agree
+	c := New(WithYawPitch(30, -20))
+	if got := c.Orientation.Rotate(viewFront); !near(got, c.Front) {
+		t.Errorf("orientation looks along %v, the front is %v", got, c.Front)
+	}
+	if got := c.Orientation.Rotate(viewUp); !near(got, c.Up) {
+		t.Errorf("orientation up is %v, the up is %v", got, c.Up)
+	}
+}
+
+func TestEulerAnglesRoundTrip(t *testing.T) {
+	for _, yaw := range []float64{-170, -90, 0, 45, 135, 180} {
+		for _, pitch := range []float64{-89, -60, 0, 30, 89} {
+			gotYaw, gotPitch := EulerAngles(YawPitch(yaw, pitch))
+
+			// The yaw is in (-180, 180]
+			if math.Abs(math.Remainder(gotYaw-yaw, 360)) > 1e-3 || math.Abs(gotPitch-pitch) > 1e-3 {
+				t.Errorf("EulerAngles(YawPitch(%v, %v)) = %v, %v", yaw, pitch, gotYaw, gotPitch)
+			}
+		}
+	}
+}
+
+func TestRoll(t *testing.T) {
+	tests := []struct {
+		name  string
+		mode  OrientationMode
+		up    mgl32.Vec3
+		right mgl32.Vec3
+	}{
+		// Clockwise seen from the camera, the up turns to the right
+		{name: "quaternion", mode: Quaternion, up: mgl32.Vec3{1, 0, 0}, right: mgl32.Vec3{0, -1, 0}},
+		{name: "euler", mode: Euler, up: mgl32.Vec3{0, 1, 0}, right: mgl32.Vec3{1, 0, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(WithOrientationMode(tt.mode))
+			front := c.Front
+
+			c.Roll(90)
+
+			if !near(c.Front, front) {
+				t.Errorf("front = %v, want it kept at %v", c.Front, front)
+			}
+			if !near(c.Up, tt.up) {
+				t.Errorf("up = %v, want %v", c.Up, tt.up)
+			}
+			if !near(c.Right, tt.right) {
+				t.Errorf("right = %v, want %v", c.Right, tt.right)
+			}
+		})
+	}
+}
+
+func TestPitchPastVertical(t *testing.T) {
+	sin89, cos89 := float32(math.Sin(89*math.Pi/180)), float32(math.Cos(89*math.Pi/180))
+	sin120, cos120 := float32(math.Sin(120*math.Pi/180)), float32(math.Cos(120*math.Pi/180))
+
+	tests := []struct {
+		name  string
+		mode  OrientationMode
+		front mgl32.Vec3
+		up    mgl32.Vec3
+	}{
+		// Over the top, upside down and looking back
+		{name: "quaternion", mode: Quaternion, front: mgl32.Vec3{0, sin120, -cos120}, up: mgl32.Vec3{0, cos120, sin120}},
+		// Clamped just before the top
+		{name: "euler", mode: Euler, front: mgl32.Vec3{0, sin89, -cos89}, up: mgl32.Vec3{0, cos89, sin89}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(WithOrientationMode(tt.mode))
+
+			// 120 degrees up with the default sensitivity
+			c.ProcessMouseMovement(0, 1200, true)
+
+			if !near(c.Front, tt.front) {
+				t.Errorf("front = %v, want %v", c.Front, tt.front)
+			}
+			if !near(c.Up, tt.up) {
+				t.Errorf("up = %v, want %v", c.Up, tt.up)
+			}
+		})
+	}
+}
+
+func TestRotateTowards(t *testing.T) {
+	const sharpness = 4.0
+
+	tests := []struct {
+		name   string
+		mode   OrientationMode
+		target mgl32.Quat
+		// samePath is whether the camera is at the same place after a second whatever the
+		// frame rate. The Euler mode drops the roll of the arc on every step, which bends
+		// its path a little unless it only turns around the world up.
+		samePath bool
+	}{
+		{name: "quaternion", mode: Quaternion, target: YawPitch(0, 30), samePath: true},
+		{name: "euler turning", mode: Euler, target: YawPitch(0, 0), samePath: true},
+		{name: "euler", mode: Euler, target: YawPitch(0, 30)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			targetFront := tt.target.Rotate(viewFront)
+
+			// The same seconds at different frame rates
+			var first mgl32.Vec3
+			for _, fps := range []int{1, 10, 60, 240} {
+				c := New(WithOrientationMode(tt.mode))
+				for range fps {
+					c.RotateTowards(tt.target, sharpness, 1.0/float64(fps))
+				}
+				if near(c.Front, targetFront) {
+					t.Errorf("%d fps: got to the target in a second, want it on the way", fps)
+				}
+				if fps == 1 {
+					first = c.Front
+				} else if tt.samePath && !near(c.Front, first) {
+					t.Errorf("%d fps: front after a second = %v, want %v as at 1 fps", fps, c.Front, first)
+				}
+
+				for range 9 * fps {
+					c.RotateTowards(tt.target, sharpness, 1.0/float64(fps))
+				}
+				if !near(c.Front, targetFront) {
+					t.Errorf("%d fps: front after 10 seconds = %v, want the target %v", fps, c.Front, targetFront)
+				}
+			}
+		})
+	}
+}
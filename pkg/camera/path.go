@@ -0,0 +1,252 @@
+package camera
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Easing shapes the way between two keyframes, from 0 at the first to 1 at the next.
+type Easing string
+
+const (
+	Linear Easing = "linear"
+	// EaseIn starts slowly and arrives at full speed.
+	EaseIn Easing = "ease-in"
+	// EaseOut starts at full speed and slows down to stop at the keyframe.
+	EaseOut Easing = "ease-out"
+	// EaseInOut starts and stops slowly.
+	EaseInOut Easing = "ease-in-out"
+)
+
+// Ease returns how far along the way the easing is at t, from 0 to 1. The empty easing
+// is Linear.
+func (e Easing) Ease(t float64) float64 {
+	t = math.Max(0.0, math.Min(t, 1.0))
+
+	switch e {
+	case EaseIn:
+		return t * t * t
+	case EaseOut:
+		u := 1.0 - t
+		return 1.0 - u*u*u
+	case EaseInOut:
+		return t * t * (3.0 - 2.0*t)
+	default:
+		return t
+	}
+}
+
+func (e Easing) valid() bool {
+	switch e {
+	case "", Linear, EaseIn, EaseOut, EaseInOut:
+		return true
+	}
+	return false
+}
+
+// Keyframe is a pose of the camera along a Path.
+type Keyframe struct {
+	// Time is when the camera is at the keyframe, in seconds.
+	Time        float64
+	Position    mgl32.Vec3
+	Orientation mgl32.Quat
+	// Fov is the vertical field of view, in degrees.
+	Fov float64
+	// Easing shapes the way to the next keyframe.
+	Easing Easing
+}
+
+// keyframeJSON is how a Keyframe is stored, its orientation as x y z w like glTF.
+type keyframeJSON struct {
+	Time        float64    `json:"time"`
+	Position    [3]float32 `json:"position"`
+	Orientation [4]float32 `json:"orientation"`
+	Fov         float64    `json:"fov"`
+	Easing      Easing     `json:"easing,omitempty"`
+}
+
+func (k Keyframe) MarshalJSON() ([]byte, error) {
+	q := k.Orientation
+	return json.Marshal(keyframeJSON{
+		Time:        k.Time,
+		Position:    k.Position,
+		Orientation: [4]float32{q.V[0], q.V[1], q.V[2], q.W},
+		Fov:         k.Fov,
+		Easing:      k.Easing,
+	})
+}
+
+func (k *Keyframe) UnmarshalJSON(data []byte) error {
+	var kj keyframeJSON
+	if err := json.Unmarshal(data, &kj); err != nil {
+		return err
+	}
+
+	o := kj.Orientation
+	*k = Keyframe{
+		Time:        kj.Time,
+		Position:    kj.Position,
+		Orientation: mgl32.Quat{W: o[3], V: mgl32.Vec3{o[0], o[1], o[2]}},
+		Fov:         kj.Fov,
+		Easing:      kj.Easing,
+	}
+	return nil
+}
+
+// Path is a scripted move of the camera through keyframes sorted by time. The positions
+// follow a Catmull-Rom spline, which passes through all of them, and the orientations
+// turn along the shortest arc.
+type Path struct {
+	Keyframes []Keyframe `json:"keyframes"`
+	// Loop starts over at the first keyframe after the last one, otherwise the camera
+	// stays there.
+	Loop bool `json:"loop"`
+}
+
+// LoadPath reads the JSON path in file, see Save.
+func LoadPath(file string) (*Path, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("camera: %w", err)
+	}
+
+	var p Path
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("camera: %s: %w", file, err)
+	}
+
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+
+	for i := range p.Keyframes {
+		p.Keyframes[i].Orientation = p.Keyframes[i].Orientation.Normalize()
+	}
+
+	return &p, nil
+}
+
+// Save writes the path to file as JSON.
+func (p *Path) Save(file string) error {
+	data, err := json.MarshalIndent(p, "", "\t")
+	if err != nil {
+		return fmt.Errorf("camera: %w", err)
+	}
+
+	if err := os.WriteFile(file, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("camera: %w", err)
+	}
+	return nil
+}
+
+// Validate reports whether the path has keyframes, each after the previous one, with
+// orientations and known easings.
+func (p *Path) Validate() error {
+	if len(p.Keyframes) == 0 {
+		return errors.New("camera: path without keyframes")
+	}
+
+	var errs []error
+	for i, k := range p.Keyframes {
+		if i > 0 && k.Time <= p.Keyframes[i-1].Time {
+			errs = append(errs, fmt.Errorf("camera: path: keyframes[%d]: time %g is not after the previous one", i, k.Time))
+		}
+		if k.Orientation.Len() == 0 {
+			errs = append(errs, fmt.Errorf("camera: path: keyframes[%d]: no orientation", i))
+		}
+		if !k.Easing.valid() {
+			errs = append(errs, fmt.Errorf("camera: path: keyframes[%d]: unknown easing %q", i, k.Easing))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Duration returns the time from the first keyframe to the last one.
+func (p *Path) Duration() float64 {
+	if len(p.Keyframes) == 0 {
+		return 0
+	}
+	return p.Keyframes[len(p.Keyframes)-1].Time - p.Keyframes[0].Time
+}
+
+// Record adds a keyframe of the pose of c at time, which must be after the last keyframe.
+func (p *Path) Record(c *Camera, time float64) {
+	p.Keyframes = append(p.Keyframes, Keyframe{
+		Time:        time,
+		Position:    c.Position,
+		Orientation: c.Orientation,
+		Fov:         c.Fov,
+	})
+}
+
+// Sample returns the pose along the path at time, the same for the same time so
+// captures can be repeated. Before the first keyframe the camera is at it, and after the
+// last one too unless the path loops. A path without keyframes returns a zero keyframe.
+func (p *Path) Sample(time float64) Keyframe {
+	n := len(p.Keyframes)
+	if n == 0 {
+		return Keyframe{}
+	}
+
+	first, last := p.Keyframes[0], p.Keyframes[n-1]
+	if p.Loop && p.Duration() > 0 {
+		time = first.Time + math.Mod(time-first.Time, p.Duration())
+		if time < first.Time {
+			time += p.Duration()
+		}
+	}
+
+	if time <= first.Time {
+		return first
+	}
+	if time >= last.Time {
+		return last
+	}
+
+	// The keyframe starting the segment holding time
+	i := sort.Search(n, func(i int) bool { return p.Keyframes[i].Time > time }) - 1
+	k0, k1 := p.Keyframes[i], p.Keyframes[i+1]
+
+	t := 1.0
+	if span := k1.Time - k0.Time; span > 0 {
+		t = (time - k0.Time) / span
+	}
+	t = k0.Easing.Ease(t)
+
+	// The ends are repeated as the missing neighbors
+	before := p.Keyframes[max(i-1, 0)].Position
+	after := p.Keyframes[min(i+2, n-1)].Position
+
+	return Keyframe{
+		Time:        time,
+		Position:    catmullRom(before, k0.Position, k1.Position, after, float32(t)),
+		Orientation: mgl32.QuatSlerp(k0.Orientation, k1.Orientation, float32(t)),
+		Fov:         k0.Fov + (k1.Fov-k0.Fov)*t,
+		Easing:      k0.Easing,
+	}
+}
+
+// catmullRom returns the point at t of the uniform Catmull-Rom segment from p1 to p2.
+func catmullRom(p0, p1, p2, p3 mgl32.Vec3, t float32) mgl32.Vec3 {
+	t2 := t * t
+	t3 := t2 * t
+
+	return p1.Mul(2.0).
+		Add(p2.Sub(p0).Mul(t)).
+		Add(p0.Mul(2.0).Sub(p1.Mul(5.0)).Add(p2.Mul(4.0)).Sub(p3).Mul(t2)).
+		Add(p1.Mul(3.0).Sub(p0).Sub(p2.Mul(3.0)).Add(p3).Mul(t3)).
+		Mul(0.5)
+}
+
+// Follow moves the camera to the pose of k. In the Euler mode the roll is dropped.
+func (c *Camera) Follow(k Keyframe) {
+	c.Position = k.Position
+	c.Fov = k.Fov
+	c.SetOrientation(k.Orientation)
+}
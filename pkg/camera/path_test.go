@@ -0,0 +1,245 @@
+package camera
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// newTestPath returns a path of three keyframes, 2 seconds apart, turning and zooming in.
+func newTestPath() *Path {
+	return &Path{Keyframes: []Keyframe{
+		{Time: 1, Position: mgl32.Vec3{0, 0, 3}, Orientation: YawPitch(-90, 0), Fov: 45},
+		{Time: 3, Position: mgl32.Vec3{3, 1, 0}, Orientation: YawPitch(-180, 10), Fov: 40, Easing: EaseInOut},
+		{Time: 5, Position: mgl32.Vec3{0, 2, -3}, Orientation: YawPitch(-270, 0), Fov: 30},
+	}}
+}
+
+func TestEasing(t *testing.T) {
+	tests := []struct {
+		easing Easing
+		// want are the values at 0, 0.5 and 1
+		want [3]float64
+	}{
+		{easing: "", want: [3]float64{0, 0.5, 1}},
+		{easing: Linear, want: [3]float64{0, 0.5, 1}},
+		{easing: EaseIn, want: [3]float64{0, 0.125, 1}},
+		{easing: EaseOut, want: [3]float64{0, 0.875, 1}},
+		{easing: EaseInOut, want: [3]float64{0, 0.5, 1}},
+	}
+
+	for _, tt := range tests {
+		for i, x := range []float64{0, 0.5, 1} {
+			if got := tt.easing.Ease(x); got != tt.want[i] {
+				t.Errorf("%q.Ease(%v) = %v, want %v", tt.easing, x, got, tt.want[i])
+			}
+		}
+		// Outside of [0, 1] it is clamped
+		if got := tt.easing.Ease(-1); got != 0 {
+			t.Errorf("%q.Ease(-1) = %v, want 0", tt.easing, got)
+		}
+		if got := tt.easing.Ease(2); got != 1 {
+			t.Errorf("%q.Ease(2) = %v, want 1", tt.easing, got)
+		}
+	}
+}
+
+func TestCatmullRom(t *testing.T) {
+	p0, p1, p2, p3 := mgl32.Vec3{-1, 0, 0}, mgl32.Vec3{0, 1, 0}, mgl32.Vec3{2, 1, 0}, mgl32.Vec3{3, 0, 0}
+
+	// The segment goes through its middle points
+	if got := catmullRom(p0, p1, p2, p3, 0); !near(got, p1) {
+		t.Errorf("at 0 = %v, want %v", got, p1)
+	}
+	if got := catmullRom(p0, p1, p2, p3, 1); !near(got, p2) {
+		t.Errorf("at 1 = %v, want %v", got, p2)
+	}
+
+	// Evenly spaced points on a line are followed at a constant speed
+	a, b, c, d := mgl32.Vec3{0, 0, 0}, mgl32.Vec3{1, 1, 1}, mgl32.Vec3{2, 2, 2}, mgl32.Vec3{3, 3, 3}
+	for _, x := range []float32{0.25, 0.5, 0.75} {
+		want := b.Add(c.Sub(b).Mul(x))
+		if got := catmullRom(a, b, c, d, x); !near(got, want) {
+			t.Errorf("line at %v = %v, want %v", x, got, want)
+		}
+	}
+}
+
+func TestSampleEndpoints(t *testing.T) {
+	p := newTestPath()
+	first, last := p.Keyframes[0], p.Keyframes[2]
+
+	tests := []struct {
+		time float64
+		want Keyframe
+	}{
+		{time: 1, want: first},
+		{time: 0, want: first},
+		{time: 5, want: last},
+		{time: 9, want: last},
+	}
+
+	for _, tt := range tests {
+		if got := p.Sample(tt.time); got != tt.want {
+			t.Errorf("Sample(%v) = %+v, want %+v", tt.time, got, tt.want)
+		}
+	}
+
+	// The spline goes through the keyframes in the middle too
+	middle := p.Sample(3)
+	if !near(middle.Position, p.Keyframes[1].Position) || middle.Fov != p.Keyframes[1].Fov {
+		t.Errorf("Sample(3) = %+v, want the pose of %+v", middle, p.Keyframes[1])
+	}
+	if got, want := middle.Orientation.Rotate(viewFront), p.Keyframes[1].Orientation.Rotate(viewFront); !near(got, want) {
+		t.Errorf("Sample(3) looks along %v, want %v", got, want)
+	}
+}
+
+func TestSampleBetween(t *testing.T) {
+	p := newTestPath()
+
+	// Halfway through the linear segment
+	k := p.Sample(2)
+	if k.Fov != 42.5 {
+		t.Errorf("Sample(2) fov = %v, want 42.5", k.Fov)
+	}
+	wantFront := mgl32.QuatSlerp(p.Keyframes[0].Orientation, p.Keyframes[1].Orientation, 0.5).Rotate(viewFront)
+	if got := k.Orientation.Rotate(viewFront); !near(got, wantFront) {
+		t.Errorf("Sample(2) looks along %v, want %v", got, wantFront)
+	}
+
+	// The ease-in-out segment is halfway at its middle but slower near its ends
+	if got := p.Sample(4).Fov; got != 35 {
+		t.Errorf("Sample(4) fov = %v, want 35", got)
+	}
+	if got, linear := p.Sample(3.5).Fov, 37.5; got <= linear {
+		t.Errorf("Sample(3.5) fov = %v, want it closer to 40 than linear %v", got, linear)
+	}
+
+	// The same time gives the same pose
+	if a, b := p.Sample(2.7), p.Sample(2.7); a != b {
+		t.Errorf("Sample(2.7) = %+v, then %+v", a, b)
+	}
+}
+
+func TestSampleLoop(t *testing.T) {
+	p := newTestPath()
+	p.Loop = true
+
+	// The path lasts 4 seconds from 1
+	tests := []struct {
+		time, same float64
+	}{
+		{time: 6, same: 2},
+		{time: 10.5, same: 2.5},
+		{time: -0.5, same: 3.5},
+		{time: 9, same: 1},
+	}
+
+	for _, tt := range tests {
+		got, want := p.Sample(tt.time), p.Sample(tt.same)
+		if got.Time != want.Time || !near(got.Position, want.Position) || math.Abs(got.Fov-want.Fov) > 1e-9 {
+			t.Errorf("Sample(%v) = %+v, want %+v as at %v", tt.time, got, want, tt.same)
+		}
+	}
+
+	p.Loop = false
+	if got := p.Sample(6); got != p.Keyframes[2] {
+		t.Errorf("Sample(6) without loop = %+v, want the last keyframe", got)
+	}
+}
+
+func TestPathValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(p *Path)
+		// want is part of the error, empty when there is none
+		want string
+	}{
+		{name: "valid", mutate: func(p *Path) {}},
+		{name: "empty", mutate: func(p *Path) { p.Keyframes = nil }, want: "path without keyframes"},
+		{name: "same time", mutate: func(p *Path) { p.Keyframes[1].Time = 1 }, want: "keyframes[1]: time 1 is not after the previous one"},
+		{name: "going back", mutate: func(p *Path) { p.Keyframes[2].Time = 2 }, want: "keyframes[2]: time 2 is not after the previous one"},
+		{name: "no orientation", mutate: func(p *Path) { p.Keyframes[0].Orientation = mgl32.Quat{} }, want: "keyframes[0]: no orientation"},
+		{name: "unknown easing", mutate: func(p *Path) { p.Keyframes[0].Easing = "bounce" }, want: `keyframes[0]: unknown easing "bounce"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestPath()
+			tt.mutate(p)
+
+			err := p.Validate()
+			if tt.want == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Validate() = %v, want an error with %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSaveLoadPath(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "camera_path.json")
+
+	p := newTestPath()
+	p.Loop = true
+	if err := p.Save(file); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadPath(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded, p) {
+		t.Errorf("LoadPath() = %+v, want %+v", loaded, p)
+	}
+
+	// The same poses are played from the file
+	for _, time := range []float64{0, 1.3, 2.9, 4.4, 7} {
+		if got, want := loaded.Sample(time), p.Sample(time); got != want {
+			t.Errorf("Sample(%v) of the loaded path = %+v, want %+v", time, got, want)
+		}
+	}
+}
+
+func TestLoadPathErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{name: "not json", data: "keyframes", want: "invalid character"},
+		{name: "invalid", data: `{"keyframes": []}`, want: "path without keyframes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(dir, tt.name+".json")
+			if err := os.WriteFile(file, []byte(tt.data), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := LoadPath(file)
+			if err == nil || !strings.Contains(err.Error(), tt.want) || !strings.Contains(err.Error(), file) {
+				t.Errorf("LoadPath() = %v, want an error of %s with %q", err, file, tt.want)
+			}
+		})
+	}
+
+	if _, err := LoadPath(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("LoadPath of a missing file succeeded")
+	}
+}
@@ -0,0 +1,58 @@
+package camera
+
+import "github.com/go-gl/mathgl/mgl32"
+
+type ProjectionMode int
+
+const (
+	// Perspective makes the far objects smaller, by the field of view.
+	Perspective ProjectionMode = iota
+	// Orthographic keeps the size of the objects at any distance, e.g. for plans and
+	// technical views.
+	Orthographic
+)
+
+// Projection maps the view space of a camera to the clip space. Cameras embed it.
+type Projection struct {
+	Mode ProjectionMode
+	// Fov is the vertical field of view of the perspective projection, in degrees.
+	Fov float64
+	// OrthoHeight is the height of the view of the orthographic projection, in units of
+	// the world.
+	OrthoHeight float32
+	// Near and Far are the distances of the clipping planes.
+	Near float32
+	Far  float32
+	// Aspect is the ratio width / height of the view, see SetAspect.
+	Aspect float32
+}
+
+func defaultProjection() Projection {
+	return Projection{
+		Mode:        Perspective,
+		Fov:         45.0,
+		OrthoHeight: 10.0,
+		Near:        0.1,
+		Far:         100.0,
+		Aspect:      1.0,
+	}
+}
+
+// SetAspect sets the aspect ratio to the one of a framebuffer of width by height pixels,
+// e.g. from the size callback of the window. A minimized window, of height 0, keeps the
+// previous one.
+func (p *Projection) SetAspect(width, height int) {
+	if width <= 0 || height <= 0 {
+		return
+	}
+	p.Aspect = float32(width) / float32(height)
+}
+
+func (p *Projection) ProjectionMatrix() mgl32.Mat4 {
+	if p.Mode == Orthographic {
+		top := p.OrthoHeight / 2.0
+		right := top * p.Aspect
+		return mgl32.Ortho(-right, right, -top, top, p.Near, p.Far)
+	}
+	return mgl32.Perspective(mgl32.DegToRad(float32(p.Fov)), p.Aspect, p.Near, p.Far)
+}
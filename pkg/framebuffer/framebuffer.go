@@ -0,0 +1,229 @@
+// Package framebuffer renders into framebuffer objects instead of the window.
+package framebuffer
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// Attachment describes an image attached to a framebuffer.
+type Attachment struct {
+	// Point is where the image is attached, e.g. gl.COLOR_ATTACHMENT0, gl.DEPTH_ATTACHMENT
+	// or gl.DEPTH_STENCIL_ATTACHMENT.
+	Point uint32
+
+	// InternalFormat is how the image is stored, e.g. gl.RGBA8, gl.RGBA16F,
+	// gl.DEPTH_COMPONENT24 or gl.DEPTH24_STENCIL8.
+	InternalFormat int32
+
+	// Renderbuffer stores the image in a renderbuffer instead of a texture. It can not be
+	// sampled by shaders but the driver may store it more efficiently.
+	Renderbuffer bool
+
+	// Cube stores the image in a cubemap texture attached as layered, so a geometry
+	// shader can draw the six faces at once by setting gl_Layer. Width and height must
+	// be the same.
+	Cube bool
+}
+
+// Color is an RGBA texture attached to gl.COLOR_ATTACHMENT0 + i.
+func Color(i int) Attachment {
+	return Attachment{Point: gl.COLOR_ATTACHMENT0 + uint32(i), InternalFormat: gl.RGBA8}
+}
+
+// Depth is a depth texture, e.g. to be sampled as a shadow map.
+func Depth() Attachment {
+	return Attachment{Point: gl.DEPTH_ATTACHMENT, InternalFormat: gl.DEPTH_COMPONENT24}
+}
+
+// DepthCube is a depth cubemap, e.g. to be sampled as an omnidirectional shadow map.
+func DepthCube() Attachment {
+	return Attachment{Point: gl.DEPTH_ATTACHMENT, InternalFormat: gl.DEPTH_COMPONENT24, Cube: true}
+}
+
+// DepthStencil is a combined depth and stencil renderbuffer, what most scenes need
+// when they are not drawn to the window.
+func DepthStencil() Attachment {
+	return Attachment{Point: gl.DEPTH_STENCIL_ATTACHMENT, InternalFormat: gl.DEPTH24_STENCIL8, Renderbuffer: true}
+}
+
+// New creates a framebuffer of width x height pixels with the attachments.
+func New(width, height int, attachments ...Attachment) (*Framebuffer, error) {
+	if len(attachments) == 0 {
+		return nil, errors.New("framebuffer: at least one attachment is needed")
+	}
+
+	fb := &Framebuffer{
+		attachments: attachments,
+		ids:         make([]uint32, len(attachments)),
+	}
+
+	gl.GenFramebuffers(1, &fb.id)
+	if err := fb.Resize(width, height); err != nil {
+		fb.Delete()
+		return nil, err
+	}
+
+	return fb, nil
+}
+
+type Framebuffer struct {
+	id          uint32
+	width       int
+	height      int
+	attachments []Attachment
+	// ids are the textures or renderbuffers of each attachment
+	ids []uint32
+}
+
+// Resize recreates the attachments with the new size. Their content is lost and the
+// default framebuffer is left bound.
+func (fb *Framebuffer) Resize(width, height int) error {
+	fb.width, fb.height = width, height
+
+	gl.BindFramebuffer(gl.FRAMEBUFFER, fb.id)
+	fb.deleteAttachments()
+
+	var drawBuffers []uint32
+	for i, a := range fb.attachments {
+		if a.Renderbuffer {
+			gl.GenRenderbuffers(1, &fb.ids[i])
+			gl.BindRenderbuffer(gl.RENDERBUFFER, fb.ids[i])
+			gl.RenderbufferStorage(gl.RENDERBUFFER, uint32(a.InternalFormat), int32(width), int32(height))
+			gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, a.Point, gl.RENDERBUFFER, fb.ids[i])
+			gl.BindRenderbuffer(gl.RENDERBUFFER, 0)
+		} else if a.Cube {
+			format, xtype := pixelFormat(a.InternalFormat)
+
+			gl.GenTextures(1, &fb.ids[i])
+			gl.BindTexture(gl.TEXTURE_CUBE_MAP, fb.ids[i])
+			for face := uint32(0); face < 6; face++ {
+				gl.TexImage2D(gl.TEXTURE_CUBE_MAP_POSITIVE_X+face, 0, a.InternalFormat, int32(width), int32(height), 0, format, xtype, nil)
+			}
+			gl.TexParameteri(gl.TEXTURE_CUBE_MAP, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
+			gl.TexParameteri(gl.TEXTURE_CUBE_MAP, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+			gl.TexParameteri(gl.TEXTURE_CUBE_MAP, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+			gl.TexParameteri(gl.TEXTURE_CUBE_MAP, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+			gl.TexParameteri(gl.TEXTURE_CUBE_MAP, gl.TEXTURE_WRAP_R, gl.CLAMP_TO_EDGE)
+			gl.FramebufferTexture(gl.FRAMEBUFFER, a.Point, fb.ids[i], 0)
+			gl.BindTexture(gl.TEXTURE_CUBE_MAP, 0)
+		} else {
+			format, xtype := pixelFormat(a.InternalFormat)
+
+			gl.GenTextures(1, &fb.ids[i])
+			gl.BindTexture(gl.TEXTURE_2D, fb.ids[i])
+			gl.TexImage2D(gl.TEXTURE_2D, 0, a.InternalFormat, int32(width), int32(height), 0, format, xtype, nil)
+			gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
+			gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+			gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+			gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+			gl.FramebufferTexture2D(gl.FRAMEBUFFER, a.Point, gl.TEXTURE_2D, fb.ids[i], 0)
+			gl.BindTexture(gl.TEXTURE_2D, 0)
+		}
+
+		if a.Point >= gl.COLOR_ATTACHMENT0 && a.Point <= gl.COLOR_ATTACHMENT15 {
+			drawBuffers = append(drawBuffers, a.Point)
+		}
+	}
+
+	// Without color attachments, e.g. a shadow map, nothing is drawn nor read
+	if len(drawBuffers) == 0 {
+		gl.DrawBuffer(gl.NONE)
+		gl.ReadBuffer(gl.NONE)
+	} else {
+		gl.DrawBuffers(int32(len(drawBuffers)), &drawBuffers[0])
+	}
+
+	status := gl.CheckFramebufferStatus(gl.FRAMEBUFFER)
+	BindDefault()
+
+	if status != gl.FRAMEBUFFER_COMPLETE {
+		return fmt.Errorf("framebuffer: not complete: 0x%X", status)
+	}
+
+	return nil
+}
+
+// Bind makes the framebuffer the target of the draw calls and sets the viewport to its size.
+func (fb *Framebuffer) Bind() {
+	gl.BindFramebuffer(gl.FRAMEBUFFER, fb.id)
+	gl.Viewport(0, 0, int32(fb.width), int32(fb.height))
+}
+
+func (fb *Framebuffer) ID() uint32 {
+	return fb.id
+}
+
+func (fb *Framebuffer) Width() int {
+	return fb.width
+}
+
+func (fb *Framebuffer) Height() int {
+	return fb.height
+}
+
+// Texture returns the texture, or cubemap, attached at point, or 0 when it is a renderbuffer or
+// nothing is attached there.
+func (fb *Framebuffer) Texture(point uint32) uint32 {
+	for i, a := range fb.attachments {
+		if a.Point == point && !a.Renderbuffer {
+			return fb.ids[i]
+		}
+	}
+	return 0
+}
+
+func (fb *Framebuffer) Delete() {
+	fb.deleteAttachments()
+	gl.DeleteFramebuffers(1, &fb.id)
+}
+
+func (fb *Framebuffer) deleteAttachments() {
+	for i, a := range fb.attachments {
+		if fb.ids[i] == 0 {
+			continue
+		}
+
+		if a.Renderbuffer {
+			gl.DeleteRenderbuffers(1, &fb.ids[i])
+		} else {
+			gl.DeleteTextures(1, &fb.ids[i])
+		}
+		fb.ids[i] = 0
+	}
+}
+
+// pixelFormat returns a format and type compatible with the internal format, needed by
+// gl.TexImage2D even though no pixels are uploaded.
+func pixelFormat(internalFormat int32) (uint32, uint32) {
+	switch internalFormat {
+	case gl.DEPTH_COMPONENT16, gl.DEPTH_COMPONENT24, gl.DEPTH_COMPONENT32, gl.DEPTH_COMPONENT32F:
+		return gl.DEPTH_COMPONENT, gl.FLOAT
+	case gl.DEPTH24_STENCIL8:
+		return gl.DEPTH_STENCIL, gl.UNSIGNED_INT_24_8
+	case gl.RGBA16F, gl.RGBA32F, gl.RGB16F, gl.RGB32F:
+		return gl.RGBA, gl.FLOAT
+	default:
+		return gl.RGBA, gl.UNSIGNED_BYTE
+	}
+}
+
+// defaultID is the framebuffer bound by BindDefault, the window unless SetDefault is used.
+var defaultID uint32
+
+// SetDefault makes BindDefault bind fb instead of the window, so a scene can be drawn
+// offscreen without changes. nil restores the window.
+func SetDefault(fb *Framebuffer) {
+	defaultID = 0
+	if fb != nil {
+		defaultID = fb.id
+	}
+}
+
+// BindDefault makes the window, or the framebuffer set by SetDefault, the target of
+// the draw calls. Scenes should use it instead of binding the framebuffer 0.
+func BindDefault() {
+	gl.BindFramebuffer(gl.FRAMEBUFFER, defaultID)
+}
@@ -0,0 +1,167 @@
+package gltf
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// The component types of accessors, which are the GL enums
+const (
+	componentByte          = 5120
+	componentUnsignedByte  = 5121
+	componentShort         = 5122
+	componentUnsignedShort = 5123
+	componentUnsignedInt   = 5125
+	componentFloat         = 5126
+)
+
+var componentSizes = map[int]int{
+	componentByte:          1,
+	componentUnsignedByte:  1,
+	componentShort:         2,
+	componentUnsignedShort: 2,
+	componentUnsignedInt:   4,
+	componentFloat:         4,
+}
+
+var typeComponents = map[string]int{
+	"SCALAR": 1,
+	"VEC2":   2,
+	"VEC3":   3,
+	"VEC4":   4,
+	"MAT2":   4,
+	"MAT3":   9,
+	"MAT4":   16,
+}
+
+func isIndexComponent(componentType int) bool {
+	switch componentType {
+	case componentUnsignedByte, componentUnsignedShort, componentUnsignedInt:
+		return true
+	}
+	return false
+}
+
+// view returns the bytes of the buffer view, from offset, and its stride for elements of
+// elemSize bytes.
+func (d *document) view(index, offset, elemSize int) ([]byte, int) {
+	v := d.BufferViews[index]
+	data := d.Buffers[v.Buffer].data[v.ByteOffset : v.ByteOffset+v.ByteLength]
+
+	stride := v.ByteStride
+	if stride == 0 {
+		stride = elemSize
+	}
+
+	return data[offset:], stride
+}
+
+// readFloats returns the elements of the accessor as floats, the components of each
+// element one after the other. Normalized integers are mapped to [0, 1] or [-1, 1], the
+// others keep their value.
+//
+// Accessors without a buffer view are zeros, and sparse accessors get the values of
+// their sparse elements replaced. The matrices of 1 and 2 byte components, whose columns
+// are padded to 4 bytes, are not supported.
+func (d *document) readFloats(index int) []float32 {
+	a := d.Accessors[index]
+	components := typeComponents[a.Type]
+	size := componentSizes[a.ComponentType]
+
+	floats := make([]float32, a.Count*components)
+
+	if a.BufferView != nil {
+		data, stride := d.view(*a.BufferView, a.ByteOffset, components*size)
+		for i := 0; i < a.Count; i++ {
+			readElement(floats[i*components:(i+1)*components], data[i*stride:], a.ComponentType, a.Normalized)
+		}
+	}
+
+	if s := a.Sparse; s != nil {
+		indices := d.readIndexView(s.Indices.BufferView, s.Indices.ByteOffset, s.Count, s.Indices.ComponentType)
+		values, stride := d.view(s.Values.BufferView, s.Values.ByteOffset, components*size)
+		for i, target := range indices {
+			if int(target) >= a.Count {
+				// Invalid, but not worth failing the whole asset for
+				continue
+			}
+			readElement(floats[int(target)*components:(int(target)+1)*components], values[i*stride:], a.ComponentType, a.Normalized)
+		}
+	}
+
+	return floats
+}
+
+// readElement reads len(dst) components of componentType from data.
+func readElement(dst []float32, data []byte, componentType int, normalized bool) {
+	for c := range dst {
+		var v float32
+		switch componentType {
+		case componentByte:
+			v = float32(int8(data[c]))
+			if normalized {
+				v = max(v/127, -1)
+			}
+		case componentUnsignedByte:
+			v = float32(data[c])
+			if normalized {
+				v /= 255
+			}
+		case componentShort:
+			v = float32(int16(binary.LittleEndian.Uint16(data[c*2:])))
+			if normalized {
+				v = max(v/32767, -1)
+			}
+		case componentUnsignedShort:
+			v = float32(binary.LittleEndian.Uint16(data[c*2:]))
+			if normalized {
+				v /= 65535
+			}
+		case componentUnsignedInt:
+			v = float32(binary.LittleEndian.Uint32(data[c*4:]))
+		case componentFloat:
+			v = math.Float32frombits(binary.LittleEndian.Uint32(data[c*4:]))
+		}
+		dst[c] = v
+	}
+}
+
+// readIndices returns the elements of an unsigned integer SCALAR accessor, like the
+// indices of a primitive.
+func (d *document) readIndices(index int) []uint32 {
+	a := d.Accessors[index]
+	if a.BufferView == nil && a.Sparse == nil {
+		return make([]uint32, a.Count)
+	}
+	if a.Sparse != nil {
+		// Rare for indices, so they go through the floats, exact up to 2^24
+		floats := d.readFloats(index)
+		indices := make([]uint32, len(floats))
+		for i, f := range floats {
+			indices[i] = uint32(f)
+		}
+		return indices
+	}
+
+	return d.readIndexView(*a.BufferView, a.ByteOffset, a.Count, a.ComponentType)
+}
+
+// readIndexView reads count unsigned integers of componentType from the buffer view.
+func (d *document) readIndexView(view, offset, count, componentType int) []uint32 {
+	data, stride := d.view(view, offset, componentSizes[componentType])
+
+	indices := make([]uint32, count)
+	for i := range indices {
+		element := data[i*stride:]
+		switch componentType {
+		case componentUnsignedByte:
+			indices[i] = uint32(element[0])
+		case componentUnsignedShort:
+			indices[i] = uint32(binary.LittleEndian.Uint16(element))
+		case componentUnsignedInt:
+			indices[i] = binary.LittleEndian.Uint32(element)
+		}
+	}
+
+	return indices
+}
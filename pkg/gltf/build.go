@@ -0,0 +1,394 @@
+package gltf
+
+import (
+	"strconv"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/igoramorim/gopengl/pkg/tangent"
+)
+
+// build turns the validated document into the meshes of its scene.
+func (d *document) build() *Model {
+	model := &Model{}
+
+	for _, m := range d.Materials {
+		model.Materials = append(model.Materials, d.convertMaterial(m))
+	}
+
+	for _, img := range d.Images {
+		data := img.data
+		if img.BufferView != nil {
+			v := d.BufferViews[*img.BufferView]
+			data = d.Buffers[v.Buffer].data[v.ByteOffset : v.ByteOffset+v.ByteLength]
+		}
+		model.Images = append(model.Images, Image{Name: img.Name, MimeType: img.MimeType, Data: data})
+	}
+
+	for _, n := range d.sceneNodes() {
+		d.buildNode(model, n, mgl32.Ident4())
+	}
+
+	d.buildSkeletons(model)
+
+	return model
+}
+
+// buildSkeletons adds the nodes, skins and animations of the document.
+func (d *document) buildSkeletons(model *Model) {
+	for _, n := range d.Nodes {
+		model.Nodes = append(model.Nodes, Node{Name: n.Name, Parent: -1, Transform: n.localTransform()})
+	}
+	for i, n := range d.Nodes {
+		for _, c := range n.Children {
+			model.Nodes[c].Parent = i
+		}
+	}
+
+	for _, s := range d.Skins {
+		skin := Skin{
+			Name:                s.Name,
+			Joints:              s.Joints,
+			InverseBindMatrices: make([]mgl32.Mat4, len(s.Joints)),
+		}
+
+		var floats []float32
+		if s.InverseBindMatrices != nil {
+			floats = d.readFloats(*s.InverseBindMatrices)
+		}
+		for i := range skin.InverseBindMatrices {
+			if floats == nil {
+				skin.InverseBindMatrices[i] = mgl32.Ident4()
+				continue
+			}
+			copy(skin.InverseBindMatrices[i][:], floats[i*16:(i+1)*16])
+		}
+
+		model.Skins = append(model.Skins, skin)
+	}
+
+	for _, a := range d.Animations {
+		animation := Animation{Name: a.Name}
+
+		for _, ch := range a.Channels {
+			if ch.Target.Node == nil {
+				// Targets of extensions
+				continue
+			}
+
+			sampler := a.Samplers[ch.Sampler]
+			interpolation := sampler.Interpolation
+			if interpolation == "" {
+				interpolation = "LINEAR"
+			}
+
+			animation.Channels = append(animation.Channels, Channel{
+				Node:          *ch.Target.Node,
+				Path:          ch.Target.Path,
+				Interpolation: interpolation,
+				Times:         d.readFloats(sampler.Input),
+				Values:        d.readFloats(sampler.Output),
+			})
+		}
+
+		model.Animations = append(model.Animations, animation)
+	}
+}
+
+// sceneNodes returns the root nodes of the default scene, of the first scene when there
+// is no default one, or of all the nodes without a parent when there are no scenes.
+func (d *document) sceneNodes() []int {
+	switch {
+	case d.Scene != nil:
+		return d.Scenes[*d.Scene].Nodes
+	case len(d.Scenes) > 0:
+		return d.Scenes[0].Nodes
+	}
+
+	isChild := make([]bool, len(d.Nodes))
+	for _, n := range d.Nodes {
+		for _, c := range n.Children {
+			isChild[c] = true
+		}
+	}
+
+	var roots []int
+	for i := range d.Nodes {
+		if !isChild[i] {
+			roots = append(roots, i)
+		}
+	}
+	return roots
+}
+
+// buildNode adds the meshes of the node and its children, parent being the transform of
+// its parent to the scene.
+func (d *document) buildNode(model *Model, index int, parent mgl32.Mat4) {
+	n := d.Nodes[index]
+	transform := parent.Mul4(n.localTransform())
+
+	if n.Mesh != nil {
+		m := d.Meshes[*n.Mesh]
+		name := n.Name
+		if name == "" {
+			name = m.Name
+		}
+
+		// Skinned meshes are moved by their joints only
+		meshTransform, skin := transform, -1
+		if n.Skin != nil {
+			meshTransform, skin = mgl32.Ident4(), *n.Skin
+		}
+
+		for _, p := range m.Primitives {
+			if mesh, ok := d.buildPrimitive(p, meshTransform); ok {
+				mesh.Name = name
+				if skin >= 0 {
+					d.skinPrimitive(&mesh, p, skin)
+				}
+				model.Meshes = append(model.Meshes, mesh)
+			}
+		}
+	}
+
+	for _, c := range n.Children {
+		d.buildNode(model, c, transform)
+	}
+}
+
+// localTransform returns the transform of the node to its parent.
+func (n node) localTransform() mgl32.Mat4 {
+	if n.Matrix != nil {
+		// Column major, like mgl32
+		return mgl32.Mat4(*n.Matrix)
+	}
+
+	transform := mgl32.Ident4()
+	if t := n.Translation; t != nil {
+		transform = mgl32.Translate3D(t[0], t[1], t[2])
+	}
+	if r := n.Rotation; r != nil {
+		q := mgl32.Quat{W: r[3], V: mgl32.Vec3{r[0], r[1], r[2]}}
+		transform = transform.Mul4(q.Normalize().Mat4())
+	}
+	if s := n.Scale; s != nil {
+		transform = transform.Mul4(mgl32.Scale3D(s[0], s[1], s[2]))
+	}
+
+	return transform
+}
+
+// buildPrimitive returns the triangles of the primitive, transformed to the scene, or
+// false when it has none.
+func (d *document) buildPrimitive(p primitive, transform mgl32.Mat4) (Mesh, bool) {
+	mesh := Mesh{Material: -1, Skin: -1}
+	if p.Material != nil {
+		mesh.Material = *p.Material
+	}
+
+	positions := vec3s(d.readFloats(p.Attributes["POSITION"]))
+
+	indices := d.primitiveIndices(p)
+	if len(indices) == 0 {
+		return mesh, false
+	}
+
+	mesh.Vertices = make([]Vertex, len(positions))
+	for i, position := range positions {
+		mesh.Vertices[i].Position = position
+	}
+
+	for set := 0; set < MaxTexCoords; set++ {
+		a, ok := p.Attributes["TEXCOORD_"+strconv.Itoa(set)]
+		if !ok {
+			continue
+		}
+		floats := d.readFloats(a)
+		for i := range mesh.Vertices {
+			mesh.Vertices[i].TexCoords[set] = mgl32.Vec2{floats[i*2], floats[i*2+1]}
+		}
+	}
+
+	normals, hasNormals := p.Attributes["NORMAL"]
+	if hasNormals {
+		for i, n := range vec3s(d.readFloats(normals)) {
+			mesh.Vertices[i].Normal = tangent.Normalize(n)
+		}
+	} else {
+		// Flat normals, so the vertices can not be shared by the triangles
+		mesh.Vertices, indices = unweld(mesh.Vertices, indices)
+		for i := 0; i+2 < len(indices); i += 3 {
+			v0, v1, v2 := &mesh.Vertices[indices[i]], &mesh.Vertices[indices[i+1]], &mesh.Vertices[indices[i+2]]
+			n := tangent.Normalize(v1.Position.Sub(v0.Position).Cross(v2.Position.Sub(v0.Position)))
+			v0.Normal, v1.Normal, v2.Normal = n, n, n
+		}
+	}
+
+	tangents, hasTangents := p.Attributes["TANGENT"]
+	// Tangents without normals are ignored, as the specification asks
+	if hasTangents && hasNormals {
+		floats := d.readFloats(tangents)
+		for i := range mesh.Vertices {
+			v := &mesh.Vertices[i]
+			v.Tangent = mgl32.Vec3{floats[i*4], floats[i*4+1], floats[i*4+2]}
+			// w is the handedness of the bitangent
+			v.Bitangent = v.Normal.Cross(v.Tangent).Mul(floats[i*4+3])
+		}
+	} else {
+		generateTangents(mesh.Vertices, indices)
+	}
+
+	transformVertices(mesh.Vertices, transform)
+
+	// A mirroring transform turns the triangles inside out
+	if transform.Det() < 0 {
+		for i := 0; i+2 < len(indices); i += 3 {
+			indices[i+1], indices[i+2] = indices[i+2], indices[i+1]
+		}
+	}
+	mesh.Indices = indices
+
+	return mesh, true
+}
+
+// skinPrimitive sets the joints and weights of the vertices of mesh, built from p.
+func (d *document) skinPrimitive(mesh *Mesh, p primitive, skin int) {
+	joints, hasJoints := p.Attributes["JOINTS_0"]
+	weights, hasWeights := p.Attributes["WEIGHTS_0"]
+	if !hasJoints || !hasWeights {
+		return
+	}
+	mesh.Skin = skin
+
+	// Flat normals unweld the vertices, see buildPrimitive
+	vertexOf := func(i int) int { return i }
+	if _, hasNormals := p.Attributes["NORMAL"]; !hasNormals {
+		indices := d.primitiveIndices(p)
+		vertexOf = func(i int) int { return int(indices[i]) }
+	}
+
+	jointFloats := d.readFloats(joints)
+	weightFloats := d.readFloats(weights)
+	count := uint32(len(d.Skins[skin].Joints))
+
+	for i := range mesh.Vertices {
+		v := &mesh.Vertices[i]
+		src := vertexOf(i)
+		for c := range 4 {
+			joint := uint32(jointFloats[src*4+c])
+			if joint >= count {
+				// Invalid, the vertex is not moved by it
+				continue
+			}
+			v.Joints[c] = joint
+			v.Weights[c] = weightFloats[src*4+c]
+		}
+	}
+}
+
+// primitiveIndices returns the triangles of the primitive, three by three, without the
+// ones with indices out of its attributes.
+func (d *document) primitiveIndices(p primitive) []uint32 {
+	count := d.Accessors[p.Attributes["POSITION"]].Count
+
+	var indices []uint32
+	if p.Indices != nil {
+		indices = d.readIndices(*p.Indices)
+	} else {
+		indices = make([]uint32, count)
+		for i := range indices {
+			indices[i] = uint32(i)
+		}
+	}
+
+	mode := modeTriangles
+	if p.Mode != nil {
+		mode = *p.Mode
+	}
+	indices = triangulate(indices, mode)
+
+	valid := indices[:0]
+	for i := 0; i+2 < len(indices); i += 3 {
+		if int(indices[i]) < count && int(indices[i+1]) < count && int(indices[i+2]) < count {
+			valid = append(valid, indices[i:i+3]...)
+		}
+	}
+
+	return valid
+}
+
+// triangulate returns the triangles, three by three, of the indices of a primitive.
+// Points and lines have none.
+func triangulate(indices []uint32, mode int) []uint32 {
+	var triangles []uint32
+
+	switch mode {
+	case modeTriangles:
+		return indices[:len(indices)/3*3]
+	case modeTriangleStrip:
+		for i := 0; i+2 < len(indices); i++ {
+			// Every other triangle is flipped to keep the winding
+			if i%2 == 0 {
+				triangles = append(triangles, indices[i], indices[i+1], indices[i+2])
+			} else {
+				triangles = append(triangles, indices[i+1], indices[i], indices[i+2])
+			}
+		}
+	case modeTriangleFan:
+		for i := 1; i+1 < len(indices); i++ {
+			triangles = append(triangles, indices[0], indices[i], indices[i+1])
+		}
+	}
+
+	return triangles
+}
+
+// unweld returns a vertex per index.
+func unweld(vertices []Vertex, indices []uint32) ([]Vertex, []uint32) {
+	unwelded := make([]Vertex, len(indices))
+	sequential := make([]uint32, len(indices))
+	for i, index := range indices {
+		unwelded[i] = vertices[index]
+		sequential[i] = uint32(i)
+	}
+	return unwelded, sequential
+}
+
+// generateTangents sets the tangent and bitangent of the vertices along their first
+// texture coordinates.
+func generateTangents(vertices []Vertex, indices []uint32) {
+	positions := make([]mgl32.Vec3, len(vertices))
+	normals := make([]mgl32.Vec3, len(vertices))
+	texCoords := make([]mgl32.Vec2, len(vertices))
+	for i, v := range vertices {
+		positions[i], normals[i], texCoords[i] = v.Position, v.Normal, v.TexCoords[0]
+	}
+
+	tangents, bitangents := tangent.Generate(positions, normals, texCoords, indices)
+	for i := range vertices {
+		vertices[i].Tangent = tangents[i]
+		vertices[i].Bitangent = bitangents[i]
+	}
+}
+
+// transformVertices moves the vertices to the space of the scene. The normals use the
+// inverse transpose, so they stay perpendicular to the faces under non-uniform scales.
+func transformVertices(vertices []Vertex, transform mgl32.Mat4) {
+	linear := transform.Mat3()
+	normalMatrix := linear.Inv().Transpose()
+
+	for i := range vertices {
+		v := &vertices[i]
+		v.Position = transform.Mul4x1(v.Position.Vec4(1)).Vec3()
+		v.Normal = tangent.Normalize(normalMatrix.Mul3x1(v.Normal))
+		v.Tangent = tangent.Normalize(linear.Mul3x1(v.Tangent))
+		v.Bitangent = tangent.Normalize(linear.Mul3x1(v.Bitangent))
+	}
+}
+
+func vec3s(floats []float32) []mgl32.Vec3 {
+	vectors := make([]mgl32.Vec3, len(floats)/3)
+	for i := range vectors {
+		vectors[i] = mgl32.Vec3{floats[i*3], floats[i*3+1], floats[i*3+2]}
+	}
+	return vectors
+}
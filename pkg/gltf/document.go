@@ -0,0 +1,264 @@
+package gltf
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+)
+
+// document is the JSON of a glTF asset. Only the properties needed to draw the meshes
+// and animate their skeletons are decoded, extensions and extras are ignored.
+type document struct {
+	Asset struct {
+		Version    string `json:"version"`
+		MinVersion string `json:"minVersion"`
+	} `json:"asset"`
+
+	Scene       *int         `json:"scene"`
+	Scenes      []scene      `json:"scenes"`
+	Nodes       []node       `json:"nodes"`
+	Meshes      []mesh       `json:"meshes"`
+	Accessors   []accessor   `json:"accessors"`
+	BufferViews []bufferView `json:"bufferViews"`
+	Buffers     []buffer     `json:"buffers"`
+	Materials   []material   `json:"materials"`
+	Textures    []texture    `json:"textures"`
+	Images      []image      `json:"images"`
+	Samplers    []sampler    `json:"samplers"`
+	Skins       []skin       `json:"skins"`
+	Animations  []animation  `json:"animations"`
+
+	// ExtensionsRequired can not be ignored, the asset does not load without them
+	ExtensionsRequired []string `json:"extensionsRequired"`
+}
+
+type scene struct {
+	Name  string `json:"name"`
+	Nodes []int  `json:"nodes"`
+}
+
+type node struct {
+	Name        string       `json:"name"`
+	Children    []int        `json:"children"`
+	Mesh        *int         `json:"mesh"`
+	Skin        *int         `json:"skin"`
+	Matrix      *[16]float32 `json:"matrix"`
+	Translation *[3]float32  `json:"translation"`
+	Rotation    *[4]float32  `json:"rotation"`
+	Scale       *[3]float32  `json:"scale"`
+}
+
+type mesh struct {
+	Name       string      `json:"name"`
+	Primitives []primitive `json:"primitives"`
+}
+
+type primitive struct {
+	Attributes map[string]int `json:"attributes"`
+	Indices    *int           `json:"indices"`
+	Material   *int           `json:"material"`
+	Mode       *int           `json:"mode"`
+}
+
+// The primitive modes
+const (
+	modePoints        = 0
+	modeLines         = 1
+	modeLineLoop      = 2
+	modeLineStrip     = 3
+	modeTriangles     = 4
+	modeTriangleStrip = 5
+	modeTriangleFan   = 6
+)
+
+type accessor struct {
+	BufferView    *int    `json:"bufferView"`
+	ByteOffset    int     `json:"byteOffset"`
+	ComponentType int     `json:"componentType"`
+	Normalized    bool    `json:"normalized"`
+	Count         int     `json:"count"`
+	Type          string  `json:"type"`
+	Sparse        *sparse `json:"sparse"`
+}
+
+type sparse struct {
+	Count   int `json:"count"`
+	Indices struct {
+		BufferView    int `json:"bufferView"`
+		ByteOffset    int `json:"byteOffset"`
+		ComponentType int `json:"componentType"`
+	} `json:"indices"`
+	Values struct {
+		BufferView int `json:"bufferView"`
+		ByteOffset int `json:"byteOffset"`
+	} `json:"values"`
+}
+
+type bufferView struct {
+	Buffer     int `json:"buffer"`
+	ByteOffset int `json:"byteOffset"`
+	ByteLength int `json:"byteLength"`
+	ByteStride int `json:"byteStride"`
+}
+
+type buffer struct {
+	URI        string `json:"uri"`
+	ByteLength int    `json:"byteLength"`
+
+	// data is the content of the buffer, set by resolve
+	data []byte
+}
+
+type material struct {
+	Name                 string `json:"name"`
+	PBRMetallicRoughness *struct {
+		BaseColorFactor          *[4]float32  `json:"baseColorFactor"`
+		BaseColorTexture         *textureInfo `json:"baseColorTexture"`
+		MetallicFactor           *float32     `json:"metallicFactor"`
+		RoughnessFactor          *float32     `json:"roughnessFactor"`
+		MetallicRoughnessTexture *textureInfo `json:"metallicRoughnessTexture"`
+	} `json:"pbrMetallicRoughness"`
+	NormalTexture    *textureInfo `json:"normalTexture"`
+	OcclusionTexture *textureInfo `json:"occlusionTexture"`
+	EmissiveTexture  *textureInfo `json:"emissiveTexture"`
+	EmissiveFactor   [3]float32   `json:"emissiveFactor"`
+	AlphaMode        string       `json:"alphaMode"`
+	AlphaCutoff      *float32     `json:"alphaCutoff"`
+	DoubleSided      bool         `json:"doubleSided"`
+}
+
+// textureInfo is a texture reference of a material. Scale is only used by normal
+// textures and Strength by occlusion ones.
+type textureInfo struct {
+	Index    int      `json:"index"`
+	TexCoord int      `json:"texCoord"`
+	Scale    *float32 `json:"scale"`
+	Strength *float32 `json:"strength"`
+}
+
+type texture struct {
+	Sampler *int `json:"sampler"`
+	Source  *int `json:"source"`
+}
+
+type image struct {
+	Name       string `json:"name"`
+	URI        string `json:"uri"`
+	MimeType   string `json:"mimeType"`
+	BufferView *int   `json:"bufferView"`
+
+	// data is the encoded image, set by resolve
+	data []byte
+}
+
+type sampler struct {
+	MagFilter int32  `json:"magFilter"`
+	MinFilter int32  `json:"minFilter"`
+	WrapS     *int32 `json:"wrapS"`
+	WrapT     *int32 `json:"wrapT"`
+}
+
+type skin struct {
+	Name                string `json:"name"`
+	InverseBindMatrices *int   `json:"inverseBindMatrices"`
+	Joints              []int  `json:"joints"`
+}
+
+type animation struct {
+	Name     string `json:"name"`
+	Channels []struct {
+		Sampler int `json:"sampler"`
+		Target  struct {
+			Node *int   `json:"node"`
+			Path string `json:"path"`
+		} `json:"target"`
+	} `json:"channels"`
+	Samplers []struct {
+		Input         int    `json:"input"`
+		Output        int    `json:"output"`
+		Interpolation string `json:"interpolation"`
+	} `json:"samplers"`
+}
+
+// The GLB container, a header followed by a JSON chunk and an optional binary one
+const (
+	glbMagic       = 0x46546C67 // "glTF"
+	glbVersion     = 2
+	glbHeaderSize  = 12
+	glbChunkHeader = 8
+	glbChunkJSON   = 0x4E4F534A // "JSON"
+	glbChunkBIN    = 0x004E4942 // "BIN\x00"
+)
+
+// isGLB reports whether data starts with the magic of the GLB container.
+func isGLB(data []byte) bool {
+	return len(data) >= 4 && binary.LittleEndian.Uint32(data) == glbMagic
+}
+
+// splitGLB returns the JSON and binary chunks of a GLB file. bin is nil when the file
+// has no binary chunk.
+func splitGLB(data []byte) (jsonChunk, bin []byte, err error) {
+	if len(data) < glbHeaderSize {
+		return nil, nil, fmt.Errorf("glb: %d bytes is too short for the header", len(data))
+	}
+
+	version := binary.LittleEndian.Uint32(data[4:])
+	if version != glbVersion {
+		return nil, nil, fmt.Errorf("glb: unsupported version %d", version)
+	}
+
+	length := int(binary.LittleEndian.Uint32(data[8:]))
+	if length > len(data) {
+		return nil, nil, fmt.Errorf("glb: header length %d is bigger than the %d bytes of the file", length, len(data))
+	}
+	data = data[glbHeaderSize:length]
+
+	for chunk := 0; len(data) > 0; chunk++ {
+		if len(data) < glbChunkHeader {
+			return nil, nil, fmt.Errorf("glb: chunk %d: truncated header", chunk)
+		}
+		chunkLength := int(binary.LittleEndian.Uint32(data))
+		chunkType := binary.LittleEndian.Uint32(data[4:])
+		data = data[glbChunkHeader:]
+
+		if chunkLength > len(data) {
+			return nil, nil, fmt.Errorf("glb: chunk %d: length %d is past the end of the file", chunk, chunkLength)
+		}
+		content := data[:chunkLength]
+		data = data[chunkLength:]
+
+		switch {
+		case chunk == 0 && chunkType != glbChunkJSON:
+			return nil, nil, fmt.Errorf("glb: the first chunk is not JSON")
+		case chunk == 0:
+			jsonChunk = content
+		case chunk == 1 && chunkType == glbChunkBIN:
+			bin = content
+		}
+		// Chunks of unknown types are skipped, as the specification asks
+	}
+
+	if jsonChunk == nil {
+		return nil, nil, fmt.Errorf("glb: no JSON chunk")
+	}
+
+	return jsonChunk, bin, nil
+}
+
+// parse decodes a glTF JSON file or a GLB one, returning the binary chunk of the latter.
+func parse(data []byte) (*document, []byte, error) {
+	var bin []byte
+	if isGLB(data) {
+		var err error
+		if data, bin, err = splitGLB(data); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	doc := &document{}
+	if err := json.Unmarshal(data, doc); err != nil {
+		return nil, nil, fmt.Errorf("gltf: %w", err)
+	}
+
+	return doc, bin, nil
+}
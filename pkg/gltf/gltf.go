@@ -0,0 +1,224 @@
+// Package gltf loads glTF 2.0 assets, JSON or binary GLB, in pure Go. Parsing,
+// validation and building the meshes do not need a GL context, the model package
+// uploads the result.
+package gltf
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// MaxTexCoords is the number of texture coordinate sets read, TEXCOORD_0 and TEXCOORD_1.
+const MaxTexCoords = 2
+
+type Vertex struct {
+	Position  mgl32.Vec3
+	Normal    mgl32.Vec3
+	TexCoords [MaxTexCoords]mgl32.Vec2
+	Tangent   mgl32.Vec3
+	Bitangent mgl32.Vec3
+	// Joints are the indices in Skin.Joints of the joints moving the vertex, by Weights.
+	Joints  [4]uint32
+	Weights [4]float32
+}
+
+// Mesh is a primitive of the asset, in the space of the scene: the transforms of the
+// nodes are applied to the vertices, except for skinned meshes which are moved by their
+// joints. A mesh used by several nodes is repeated.
+type Mesh struct {
+	// Name is the name of the node, or of its mesh when the node has none.
+	Name string
+	// Material is the index of the material in Model.Materials, -1 for the default one.
+	Material int
+	// Skin is the index of the skin in Model.Skins, -1 when the mesh is not skinned.
+	Skin     int
+	Vertices []Vertex
+	// Indices are the vertices of the triangles, three by three.
+	Indices []uint32
+}
+
+type Model struct {
+	Meshes    []Mesh
+	Materials []Material
+	// Images are the images of the asset, by index, referenced by the materials.
+	Images []Image
+	// Nodes are all the nodes of the asset, by index, the joints of the skins among them.
+	Nodes      []Node
+	Skins      []Skin
+	Animations []Animation
+}
+
+// Node is a node of the hierarchy of the asset.
+type Node struct {
+	Name string
+	// Parent is the index of the parent node, -1 for the roots.
+	Parent int
+	// Transform is the transform to the parent node.
+	Transform mgl32.Mat4
+}
+
+// Skin binds meshes to a skeleton.
+type Skin struct {
+	Name string
+	// Joints are the indices of the nodes moving the vertices.
+	Joints []int
+	// InverseBindMatrices transform from the space of the mesh to the one of each joint
+	// in the pose the mesh was modeled in.
+	InverseBindMatrices []mgl32.Mat4
+}
+
+// Animation is a keyframed animation of the nodes.
+type Animation struct {
+	Name     string
+	Channels []Channel
+}
+
+// Channel are the keyframes of a property of a node.
+type Channel struct {
+	Node int
+	// Path is the property animated: "translation", "rotation", "scale" or "weights".
+	Path string
+	// Interpolation is "LINEAR", "STEP" or "CUBICSPLINE".
+	Interpolation string
+	// Times of the keyframes, in seconds.
+	Times []float32
+	// Values are the components of the values of the keyframes one after the other, x y z
+	// w for rotations. CUBICSPLINE keyframes have an in-tangent, the value and an
+	// out-tangent each.
+	Values []float32
+}
+
+// Image is an encoded image, embedded in the asset or read from the file next to it.
+type Image struct {
+	Name string
+	// MimeType is "image/png" or "image/jpeg", or empty when the asset did not say.
+	MimeType string
+	Data     []byte
+}
+
+// Load reads the glTF or GLB file from fsys and the buffers and images it references,
+// relative to it.
+func Load(fsys fs.FS, file string) (*Model, error) {
+	data, err := fs.ReadFile(fsys, file)
+	if err != nil {
+		return nil, fmt.Errorf("gltf: %w", err)
+	}
+
+	model, err := Decode(data, fsys, path.Dir(file))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+
+	return model, nil
+}
+
+// Decode parses, validates and builds a glTF or GLB asset. The external buffers and
+// images are read from dir in fsys, which can be nil for assets with everything
+// embedded.
+//
+// The meshes are the ones of the default scene, or of all the root nodes when the
+// asset has no scenes. Faces without normals get flat normals and, without tangents,
+// tangents along the first texture coordinates. Points and lines are skipped. All the
+// nodes, skins and animations are kept for skeletal animation.
+func Decode(data []byte, fsys fs.FS, dir string) (*Model, error) {
+	doc, bin, err := parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := doc.resolve(fsys, dir, bin); err != nil {
+		return nil, err
+	}
+
+	if err := doc.validate(); err != nil {
+		return nil, err
+	}
+
+	return doc.build(), nil
+}
+
+// resolve reads the data of the buffers and images, from the binary chunk of a GLB,
+// data URIs or files.
+func (d *document) resolve(fsys fs.FS, dir string, bin []byte) error {
+	for i := range d.Buffers {
+		b := &d.Buffers[i]
+
+		if i == 0 && b.URI == "" && bin != nil {
+			b.data = bin
+			continue
+		}
+
+		data, _, err := readURI(fsys, dir, b.URI)
+		if err != nil {
+			return fmt.Errorf("gltf: buffers[%d]: %w", i, err)
+		}
+		b.data = data
+	}
+
+	for i := range d.Images {
+		img := &d.Images[i]
+		if img.BufferView != nil {
+			// Read once the buffer views are validated, see build
+			continue
+		}
+
+		data, mimeType, err := readURI(fsys, dir, img.URI)
+		if err != nil {
+			return fmt.Errorf("gltf: images[%d]: %w", i, err)
+		}
+		img.data = data
+		if img.MimeType == "" {
+			img.MimeType = mimeType
+		}
+	}
+
+	return nil
+}
+
+// readURI returns the content of a data URI, and its mime type, or of the file at the
+// relative URI in dir.
+func readURI(fsys fs.FS, dir, uri string) ([]byte, string, error) {
+	if uri == "" {
+		return nil, "", fmt.Errorf("no uri")
+	}
+
+	if rest, ok := strings.CutPrefix(uri, "data:"); ok {
+		header, encoded, ok := strings.Cut(rest, ",")
+		if !ok {
+			return nil, "", fmt.Errorf("data uri without a comma")
+		}
+		mimeType, isBase64 := strings.CutSuffix(header, ";base64")
+		if !isBase64 {
+			return nil, "", fmt.Errorf("data uri is not base64")
+		}
+
+		data, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, "", fmt.Errorf("data uri: %w", err)
+		}
+		return data, mimeType, nil
+	}
+
+	if fsys == nil {
+		return nil, "", fmt.Errorf("external uri %q without a file system", uri)
+	}
+
+	// URIs are percent encoded, e.g. spaces are %20
+	name, err := url.PathUnescape(uri)
+	if err != nil {
+		return nil, "", fmt.Errorf("uri %q: %w", uri, err)
+	}
+
+	data, err := fs.ReadFile(fsys, path.Join(dir, name))
+	if err != nil {
+		return nil, "", err
+	}
+
+	return data, "", nil
+}
@@ -0,0 +1,566 @@
+package gltf
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const epsilon = 1e-5
+
+// near reports whether a and b differ by less than epsilon, which, unlike the relative
+// comparisons of mgl32, holds for components that are close to 0.
+func near(a, b float32) bool {
+	return math.Abs(float64(a-b)) < epsilon
+}
+
+func ref[T any](v T) *T {
+	return &v
+}
+
+func floatBytes(values ...float32) []byte {
+	data := make([]byte, 0, len(values)*4)
+	for _, v := range values {
+		data = binary.LittleEndian.AppendUint32(data, math.Float32bits(v))
+	}
+	return data
+}
+
+func uint16Bytes(values ...uint16) []byte {
+	data := make([]byte, 0, len(values)*2)
+	for _, v := range values {
+		data = binary.LittleEndian.AppendUint16(data, v)
+	}
+	return data
+}
+
+func dataURI(data []byte) string {
+	return "data:application/octet-stream;base64," + base64.StdEncoding.EncodeToString(data)
+}
+
+// testAsset builds a glTF document whose buffer views are all in one buffer, embedded
+// as a data URI.
+type testAsset struct {
+	doc    document
+	buffer []byte
+}
+
+func newTestAsset() *testAsset {
+	a := &testAsset{}
+	a.doc.Asset.Version = "2.0"
+	return a
+}
+
+// newTriangle returns an asset with a node whose mesh is a triangle in the plane z = 0,
+// counter-clockwise seen from +Z. Its positions are accessors[0] in bufferViews[0] and
+// its indices accessors[1] in bufferViews[1].
+func newTriangle() *testAsset {
+	a := newTestAsset()
+	positions := a.floats("VEC3", 0, 0, 0, 1, 0, 0, 0, 1, 0)
+	indices := a.indices(0, 1, 2)
+	a.doc.Meshes = []mesh{{Primitives: []primitive{{
+		Attributes: map[string]int{"POSITION": positions},
+		Indices:    ref(indices),
+	}}}}
+	a.doc.Nodes = []node{{Mesh: ref(0)}}
+	return a
+}
+
+// view adds a buffer view of data and returns its index.
+func (a *testAsset) view(data []byte, stride int) int {
+	// Every view starts aligned to 4 bytes, as the components need
+	for len(a.buffer)%4 != 0 {
+		a.buffer = append(a.buffer, 0)
+	}
+	a.doc.BufferViews = append(a.doc.BufferViews, bufferView{ByteOffset: len(a.buffer), ByteLength: len(data), ByteStride: stride})
+	a.buffer = append(a.buffer, data...)
+	return len(a.doc.BufferViews) - 1
+}
+
+// accessor adds acc and returns its index.
+func (a *testAsset) accessor(acc accessor) int {
+	a.doc.Accessors = append(a.doc.Accessors, acc)
+	return len(a.doc.Accessors) - 1
+}
+
+// floats adds an accessor of float elements of xtype, in a buffer view of their own.
+func (a *testAsset) floats(xtype string, values ...float32) int {
+	return a.accessor(accessor{
+		BufferView:    ref(a.view(floatBytes(values...), 0)),
+		ComponentType: componentFloat,
+		Count:         len(values) / typeComponents[xtype],
+		Type:          xtype,
+	})
+}
+
+// indices adds an accessor of unsigned short SCALARs, in a buffer view of their own.
+func (a *testAsset) indices(values ...uint16) int {
+	return a.accessor(accessor{
+		BufferView:    ref(a.view(uint16Bytes(values...), 0)),
+		ComponentType: componentUnsignedShort,
+		Count:         len(values),
+		Type:          "SCALAR",
+	})
+}
+
+// encode returns the JSON of the asset. The buffer is added unless the document already
+// has one.
+func (a *testAsset) encode(t *testing.T) []byte {
+	t.Helper()
+
+	if a.doc.Buffers == nil {
+		a.doc.Buffers = []buffer{{URI: dataURI(a.buffer), ByteLength: len(a.buffer)}}
+	}
+
+	data, err := json.Marshal(a.doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func decode(t *testing.T, a *testAsset) *Model {
+	t.Helper()
+
+	model, err := Decode(a.encode(t), nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return model
+}
+
+// onlyMesh returns the single mesh of model.
+func onlyMesh(t *testing.T, model *Model) Mesh {
+	t.Helper()
+
+	if len(model.Meshes) != 1 {
+		t.Fatalf("got %d meshes, want 1", len(model.Meshes))
+	}
+	return model.Meshes[0]
+}
+
+// checkPositions fails unless the vertices of mesh are at want, in order.
+func checkPositions(t *testing.T, mesh Mesh, want []mgl32.Vec3) {
+	t.Helper()
+
+	if len(mesh.Vertices) != len(want) {
+		t.Fatalf("got %d vertices, want %d", len(mesh.Vertices), len(want))
+	}
+	for i, w := range want {
+		if got := mesh.Vertices[i].Position; !got.ApproxEqualThreshold(w, epsilon) {
+			t.Errorf("vertex %d position = %v, want %v", i, got, w)
+		}
+	}
+}
+
+func TestDecodeInterleaved(t *testing.T) {
+	positions := []mgl32.Vec3{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}}
+	normals := []mgl32.Vec3{{0, 0, 1}, {0, 1, 0}, {1, 0, 0}}
+
+	tests := []struct {
+		name   string
+		stride int
+	}{
+		{name: "packed", stride: 24},
+		{name: "padded", stride: 32},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The position and the normal of each vertex, one vertex after the other
+			data := make([]byte, tt.stride*len(positions))
+			for i := range positions {
+				copy(data[i*tt.stride:], floatBytes(positions[i][:]...))
+				copy(data[i*tt.stride+12:], floatBytes(normals[i][:]...))
+			}
+
+			a := newTestAsset()
+			view := a.view(data, tt.stride)
+			attributes := map[string]int{
+				"POSITION": a.accessor(accessor{BufferView: ref(view), ComponentType: componentFloat, Count: 3, Type: "VEC3"}),
+				"NORMAL":   a.accessor(accessor{BufferView: ref(view), ByteOffset: 12, ComponentType: componentFloat, Count: 3, Type: "VEC3"}),
+			}
+			a.doc.Meshes = []mesh{{Primitives: []primitive{{Attributes: attributes}}}}
+			a.doc.Nodes = []node{{Mesh: ref(0)}}
+
+			mesh := onlyMesh(t, decode(t, a))
+			checkPositions(t, mesh, positions)
+			for i, want := range normals {
+				if got := mesh.Vertices[i].Normal; !got.ApproxEqualThreshold(want, epsilon) {
+					t.Errorf("vertex %d normal = %v, want %v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeSparse(t *testing.T) {
+	tests := []struct {
+		name string
+		// base are the positions replaced by the sparse elements, zeros when nil
+		base []float32
+	}{
+		{name: "over a buffer view", base: []float32{0, 0, 0, 5, 5, 5, 0, 1, 0}},
+		{name: "over zeros"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newTestAsset()
+
+			s := &sparse{Count: 2}
+			s.Indices.BufferView = a.view(uint16Bytes(1, 2), 0)
+			s.Indices.ComponentType = componentUnsignedShort
+			s.Values.BufferView = a.view(floatBytes(1, 0, 0, 1, 1, 0), 0)
+
+			positions := accessor{ComponentType: componentFloat, Count: 3, Type: "VEC3", Sparse: s}
+			if tt.base != nil {
+				positions.BufferView = ref(a.view(floatBytes(tt.base...), 0))
+			}
+
+			a.doc.Meshes = []mesh{{Primitives: []primitive{{
+				Attributes: map[string]int{"POSITION": a.accessor(positions)},
+			}}}}
+			a.doc.Nodes = []node{{Mesh: ref(0)}}
+
+			checkPositions(t, onlyMesh(t, decode(t, a)), []mgl32.Vec3{{0, 0, 0}, {1, 0, 0}, {1, 1, 0}})
+		})
+	}
+}
+
+func TestDecodeHierarchy(t *testing.T) {
+	// The child is moved up and turned a quarter counter-clockwise around Z
+	local := mgl32.Translate3D(0, 1, 0).Mul4(mgl32.HomogRotate3DZ(math.Pi / 2))
+	q := mgl32.QuatRotate(math.Pi/2, mgl32.Vec3{0, 0, 1})
+
+	tests := []struct {
+		name  string
+		child node
+	}{
+		{
+			name:  "trs",
+			child: node{Mesh: ref(0), Translation: &[3]float32{0, 1, 0}, Rotation: &[4]float32{q.V[0], q.V[1], q.V[2], q.W}},
+		},
+		{
+			name:  "matrix",
+			child: node{Mesh: ref(0), Matrix: ref([16]float32(local))},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newTriangle()
+			a.doc.Nodes = []node{
+				{Name: "root", Children: []int{1}, Translation: &[3]float32{1, 0, 0}, Scale: &[3]float32{2, 2, 2}},
+				tt.child,
+			}
+
+			model := decode(t, a)
+
+			// Turned, moved by the child, then scaled and moved by the root
+			checkPositions(t, onlyMesh(t, model), []mgl32.Vec3{{1, 2, 0}, {1, 4, 0}, {-1, 2, 0}})
+
+			if len(model.Nodes) != 2 {
+				t.Fatalf("got %d nodes, want 2", len(model.Nodes))
+			}
+			if p := model.Nodes[0].Parent; p != -1 {
+				t.Errorf("parent of the root = %d, want -1", p)
+			}
+			if p := model.Nodes[1].Parent; p != 0 {
+				t.Errorf("parent of the child = %d, want 0", p)
+			}
+			if got := model.Nodes[1].Transform; !got.ApproxFuncEqual(local, near) {
+				t.Errorf("transform of the child = %v, want %v", got, local)
+			}
+		})
+	}
+}
+
+func TestDecodeMirrored(t *testing.T) {
+	tests := []struct {
+		name string
+		node node
+		// normal is the one of the triangle in the scene
+		normal mgl32.Vec3
+	}{
+		{name: "identity", normal: mgl32.Vec3{0, 0, 1}},
+		{name: "mirrored in x", node: node{Scale: &[3]float32{-1, 1, 1}}, normal: mgl32.Vec3{0, 0, 1}},
+		{name: "mirrored in all axes", node: node{Scale: &[3]float32{-1, -1, -1}}, normal: mgl32.Vec3{0, 0, -1}},
+		{name: "turned around y", node: node{Rotation: &[4]float32{0, 1, 0, 0}}, normal: mgl32.Vec3{0, 0, -1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newTriangle()
+			a.doc.Nodes[0].Scale = tt.node.Scale
+			a.doc.Nodes[0].Rotation = tt.node.Rotation
+
+			mesh := onlyMesh(t, decode(t, a))
+			if len(mesh.Indices) != 3 {
+				t.Fatalf("got %d indices, want 3", len(mesh.Indices))
+			}
+
+			// Counter-clockwise triangles face their normals
+			v0, v1, v2 := mesh.Vertices[mesh.Indices[0]], mesh.Vertices[mesh.Indices[1]], mesh.Vertices[mesh.Indices[2]]
+			winding := v1.Position.Sub(v0.Position).Cross(v2.Position.Sub(v0.Position)).Normalize()
+			if !winding.ApproxEqualThreshold(tt.normal, epsilon) {
+				t.Errorf("winding faces %v, want %v", winding, tt.normal)
+			}
+			for i, v := range []Vertex{v0, v1, v2} {
+				if !v.Normal.ApproxEqualThreshold(tt.normal, epsilon) {
+					t.Errorf("corner %d normal = %v, want %v", i, v.Normal, tt.normal)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeIndices(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    *int
+		indices []uint16
+		// want are the indices of the mesh, none when it is skipped
+		want []uint32
+	}{
+		{name: "triangles", indices: []uint16{0, 1, 2, 0, 2, 3}, want: []uint32{0, 1, 2, 0, 2, 3}},
+		{name: "out of range", indices: []uint16{0, 1, 2, 0, 2, 7, 0, 2, 3}, want: []uint32{0, 1, 2, 0, 2, 3}},
+		{name: "incomplete triangle", indices: []uint16{0, 1, 2, 0}, want: []uint32{0, 1, 2}},
+		{name: "all out of range", indices: []uint16{4, 5, 6}},
+		{name: "strip", mode: ref(modeTriangleStrip), indices: []uint16{0, 1, 3, 2}, want: []uint32{0, 1, 3, 3, 1, 2}},
+		{name: "fan", mode: ref(modeTriangleFan), indices: []uint16{0, 1, 2, 3}, want: []uint32{0, 1, 2, 0, 2, 3}},
+		{name: "points", mode: ref(modePoints), indices: []uint16{0, 1, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A quad, with normals so its vertices are kept as they are
+			a := newTestAsset()
+			attributes := map[string]int{
+				"POSITION": a.floats("VEC3", 0, 0, 0, 1, 0, 0, 1, 1, 0, 0, 1, 0),
+				"NORMAL":   a.floats("VEC3", 0, 0, 1, 0, 0, 1, 0, 0, 1, 0, 0, 1),
+			}
+			a.doc.Meshes = []mesh{{Primitives: []primitive{{
+				Attributes: attributes,
+				Indices:    ref(a.indices(tt.indices...)),
+				Mode:       tt.mode,
+			}}}}
+			a.doc.Nodes = []node{{Mesh: ref(0)}}
+
+			model := decode(t, a)
+			if tt.want == nil {
+				if len(model.Meshes) != 0 {
+					t.Fatalf("got %d meshes, want the primitive skipped", len(model.Meshes))
+				}
+				return
+			}
+
+			mesh := onlyMesh(t, model)
+			if len(mesh.Vertices) != 4 {
+				t.Errorf("got %d vertices, want 4", len(mesh.Vertices))
+			}
+			if !slices.Equal(mesh.Indices, tt.want) {
+				t.Errorf("indices = %v, want %v", mesh.Indices, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(a *testAsset)
+		// want are parts of the error, which is nil when there are none
+		want []string
+	}{
+		{
+			name:   "valid",
+			mutate: func(a *testAsset) {},
+		},
+		{
+			name:   "unsupported version",
+			mutate: func(a *testAsset) { a.doc.Asset.Version = "1.0" },
+			want:   []string{`asset: unsupported version "1.0"`},
+		},
+		{
+			name:   "required extension",
+			mutate: func(a *testAsset) { a.doc.ExtensionsRequired = []string{"KHR_draco_mesh_compression"} },
+			want:   []string{"extension KHR_draco_mesh_compression is required but not supported"},
+		},
+		{
+			name:   "scene out of range",
+			mutate: func(a *testAsset) { a.doc.Scene = ref(0) },
+			want:   []string{"scene: index 0 out of range, 0 are defined"},
+		},
+		{
+			name:   "mesh out of range",
+			mutate: func(a *testAsset) { a.doc.Nodes[0].Mesh = ref(1) },
+			want:   []string{"nodes[0].mesh: index 1 out of range, 1 are defined"},
+		},
+		{
+			name:   "child out of range",
+			mutate: func(a *testAsset) { a.doc.Nodes[0].Children = []int{4} },
+			want:   []string{"nodes[0].children: index 4 out of range, 1 are defined"},
+		},
+		{
+			name: "matrix and trs",
+			mutate: func(a *testAsset) {
+				a.doc.Nodes[0].Matrix = ref([16]float32(mgl32.Ident4()))
+				a.doc.Nodes[0].Scale = &[3]float32{1, 1, 1}
+			},
+			want: []string{"nodes[0]: has both a matrix and TRS properties"},
+		},
+		{
+			name: "two parents",
+			mutate: func(a *testAsset) {
+				a.doc.Nodes = append(a.doc.Nodes, node{Children: []int{0}}, node{Children: []int{0}})
+			},
+			want: []string{"nodes[0]: is a child of nodes[1] and nodes[2]"},
+		},
+		{
+			name: "cycle",
+			mutate: func(a *testAsset) {
+				a.doc.Nodes = append(a.doc.Nodes, node{Children: []int{2}}, node{Children: []int{1}})
+			},
+			want: []string{"nodes[1]: is part of a cycle", "nodes[2]: is part of a cycle"},
+		},
+		{
+			name:   "no primitives",
+			mutate: func(a *testAsset) { a.doc.Meshes = append(a.doc.Meshes, mesh{}) },
+			want:   []string{"meshes[1]: no primitives"},
+		},
+		{
+			name:   "no position",
+			mutate: func(a *testAsset) { delete(a.doc.Meshes[0].Primitives[0].Attributes, "POSITION") },
+			want:   []string{"meshes[0].primitives[0]: no POSITION attribute"},
+		},
+		{
+			name:   "attribute type",
+			mutate: func(a *testAsset) { a.doc.Meshes[0].Primitives[0].Attributes["TEXCOORD_0"] = 0 },
+			want:   []string{"meshes[0].primitives[0]: TEXCOORD_0 must be a VEC2"},
+		},
+		{
+			name: "attribute count",
+			mutate: func(a *testAsset) {
+				a.doc.Meshes[0].Primitives[0].Attributes["NORMAL"] = a.floats("VEC3", 0, 0, 1, 0, 0, 1)
+			},
+			want: []string{"meshes[0].primitives[0]: NORMAL has 2 elements but POSITION 3"},
+		},
+		{
+			name:   "float indices",
+			mutate: func(a *testAsset) { a.doc.Meshes[0].Primitives[0].Indices = ref(0) },
+			want:   []string{"meshes[0].primitives[0]: indices must be unsigned integer SCALARs"},
+		},
+		{
+			name:   "material out of range",
+			mutate: func(a *testAsset) { a.doc.Meshes[0].Primitives[0].Material = ref(0) },
+			want:   []string{"meshes[0].primitives[0].material: index 0 out of range, 0 are defined"},
+		},
+		{
+			name:   "unknown mode",
+			mutate: func(a *testAsset) { a.doc.Meshes[0].Primitives[0].Mode = ref(7) },
+			want:   []string{"meshes[0].primitives[0]: unknown mode 7"},
+		},
+		{
+			name:   "byte stride",
+			mutate: func(a *testAsset) { a.doc.BufferViews[0].ByteStride = 6 },
+			want:   []string{"bufferViews[0]: byte stride 6 must be a multiple of 4 in [4, 252]"},
+		},
+		{
+			name:   "view out of the buffer",
+			mutate: func(a *testAsset) { a.doc.BufferViews[1].ByteLength = 64 },
+			want:   []string{"bufferViews[1]: bytes [36, 100) out of the buffer"},
+		},
+		{
+			name:   "accessor out of the view",
+			mutate: func(a *testAsset) { a.doc.Accessors[0].ByteOffset = 4 },
+			want:   []string{"accessors[0]: bytes [4, 40) out of the 36 of buffer view 0"},
+		},
+		{
+			name:   "unknown component type",
+			mutate: func(a *testAsset) { a.doc.Accessors[1].ComponentType = 5124 },
+			want:   []string{"accessors[1]: unknown component type 5124"},
+		},
+		{
+			name:   "sparse count",
+			mutate: func(a *testAsset) { a.doc.Accessors[0].Sparse = &sparse{Count: 4} },
+			want:   []string{"accessors[0]: sparse: count 4 out of [1, 3]"},
+		},
+		{
+			name: "buffer length",
+			mutate: func(a *testAsset) {
+				a.doc.Buffers = []buffer{{URI: dataURI(a.buffer), ByteLength: len(a.buffer) + 4}}
+			},
+			want: []string{"buffers[0]: byte length 46, but the data has 42"},
+		},
+		{
+			name: "data uri without base64",
+			mutate: func(a *testAsset) {
+				a.doc.Buffers = []buffer{{URI: "data:application/octet-stream,AAAA", ByteLength: 3}}
+			},
+			want: []string{"buffers[0]: data uri is not base64"},
+		},
+		{
+			name: "external uri",
+			mutate: func(a *testAsset) {
+				a.doc.Buffers = []buffer{{URI: "triangle.bin", ByteLength: len(a.buffer)}}
+			},
+			want: []string{`buffers[0]: external uri "triangle.bin" without a file system`},
+		},
+		{
+			name:   "texture source out of range",
+			mutate: func(a *testAsset) { a.doc.Textures = []texture{{Source: ref(0)}} },
+			want:   []string{"textures[0].source: index 0 out of range, 0 are defined"},
+		},
+		{
+			name:   "image with a uri and a buffer view",
+			mutate: func(a *testAsset) { a.doc.Images = []image{{URI: "wood.png", BufferView: ref(0)}} },
+			want:   []string{"images[0]: has both a uri and a buffer view"},
+		},
+		{
+			name: "skin without mesh",
+			mutate: func(a *testAsset) {
+				a.doc.Nodes = append(a.doc.Nodes, node{Skin: ref(0)})
+				a.doc.Skins = []skin{{Joints: []int{0}}}
+			},
+			want: []string{"nodes[1]: has a skin but no mesh"},
+		},
+		{
+			name: "every error",
+			mutate: func(a *testAsset) {
+				a.doc.Asset.Version = "1.0"
+				a.doc.Nodes[0].Mesh = ref(1)
+			},
+			want: []string{"unsupported version", "nodes[0].mesh: index 1 out of range"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newTriangle()
+			tt.mutate(a)
+
+			_, err := Decode(a.encode(t), nil, "")
+			if tt.want == nil {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("Decode succeeded")
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(err.Error(), w) {
+					t.Errorf("error %q does not contain %q", err, w)
+				}
+			}
+		})
+	}
+}
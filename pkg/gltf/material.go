@@ -0,0 +1,160 @@
+package gltf
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// AlphaMode is how the alpha of the base color is used.
+type AlphaMode string
+
+const (
+	// Opaque ignores the alpha.
+	Opaque AlphaMode = "OPAQUE"
+	// Mask discards the fragments with an alpha below Material.AlphaCutoff.
+	Mask AlphaMode = "MASK"
+	// Blend blends the fragments with the ones behind them.
+	Blend AlphaMode = "BLEND"
+)
+
+// Material is a PBR metallic-roughness material. The factors multiply the values of the
+// textures, when they have one.
+type Material struct {
+	Name string
+
+	BaseColor mgl32.Vec4
+	// BaseColorTexture is in sRGB, like EmissiveTexture.
+	BaseColorTexture *TextureRef
+
+	Metallic  float32
+	Roughness float32
+	// MetallicRoughnessTexture holds the roughness in the green channel and the metalness
+	// in the blue one.
+	MetallicRoughnessTexture *TextureRef
+
+	NormalTexture *TextureRef
+	// NormalScale scales the X and Y of the normals of NormalTexture.
+	NormalScale float32
+
+	// OcclusionTexture holds the ambient occlusion in the red channel.
+	OcclusionTexture *TextureRef
+	// OcclusionStrength goes from 0 (no occlusion) to 1 (full occlusion).
+	OcclusionStrength float32
+
+	Emissive        mgl32.Vec3
+	EmissiveTexture *TextureRef
+
+	AlphaMode   AlphaMode
+	AlphaCutoff float32
+	// DoubleSided materials are lit on their back faces too, so they should not be culled.
+	DoubleSided bool
+}
+
+// TextureRef is a texture used by a material.
+type TextureRef struct {
+	// Image is the index of the image in Model.Images.
+	Image int
+	// TexCoord is the set of texture coordinates of the vertices it is sampled with.
+	TexCoord int
+	Sampler  Sampler
+}
+
+// Sampler are the filters and wrap modes of a texture, as GL enums, e.g. gl.LINEAR and
+// gl.REPEAT. The filters are 0 when the asset leaves them to the renderer.
+type Sampler struct {
+	MagFilter int32
+	MinFilter int32
+	WrapS     int32
+	WrapT     int32
+}
+
+// glRepeat is gl.REPEAT, the default wrap mode.
+const glRepeat = 10497
+
+// defaultMaterial returns the material of the primitives without one.
+func defaultMaterial() Material {
+	return Material{
+		BaseColor:         mgl32.Vec4{1.0, 1.0, 1.0, 1.0},
+		Metallic:          1.0,
+		Roughness:         1.0,
+		NormalScale:       1.0,
+		OcclusionStrength: 1.0,
+		AlphaMode:         Opaque,
+		AlphaCutoff:       0.5,
+	}
+}
+
+// convertMaterial applies the properties the material has over the defaults.
+func (d *document) convertMaterial(m material) Material {
+	mat := defaultMaterial()
+	mat.Name = m.Name
+
+	if pbr := m.PBRMetallicRoughness; pbr != nil {
+		if pbr.BaseColorFactor != nil {
+			mat.BaseColor = *pbr.BaseColorFactor
+		}
+		if pbr.MetallicFactor != nil {
+			mat.Metallic = *pbr.MetallicFactor
+		}
+		if pbr.RoughnessFactor != nil {
+			mat.Roughness = *pbr.RoughnessFactor
+		}
+		mat.BaseColorTexture = d.textureRef(pbr.BaseColorTexture)
+		mat.MetallicRoughnessTexture = d.textureRef(pbr.MetallicRoughnessTexture)
+	}
+
+	mat.NormalTexture = d.textureRef(m.NormalTexture)
+	if m.NormalTexture != nil && m.NormalTexture.Scale != nil {
+		mat.NormalScale = *m.NormalTexture.Scale
+	}
+
+	mat.OcclusionTexture = d.textureRef(m.OcclusionTexture)
+	if m.OcclusionTexture != nil && m.OcclusionTexture.Strength != nil {
+		mat.OcclusionStrength = *m.OcclusionTexture.Strength
+	}
+
+	mat.Emissive = m.EmissiveFactor
+	mat.EmissiveTexture = d.textureRef(m.EmissiveTexture)
+
+	if m.AlphaMode != "" {
+		mat.AlphaMode = AlphaMode(m.AlphaMode)
+	}
+	if m.AlphaCutoff != nil {
+		mat.AlphaCutoff = *m.AlphaCutoff
+	}
+	mat.DoubleSided = m.DoubleSided
+
+	return mat
+}
+
+// textureRef resolves the texture of info, nil when there is none or the texture has
+// no image, e.g. because it only has one in an unsupported extension.
+func (d *document) textureRef(info *textureInfo) *TextureRef {
+	if info == nil {
+		return nil
+	}
+
+	t := d.Textures[info.Index]
+	if t.Source == nil {
+		return nil
+	}
+
+	ref := &TextureRef{
+		Image:    *t.Source,
+		TexCoord: info.TexCoord,
+		Sampler:  Sampler{WrapS: glRepeat, WrapT: glRepeat},
+	}
+
+	if t.Sampler != nil {
+		s := d.Samplers[*t.Sampler]
+		ref.Sampler.MagFilter = s.MagFilter
+		ref.Sampler.MinFilter = s.MinFilter
+		if s.WrapS != nil {
+			ref.Sampler.WrapS = *s.WrapS
+		}
+		if s.WrapT != nil {
+			ref.Sampler.WrapT = *s.WrapT
+		}
+	}
+
+	return ref
+}
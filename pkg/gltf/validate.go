@@ -0,0 +1,368 @@
+package gltf
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// validate checks the references between the objects of the document and that the
+// accessors fit in their buffer views, so building the meshes can index them freely.
+// The buffers must be resolved, see resolve.
+func (d *document) validate() error {
+	var errs []error
+	fail := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if !strings.HasPrefix(d.Asset.Version, "2.") {
+		fail("asset: unsupported version %q", d.Asset.Version)
+	}
+	if d.Asset.MinVersion != "" && d.Asset.MinVersion != "2.0" {
+		fail("asset: unsupported min version %q", d.Asset.MinVersion)
+	}
+	for _, ext := range d.ExtensionsRequired {
+		fail("extension %s is required but not supported", ext)
+	}
+
+	inRange := func(what string, index, count int) bool {
+		if index < 0 || index >= count {
+			fail("%s: index %d out of range, %d are defined", what, index, count)
+			return false
+		}
+		return true
+	}
+
+	if d.Scene != nil {
+		inRange("scene", *d.Scene, len(d.Scenes))
+	}
+	for i, s := range d.Scenes {
+		for _, n := range s.Nodes {
+			inRange(fmt.Sprintf("scenes[%d].nodes", i), n, len(d.Nodes))
+		}
+	}
+
+	parents := make([]int, len(d.Nodes))
+	for i := range parents {
+		parents[i] = -1
+	}
+	for i, n := range d.Nodes {
+		if n.Mesh != nil {
+			inRange(fmt.Sprintf("nodes[%d].mesh", i), *n.Mesh, len(d.Meshes))
+		}
+		if n.Skin != nil {
+			inRange(fmt.Sprintf("nodes[%d].skin", i), *n.Skin, len(d.Skins))
+			if n.Mesh == nil {
+				fail("nodes[%d]: has a skin but no mesh", i)
+			}
+		}
+		if n.Matrix != nil && (n.Translation != nil || n.Rotation != nil || n.Scale != nil) {
+			fail("nodes[%d]: has both a matrix and TRS properties", i)
+		}
+		for _, c := range n.Children {
+			if !inRange(fmt.Sprintf("nodes[%d].children", i), c, len(d.Nodes)) {
+				continue
+			}
+			if parents[c] >= 0 {
+				fail("nodes[%d]: is a child of nodes[%d] and nodes[%d]", c, parents[c], i)
+				continue
+			}
+			parents[c] = i
+		}
+	}
+	// A node that is its own ancestor never reaches a root
+	for i := range d.Nodes {
+		steps, p := 0, parents[i]
+		for p >= 0 && steps <= len(d.Nodes) {
+			p = parents[p]
+			steps++
+		}
+		if p >= 0 {
+			fail("nodes[%d]: is part of a cycle", i)
+		}
+	}
+
+	for i, m := range d.Meshes {
+		if len(m.Primitives) == 0 {
+			fail("meshes[%d]: no primitives", i)
+		}
+		for j, p := range m.Primitives {
+			what := fmt.Sprintf("meshes[%d].primitives[%d]", i, j)
+
+			position, ok := p.Attributes["POSITION"]
+			if !ok {
+				fail("%s: no POSITION attribute", what)
+			}
+			for name, a := range p.Attributes {
+				if !inRange(what+"."+name, a, len(d.Accessors)) {
+					continue
+				}
+				xtype, known := attributeTypes[name]
+				switch {
+				case !known:
+					// Other attributes, like colors, are not read
+				case d.Accessors[a].Type != xtype:
+					fail("%s: %s must be a %s", what, name, xtype)
+				case name == "POSITION" && d.Accessors[a].ComponentType != componentFloat:
+					fail("%s: POSITION must be floats", what)
+				case ok && position >= 0 && position < len(d.Accessors) && d.Accessors[a].Count != d.Accessors[position].Count:
+					fail("%s: %s has %d elements but POSITION %d", what, name, d.Accessors[a].Count, d.Accessors[position].Count)
+				}
+			}
+
+			if p.Indices != nil && inRange(what+".indices", *p.Indices, len(d.Accessors)) {
+				a := d.Accessors[*p.Indices]
+				if a.Type != "SCALAR" || !isIndexComponent(a.ComponentType) {
+					fail("%s: indices must be unsigned integer SCALARs", what)
+				}
+			}
+			if p.Material != nil {
+				inRange(what+".material", *p.Material, len(d.Materials))
+			}
+			if p.Mode != nil && (*p.Mode < modePoints || *p.Mode > modeTriangleFan) {
+				fail("%s: unknown mode %d", what, *p.Mode)
+			}
+		}
+	}
+
+	for i, b := range d.Buffers {
+		if b.data == nil {
+			fail("buffers[%d]: not resolved", i)
+		} else if len(b.data) < b.ByteLength {
+			fail("buffers[%d]: byte length %d, but the data has %d", i, b.ByteLength, len(b.data))
+		}
+	}
+
+	for i, v := range d.BufferViews {
+		what := fmt.Sprintf("bufferViews[%d]", i)
+		if !inRange(what+".buffer", v.Buffer, len(d.Buffers)) {
+			continue
+		}
+		if v.ByteOffset < 0 || v.ByteLength < 1 || v.ByteOffset+v.ByteLength > d.Buffers[v.Buffer].ByteLength {
+			fail("%s: bytes [%d, %d) out of the buffer", what, v.ByteOffset, v.ByteOffset+v.ByteLength)
+		}
+		if v.ByteStride != 0 && (v.ByteStride < 4 || v.ByteStride > 252 || v.ByteStride%4 != 0) {
+			fail("%s: byte stride %d must be a multiple of 4 in [4, 252]", what, v.ByteStride)
+		}
+	}
+
+	for i, a := range d.Accessors {
+		if err := d.validateAccessor(a); err != nil {
+			fail("accessors[%d]: %w", i, err)
+		}
+	}
+
+	for i, s := range d.Skins {
+		if len(s.Joints) == 0 {
+			fail("skins[%d]: no joints", i)
+		}
+		for _, j := range s.Joints {
+			inRange(fmt.Sprintf("skins[%d].joints", i), j, len(d.Nodes))
+		}
+		if s.InverseBindMatrices != nil && inRange(fmt.Sprintf("skins[%d].inverseBindMatrices", i), *s.InverseBindMatrices, len(d.Accessors)) {
+			a := d.Accessors[*s.InverseBindMatrices]
+			if a.Type != "MAT4" || a.ComponentType != componentFloat || a.Count < len(s.Joints) {
+				fail("skins[%d]: inverseBindMatrices must be a float MAT4 per joint", i)
+			}
+		}
+	}
+
+	for i, anim := range d.Animations {
+		for j, sampler := range anim.Samplers {
+			what := fmt.Sprintf("animations[%d].samplers[%d]", i, j)
+			if !inRange(what+".input", sampler.Input, len(d.Accessors)) || !inRange(what+".output", sampler.Output, len(d.Accessors)) {
+				continue
+			}
+			input, output := d.Accessors[sampler.Input], d.Accessors[sampler.Output]
+			if input.Type != "SCALAR" || input.ComponentType != componentFloat {
+				fail("%s: input must be float SCALARs", what)
+			}
+
+			keys := input.Count
+			switch sampler.Interpolation {
+			case "", "LINEAR", "STEP":
+			case "CUBICSPLINE":
+				keys *= 3
+			default:
+				fail("%s: unknown interpolation %q", what, sampler.Interpolation)
+			}
+			if output.Count != keys {
+				fail("%s: output has %d elements for %d keyframes", what, output.Count, input.Count)
+			}
+		}
+
+		for j, ch := range anim.Channels {
+			what := fmt.Sprintf("animations[%d].channels[%d]", i, j)
+			if !inRange(what+".sampler", ch.Sampler, len(anim.Samplers)) {
+				continue
+			}
+			if ch.Target.Node != nil {
+				inRange(what+".target.node", *ch.Target.Node, len(d.Nodes))
+			}
+
+			output := anim.Samplers[ch.Sampler].Output
+			if output < 0 || output >= len(d.Accessors) {
+				continue
+			}
+			xtype := d.Accessors[output].Type
+			switch ch.Target.Path {
+			case "translation", "scale":
+				if xtype != "VEC3" {
+					fail("%s: %s must be VEC3s", what, ch.Target.Path)
+				}
+			case "rotation":
+				if xtype != "VEC4" {
+					fail("%s: rotation must be VEC4s", what)
+				}
+			case "weights":
+			default:
+				fail("%s: unknown path %q", what, ch.Target.Path)
+			}
+		}
+	}
+
+	for i, t := range d.Textures {
+		if t.Sampler != nil {
+			inRange(fmt.Sprintf("textures[%d].sampler", i), *t.Sampler, len(d.Samplers))
+		}
+		if t.Source != nil {
+			inRange(fmt.Sprintf("textures[%d].source", i), *t.Source, len(d.Images))
+		}
+	}
+
+	for i, img := range d.Images {
+		what := fmt.Sprintf("images[%d]", i)
+		switch {
+		case img.BufferView != nil && img.URI != "":
+			fail("%s: has both a uri and a buffer view", what)
+		case img.BufferView != nil:
+			inRange(what+".bufferView", *img.BufferView, len(d.BufferViews))
+			if img.MimeType == "" {
+				fail("%s: a mime type is required with a buffer view", what)
+			}
+		case img.URI == "":
+			fail("%s: has no uri nor buffer view", what)
+		}
+	}
+
+	for i, m := range d.Materials {
+		for _, t := range m.textureInfos() {
+			inRange(fmt.Sprintf("materials[%d].%s", i, t.name), t.info.Index, len(d.Textures))
+			if t.info.TexCoord < 0 || t.info.TexCoord >= MaxTexCoords {
+				fail("materials[%d].%s: texture coordinates %d are not supported, up to %d", i, t.name, t.info.TexCoord, MaxTexCoords-1)
+			}
+		}
+		switch m.AlphaMode {
+		case "", "OPAQUE", "MASK", "BLEND":
+		default:
+			fail("materials[%d]: unknown alpha mode %q", i, m.AlphaMode)
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("gltf: %w", errors.Join(errs...))
+	}
+
+	return nil
+}
+
+func (d *document) validateAccessor(a accessor) error {
+	components, ok := typeComponents[a.Type]
+	if !ok {
+		return fmt.Errorf("unknown type %q", a.Type)
+	}
+	size, ok := componentSizes[a.ComponentType]
+	if !ok {
+		return fmt.Errorf("unknown component type %d", a.ComponentType)
+	}
+	if a.Count < 1 {
+		return fmt.Errorf("count %d must be 1 at least", a.Count)
+	}
+
+	if a.BufferView != nil {
+		if err := d.validateView(*a.BufferView, a.ByteOffset, a.Count, components*size); err != nil {
+			return err
+		}
+	}
+
+	if a.Sparse == nil {
+		return nil
+	}
+
+	s := a.Sparse
+	if s.Count < 1 || s.Count > a.Count {
+		return fmt.Errorf("sparse: count %d out of [1, %d]", s.Count, a.Count)
+	}
+	if !isIndexComponent(s.Indices.ComponentType) {
+		return fmt.Errorf("sparse: indices must be unsigned integers")
+	}
+	if err := d.validateView(s.Indices.BufferView, s.Indices.ByteOffset, s.Count, componentSizes[s.Indices.ComponentType]); err != nil {
+		return fmt.Errorf("sparse: indices: %w", err)
+	}
+	if err := d.validateView(s.Values.BufferView, s.Values.ByteOffset, s.Count, components*size); err != nil {
+		return fmt.Errorf("sparse: values: %w", err)
+	}
+
+	return nil
+}
+
+// validateView checks that count elements of elemSize bytes, from offset in the buffer
+// view, fit in it.
+func (d *document) validateView(view, offset, count, elemSize int) error {
+	if view < 0 || view >= len(d.BufferViews) {
+		return fmt.Errorf("buffer view %d out of range, %d are defined", view, len(d.BufferViews))
+	}
+	v := d.BufferViews[view]
+
+	stride := v.ByteStride
+	if stride == 0 {
+		stride = elemSize
+	}
+	if stride < elemSize {
+		return fmt.Errorf("byte stride %d is smaller than the %d bytes of the elements", stride, elemSize)
+	}
+
+	end := offset + stride*(count-1) + elemSize
+	if offset < 0 || end > v.ByteLength {
+		return fmt.Errorf("bytes [%d, %d) out of the %d of buffer view %d", offset, end, v.ByteLength, view)
+	}
+
+	return nil
+}
+
+// attributeTypes are the types of the attributes read by build.
+var attributeTypes = map[string]string{
+	"POSITION":   "VEC3",
+	"NORMAL":     "VEC3",
+	"TANGENT":    "VEC4",
+	"TEXCOORD_0": "VEC2",
+	"TEXCOORD_1": "VEC2",
+	"JOINTS_0":   "VEC4",
+	"WEIGHTS_0":  "VEC4",
+}
+
+// namedTextureInfo is a texture reference of a material and the property holding it.
+type namedTextureInfo struct {
+	name string
+	info *textureInfo
+}
+
+// textureInfos returns the texture references the material has.
+func (m *material) textureInfos() []namedTextureInfo {
+	var infos []namedTextureInfo
+	add := func(name string, info *textureInfo) {
+		if info != nil {
+			infos = append(infos, namedTextureInfo{name, info})
+		}
+	}
+
+	if pbr := m.PBRMetallicRoughness; pbr != nil {
+		add("baseColorTexture", pbr.BaseColorTexture)
+		add("metallicRoughnessTexture", pbr.MetallicRoughnessTexture)
+	}
+	add("normalTexture", m.NormalTexture)
+	add("occlusionTexture", m.OcclusionTexture)
+	add("emissiveTexture", m.EmissiveTexture)
+
+	return infos
+}
@@ -0,0 +1,86 @@
+// Package imageutil converts images between the top-down row order of image.Image
+// and the bottom-up row order OpenGL uses for textures and framebuffers.
+package imageutil
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/jpeg"
+	_ "image/png"
+	"io/fs"
+)
+
+// Load decodes the image at path in fsys into RGBA. With flipY the rows are reversed so
+// the first row is the bottom of the image, which is what gl.TexImage2D expects for the
+// texture coordinate (0, 0) to be the bottom left corner.
+func Load(fsys fs.FS, path string, flipY bool) (*image.RGBA, error) {
+	f, err := fsys.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("image %q not found: %v", path, err)
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("decode image %q: %v", path, err)
+	}
+
+	rgba := ToRGBA(img)
+	if flipY {
+		FlipY(rgba)
+	}
+
+	return rgba, nil
+}
+
+// Decode decodes the PNG or JPEG image in data into RGBA, like Load.
+func Decode(data []byte, flipY bool) (*image.RGBA, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decode image: %v", err)
+	}
+
+	rgba := ToRGBA(img)
+	if flipY {
+		FlipY(rgba)
+	}
+
+	return rgba, nil
+}
+
+// ToRGBA returns a copy of img as RGBA with its bounds starting at (0, 0).
+func ToRGBA(img image.Image) *image.RGBA {
+	rgba := image.NewRGBA(image.Rect(0, 0, img.Bounds().Dx(), img.Bounds().Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, img.Bounds().Min, draw.Src)
+	return rgba
+}
+
+// FlipY reverses the order of the rows of img in place.
+func FlipY(img *image.RGBA) {
+	rowLen := img.Bounds().Dx() * 4
+	row := make([]uint8, rowLen)
+
+	for top, bottom := 0, img.Bounds().Dy()-1; top < bottom; top, bottom = top+1, bottom-1 {
+		topRow := img.Pix[top*img.Stride : top*img.Stride+rowLen]
+		bottomRow := img.Pix[bottom*img.Stride : bottom*img.Stride+rowLen]
+
+		copy(row, topRow)
+		copy(topRow, bottomRow)
+		copy(bottomRow, row)
+	}
+}
+
+// FromGL wraps the RGBA pixels returned by gl.ReadPixels, whose first row is the bottom
+// of the framebuffer, into an upright image. The rows of pix are flipped in place.
+func FromGL(pix []uint8, width, height int) *image.RGBA {
+	img := &image.RGBA{
+		Pix:    pix,
+		Stride: width * 4,
+		Rect:   image.Rect(0, 0, width, height),
+	}
+	FlipY(img)
+
+	return img
+}
@@ -0,0 +1,147 @@
+package imageutil
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+	"testing/fstest"
+)
+
+// rowColors are the colors of the rows of the test image, from the top.
+var rowColors = []color.RGBA{
+	{R: 255, A: 255},
+	{G: 255, A: 255},
+	{B: 255, A: 255},
+}
+
+// newTestImage returns a 2x3 image whose rows are rowColors.
+func newTestImage() *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, 2, len(rowColors)))
+	for y, c := range rowColors {
+		for x := range 2 {
+			img.SetRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+// checkRows fails unless the rows of img are want, from the top.
+func checkRows(t *testing.T, img *image.RGBA, want []color.RGBA) {
+	t.Helper()
+
+	if got := img.Bounds(); got != image.Rect(0, 0, 2, len(want)) {
+		t.Fatalf("bounds = %v, want 2x%d", got, len(want))
+	}
+	for y, c := range want {
+		for x := range 2 {
+			if got := img.RGBAAt(x, y); got != c {
+				t.Errorf("pixel (%d, %d) = %v, want %v", x, y, got, c)
+			}
+		}
+	}
+}
+
+func reversed(colors []color.RGBA) []color.RGBA {
+	r := make([]color.RGBA, len(colors))
+	for i, c := range colors {
+		r[len(colors)-1-i] = c
+	}
+	return r
+}
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestFlipY(t *testing.T) {
+	img := newTestImage()
+
+	FlipY(img)
+	checkRows(t, img, reversed(rowColors))
+
+	FlipY(img)
+	checkRows(t, img, rowColors)
+}
+
+func TestFlipYSubImage(t *testing.T) {
+	// The stride of a sub image is the one of its parent
+	parent := image.NewRGBA(image.Rect(0, 0, 4, 3))
+	img := parent.SubImage(image.Rect(0, 0, 2, 3)).(*image.RGBA)
+	for y, c := range rowColors {
+		for x := range 2 {
+			img.SetRGBA(x, y, c)
+		}
+	}
+
+	FlipY(img)
+	checkRows(t, img, reversed(rowColors))
+
+	if got := parent.RGBAAt(3, 0); got != (color.RGBA{}) {
+		t.Errorf("pixel (3, 0) of the parent = %v, want it untouched", got)
+	}
+}
+
+func TestFromGL(t *testing.T) {
+	// gl.ReadPixels returns the bottom row first
+	var pix []uint8
+	for _, c := range reversed(rowColors) {
+		for range 2 {
+			pix = append(pix, c.R, c.G, c.B, c.A)
+		}
+	}
+
+	checkRows(t, FromGL(pix, 2, 3), rowColors)
+}
+
+func TestDecode(t *testing.T) {
+	data := encodePNG(t, newTestImage())
+
+	tests := []struct {
+		name  string
+		flipY bool
+		want  []color.RGBA
+	}{
+		{name: "top-down", flipY: false, want: rowColors},
+		{name: "flipped", flipY: true, want: reversed(rowColors)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img, err := Decode(data, tt.flipY)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkRows(t, img, tt.want)
+		})
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	if _, err := Decode([]byte("not an image"), false); err == nil {
+		t.Error("Decode of garbage succeeded")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"textures/rows.png": {Data: encodePNG(t, newTestImage())},
+	}
+
+	img, err := Load(fsys, "textures/rows.png", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRows(t, img, reversed(rowColors))
+
+	if _, err := Load(fsys, "textures/missing.png", true); err == nil {
+		t.Error("Load of a missing file succeeded")
+	}
+}
@@ -0,0 +1,149 @@
+package light
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/igoramorim/gopengl/pkg/shader"
+)
+
+// Sizes, in bytes, of the light structs in the std140 layout. Each vec3 takes 16 bytes
+// unless it is followed by a float, which is packed in its 4 last bytes.
+const (
+	directionalSize = 64
+	pointSize       = 64
+	spotSize        = 80
+	// pointLightsOffset is where the point lights start, after the directional light
+	// and the two counts aligned to 16 bytes
+	pointLightsOffset = directionalSize + 16
+)
+
+// NewUniformBuffer creates a buffer for up to maxPointLights and maxSpotLights bound to
+// the uniform buffer binding point, which is shared by every shader bound to it.
+// The shaders must declare the arrays of the Lights block of shaders/lights.glsl with
+// the same sizes.
+func NewUniformBuffer(binding uint32, maxPointLights, maxSpotLights int) (*UniformBuffer, error) {
+	if maxPointLights < 1 || maxSpotLights < 1 {
+		return nil, fmt.Errorf("light: the uniform buffer needs room for 1 point and 1 spot light at least, got %d and %d",
+			maxPointLights, maxSpotLights)
+	}
+
+	b := &UniformBuffer{
+		binding:   binding,
+		maxPoints: maxPointLights,
+		maxSpots:  maxSpotLights,
+	}
+	b.data = make([]byte, b.spotLightsOffset()+maxSpotLights*spotSize)
+
+	gl.GenBuffers(1, &b.id)
+	gl.BindBuffer(gl.UNIFORM_BUFFER, b.id)
+	gl.BufferData(gl.UNIFORM_BUFFER, len(b.data), nil, gl.DYNAMIC_DRAW)
+	gl.BindBuffer(gl.UNIFORM_BUFFER, 0)
+
+	gl.BindBufferBase(gl.UNIFORM_BUFFER, binding, b.id)
+
+	return b, nil
+}
+
+// UniformBuffer holds the lights of a scene in a uniform buffer, so they are uploaded
+// once per frame however many shaders use them.
+type UniformBuffer struct {
+	id        uint32
+	binding   uint32
+	maxPoints int
+	maxSpots  int
+	// data is the std140 content of the buffer
+	data []byte
+}
+
+func (b *UniformBuffer) spotLightsOffset() int {
+	return pointLightsOffset + b.maxPoints*pointSize
+}
+
+// Update uploads the lights. dir can be nil when the scene has no directional light.
+func (b *UniformBuffer) Update(dir *DirectionalLight, points []*PointLight, spots []*SpotLight) error {
+	if len(points) > b.maxPoints || len(spots) > b.maxSpots {
+		return fmt.Errorf("light: the uniform buffer holds %d point and %d spot lights, got %d and %d",
+			b.maxPoints, b.maxSpots, len(points), len(spots))
+	}
+
+	clear(b.data)
+	w := std140(b.data)
+
+	if dir != nil {
+		w.vec3(0, dir.Direction)
+		w.colors(16, dir.Colors, nil)
+	}
+
+	w.int(directionalSize, int32(len(points)))
+	w.int(directionalSize+4, int32(len(spots)))
+
+	for i, l := range points {
+		at := pointLightsOffset + i*pointSize
+		w.vec3(at, l.Position)
+		w.colors(at+16, l.Colors, []float32{l.Linear, l.Quadratic})
+		w.float(at+12, l.Constant)
+	}
+
+	for i, l := range spots {
+		at := b.spotLightsOffset() + i*spotSize
+		w.vec3(at, l.Position)
+		w.float(at+12, l.Constant)
+		w.vec3(at+16, l.Direction)
+		w.float(at+28, l.Linear)
+		w.colors(at+32, l.Colors, []float32{l.Quadratic, cos(l.CutOff), cos(l.OuterCutOff)})
+	}
+
+	gl.BindBuffer(gl.UNIFORM_BUFFER, b.id)
+	gl.BufferSubData(gl.UNIFORM_BUFFER, 0, len(b.data), gl.Ptr(b.data))
+	gl.BindBuffer(gl.UNIFORM_BUFFER, 0)
+
+	return nil
+}
+
+// Bind connects the uniform block called block of s to the buffer. The connection is
+// part of the program, so it has to be done again after s is reloaded.
+func (b *UniformBuffer) Bind(s *shader.Shader, block string) error {
+	index := gl.GetUniformBlockIndex(s.ID, gl.Str(block+"\x00"))
+	if index == gl.INVALID_INDEX {
+		return fmt.Errorf("light: uniform block %q not found", block)
+	}
+
+	gl.UniformBlockBinding(s.ID, index, b.binding)
+	return nil
+}
+
+func (b *UniformBuffer) Delete() {
+	gl.DeleteBuffers(1, &b.id)
+}
+
+// std140 writes values at byte offsets of a uniform block in the std140 layout.
+type std140 []byte
+
+func (w std140) float(at int, v float32) {
+	binary.NativeEndian.PutUint32(w[at:], math.Float32bits(v))
+}
+
+func (w std140) int(at int, v int32) {
+	binary.NativeEndian.PutUint32(w[at:], uint32(v))
+}
+
+func (w std140) vec3(at int, v mgl32.Vec3) {
+	for i, f := range v {
+		w.float(at+i*4, f)
+	}
+}
+
+// colors writes the ambient, diffuse and specular colors 16 bytes apart, starting at at,
+// and packs the floats after the first of them.
+func (w std140) colors(at int, c Colors, floats []float32) {
+	for i, color := range []mgl32.Vec3{c.Ambient, c.Diffuse, c.Specular} {
+		w.vec3(at+i*16, color.Mul(c.Intensity))
+		if i < len(floats) {
+			w.float(at+i*16+12, floats[i])
+		}
+	}
+}
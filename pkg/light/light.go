@@ -0,0 +1,247 @@
+// Package light describes the lights of a scene and the shadows they cast.
+//
+// The lights upload themselves to the structs declared in shaders/lights.glsl, either
+// one by one, into arrays like pointLights[i], or all at once into a UniformBuffer.
+package light
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/igoramorim/gopengl/pkg/shader"
+	"github.com/igoramorim/gopengl/pkg/shadow"
+)
+
+// Colors are the Phong terms of a light. They are multiplied by Intensity when uploaded,
+// so a light with the zero Colors is off.
+type Colors struct {
+	Ambient   mgl32.Vec3
+	Diffuse   mgl32.Vec3
+	Specular  mgl32.Vec3
+	Intensity float32
+}
+
+// NewColors returns the Colors of a light of color, of which ambient and diffuse are
+// the fractions that light the surfaces facing away from it and facing it. The
+// highlights are of the full color.
+func NewColors(color mgl32.Vec3, ambient, diffuse float32) Colors {
+	return Colors{
+		Ambient:   color.Mul(ambient),
+		Diffuse:   color.Mul(diffuse),
+		Specular:  color,
+		Intensity: 1.0,
+	}
+}
+
+func (c Colors) upload(s *shader.Shader, name string) error {
+	return errors.Join(
+		s.SetVec3(name+".ambient", c.Ambient.Mul(c.Intensity)),
+		s.SetVec3(name+".diffuse", c.Diffuse.Mul(c.Intensity)),
+		s.SetVec3(name+".specular", c.Specular.Mul(c.Intensity)),
+	)
+}
+
+// Attenuation are the terms of 1 / (constant + linear * d + quadratic * d²), how much of
+// the light reaches a distance d.
+type Attenuation struct {
+	Constant  float32
+	Linear    float32
+	Quadratic float32
+}
+
+// attenuations by the distance the light covers, from Ogre3D's wiki.
+var attenuations = []struct {
+	distance    float32
+	attenuation Attenuation
+}{
+	{7, Attenuation{1.0, 0.7, 1.8}},
+	{13, Attenuation{1.0, 0.35, 0.44}},
+	{20, Attenuation{1.0, 0.22, 0.20}},
+	{32, Attenuation{1.0, 0.14, 0.07}},
+	{50, Attenuation{1.0, 0.09, 0.032}},
+	{65, Attenuation{1.0, 0.07, 0.017}},
+	{100, Attenuation{1.0, 0.045, 0.0075}},
+	{160, Attenuation{1.0, 0.027, 0.0028}},
+	{200, Attenuation{1.0, 0.022, 0.0019}},
+	{325, Attenuation{1.0, 0.014, 0.0007}},
+	{600, Attenuation{1.0, 0.007, 0.0002}},
+	{3250, Attenuation{1.0, 0.0014, 0.000007}},
+}
+
+// Range returns the attenuation of a light that fades out at distance, rounded up to
+// the closest preset.
+func Range(distance float32) Attenuation {
+	for _, a := range attenuations {
+		if distance <= a.distance {
+			return a.attenuation
+		}
+	}
+	return attenuations[len(attenuations)-1].attenuation
+}
+
+func (a Attenuation) upload(s *shader.Shader, name string) error {
+	return errors.Join(
+		s.SetFloat(name+".constant", a.Constant),
+		s.SetFloat(name+".linear", a.Linear),
+		s.SetFloat(name+".quadratic", a.Quadratic),
+	)
+}
+
+// DirectionalLight is a light infinitely far away, like the sun, whose rays are parallel.
+type DirectionalLight struct {
+	Direction mgl32.Vec3
+	Colors
+
+	// Shadow is the shadow map of the light, nil when it casts no shadows.
+	Shadow *shadow.Map
+}
+
+// Upload sets the fields of the DirectionalLight struct uniform called name.
+// s must be in use.
+func (l *DirectionalLight) Upload(s *shader.Shader, name string) error {
+	return errors.Join(
+		s.SetVec3(name+".direction", l.Direction),
+		l.Colors.upload(s, name),
+	)
+}
+
+// LightSpace returns the orthographic projection and view of the light that cover the
+// sphere of radius around center, the part of the scene that casts and receives shadows.
+func (l *DirectionalLight) LightSpace(center mgl32.Vec3, radius float32) mgl32.Mat4 {
+	dir := l.Direction.Normalize()
+	eye := center.Sub(dir.Mul(radius))
+
+	projection := mgl32.Ortho(-radius, radius, -radius, radius, 0.0, 2*radius)
+	view := mgl32.LookAtV(eye, center, up(dir))
+
+	return projection.Mul4(view)
+}
+
+// RenderShadow renders the shadow map of the light, if any, for the sphere of radius
+// around center. See LightSpace.
+func (l *DirectionalLight) RenderShadow(center mgl32.Vec3, radius float32, draw shadow.DrawFunc) {
+	if l.Shadow == nil {
+		return
+	}
+	l.Shadow.Render(l.LightSpace(center, radius), draw)
+}
+
+// PointLight is a light at a position that shines in every direction.
+type PointLight struct {
+	Position mgl32.Vec3
+	Colors
+	Attenuation
+
+	// Shadow is the shadow map of the light, nil when it casts no shadows.
+	Shadow *shadow.CubeMap
+}
+
+// Upload sets the fields of the PointLight struct uniform called name.
+// s must be in use.
+func (l *PointLight) Upload(s *shader.Shader, name string) error {
+	return errors.Join(
+		s.SetVec3(name+".position", l.Position),
+		l.Colors.upload(s, name),
+		l.Attenuation.upload(s, name),
+	)
+}
+
+// RenderShadow renders the shadow map of the light, if any.
+func (l *PointLight) RenderShadow(draw shadow.DrawFunc) {
+	if l.Shadow == nil {
+		return
+	}
+	l.Shadow.Render(l.Position, draw)
+}
+
+// SpotLight is a light at a position that shines in a cone around its direction.
+type SpotLight struct {
+	Position  mgl32.Vec3
+	Direction mgl32.Vec3
+
+	// CutOff is the angle, in degrees, of the cone lit at full intensity and OuterCutOff
+	// where the light fades out to nothing.
+	CutOff      float32
+	OuterCutOff float32
+
+	Colors
+	Attenuation
+
+	// Shadow is the shadow map of the light, nil when it casts no shadows.
+	Shadow *shadow.Map
+}
+
+// Upload sets the fields of the SpotLight struct uniform called name. The cut offs are
+// uploaded as the cosines of the angles, which is what the shader compares.
+// s must be in use.
+func (l *SpotLight) Upload(s *shader.Shader, name string) error {
+	return errors.Join(
+		s.SetVec3(name+".position", l.Position),
+		s.SetVec3(name+".direction", l.Direction),
+		s.SetFloat(name+".cutOff", cos(l.CutOff)),
+		s.SetFloat(name+".outerCutOff", cos(l.OuterCutOff)),
+		l.Colors.upload(s, name),
+		l.Attenuation.upload(s, name),
+	)
+}
+
+// LightSpace returns the perspective projection and view of the light that cover its
+// cone, from near to far.
+func (l *SpotLight) LightSpace(near, far float32) mgl32.Mat4 {
+	dir := l.Direction.Normalize()
+
+	projection := mgl32.Perspective(mgl32.DegToRad(2*l.OuterCutOff), 1.0, near, far)
+	view := mgl32.LookAtV(l.Position, l.Position.Add(dir), up(dir))
+
+	return projection.Mul4(view)
+}
+
+// RenderShadow renders the shadow map of the light, if any, in the range of its options.
+func (l *SpotLight) RenderShadow(draw shadow.DrawFunc) {
+	if l.Shadow == nil {
+		return
+	}
+	l.Shadow.Render(l.LightSpace(l.Shadow.Near, l.Shadow.Far), draw)
+}
+
+// UploadPointLights uploads the lights to the elements of the array uniform called name,
+// e.g. pointLights[0], pointLights[1] and so on. s must be in use.
+func UploadPointLights(s *shader.Shader, name string, lights []*PointLight) error {
+	var errs []error
+	for i, l := range lights {
+		errs = append(errs, l.Upload(s, fmt.Sprintf("%s[%d]", name, i)))
+	}
+	return errors.Join(errs...)
+}
+
+// UploadSpotLights uploads the lights to the elements of the array uniform called name,
+// e.g. spotLights[0], spotLights[1] and so on. s must be in use.
+func UploadSpotLights(s *shader.Shader, name string, lights []*SpotLight) error {
+	var errs []error
+	for i, l := range lights {
+		errs = append(errs, l.Upload(s, fmt.Sprintf("%s[%d]", name, i)))
+	}
+	return errors.Join(errs...)
+}
+
+// up returns an up vector for a view looking at dir, which can not be parallel to it.
+func up(dir mgl32.Vec3) mgl32.Vec3 {
+	if abs(dir.Y()) > 0.99 {
+		return mgl32.Vec3{0.0, 0.0, 1.0}
+	}
+	return mgl32.Vec3{0.0, 1.0, 0.0}
+}
+
+func abs(x float32) float32 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// cos returns the cosine of degrees.
+func cos(degrees float32) float32 {
+	return float32(math.Cos(float64(mgl32.DegToRad(degrees))))
+}
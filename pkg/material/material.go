@@ -0,0 +1,250 @@
+// Package material describes how a surface reflects light, with the colors and
+// textures read by the shaders.
+package material
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/igoramorim/gopengl/pkg/shader"
+	"github.com/igoramorim/gopengl/pkg/texture"
+)
+
+// TextureType is what a texture of a material holds.
+type TextureType int
+
+const (
+	DiffuseMap TextureType = iota
+	SpecularMap
+	NormalMap
+	HeightMap
+	EmissiveMap
+	// MetallicRoughnessMap holds the roughness in the green channel and the metalness in
+	// the blue one, like glTF.
+	MetallicRoughnessMap
+	// OcclusionMap holds the ambient occlusion in the red channel.
+	OcclusionMap
+	numTextureTypes
+)
+
+func (t TextureType) String() string {
+	switch t {
+	case DiffuseMap:
+		return "diffuse"
+	case SpecularMap:
+		return "specular"
+	case NormalMap:
+		return "normal"
+	case HeightMap:
+		return "height"
+	case EmissiveMap:
+		return "emissive"
+	case MetallicRoughnessMap:
+		return "metallicRoughness"
+	case OcclusionMap:
+		return "occlusion"
+	default:
+		return fmt.Sprintf("TextureType(%d)", int(t))
+	}
+}
+
+// New returns a white, opaque material with a soft highlight and no textures.
+func New(name string) *Material {
+	return &Material{
+		Name:      name,
+		Ambient:   mgl32.Vec3{1.0, 1.0, 1.0},
+		Diffuse:   mgl32.Vec3{1.0, 1.0, 1.0},
+		Specular:  mgl32.Vec3{0.5, 0.5, 0.5},
+		Shininess: 32.0,
+		Opacity:   1.0,
+		Roughness: 1.0,
+	}
+}
+
+type Material struct {
+	Name string
+
+	// Ambient, Diffuse and Specular are the colors reflected of the Phong terms of a light
+	// and Emissive the color given off by the surface itself.
+	Ambient  mgl32.Vec3
+	Diffuse  mgl32.Vec3
+	Specular mgl32.Vec3
+	Emissive mgl32.Vec3
+
+	// Shininess is the exponent of the specular highlight, the higher the smaller.
+	Shininess float32
+
+	// Opacity goes from 0 (transparent) to 1 (opaque).
+	Opacity float32
+
+	// Metallic and Roughness go from 0 to 1, for PBR shaders.
+	Metallic  float32
+	Roughness float32
+
+	// Textures by type, nil when the material has none of the type. They are not owned
+	// by the material, so Delete keeps them.
+	Textures [numTextureTypes]*texture.Texture
+
+	// fallbacks are the 1x1 textures bound when the shader samples a type the material
+	// has no texture of
+	fallbacks [numTextureTypes]*fallback
+}
+
+// fallback is a 1x1 texture and the color it holds.
+type fallback struct {
+	texture *texture.Texture
+	color   color.RGBA
+}
+
+// Bind sets the fields of the struct uniform called name that the shader declares,
+// binding the textures from the unit gl.TEXTURE0 + firstUnit onwards, and returns the
+// next free unit. s must be in use.
+//
+// The fields are matched by name and type:
+//
+//	ambient, ambientColor        vec3
+//	diffuse, diffuseColor        vec3, or vec4 with the opacity
+//	specular, specularColor      vec3
+//	emission, emissive,
+//	emissiveColor                vec3
+//	shininess, opacity,
+//	metallic, roughness          float
+//	diffuse, diffuseMap          sampler2D, and the same for specular, normal, height,
+//	                             emission, emissive, metallicRoughness and occlusion
+//
+// When a sampler has no texture in the material a 1x1 texture of the matching color
+// is bound instead: Diffuse, Specular or Emissive, a flat normal, no height, Metallic and
+// Roughness or no occlusion. So
+// shaders written for textures still render materials that only have colors.
+func (m *Material) Bind(s *shader.Shader, name string, firstUnit uint32) (uint32, error) {
+	var errs []error
+	unit := firstUnit
+
+	set := func(field string, xtype uint32, set func(uniform string) error) bool {
+		uniform := name + "." + field
+		if t, ok := s.UniformType(uniform); !ok || t != xtype {
+			return false
+		}
+		errs = append(errs, set(uniform))
+		return true
+	}
+
+	vec3 := func(value mgl32.Vec3, fields ...string) {
+		for _, field := range fields {
+			set(field, gl.FLOAT_VEC3, func(uniform string) error { return s.SetVec3(uniform, value) })
+		}
+	}
+
+	sampler := func(t TextureType, fields ...string) {
+		for _, field := range fields {
+			bound := set(field, gl.SAMPLER_2D, func(uniform string) error {
+				gl.ActiveTexture(gl.TEXTURE0 + unit)
+				m.texture(t).Bind()
+				return s.SetInt(uniform, int32(unit))
+			})
+			if bound {
+				unit++
+			}
+		}
+	}
+
+	vec3(m.Ambient, "ambient", "ambientColor")
+	vec3(m.Diffuse, "diffuse", "diffuseColor")
+	vec3(m.Specular, "specular", "specularColor")
+	vec3(m.Emissive, "emission", "emissive", "emissiveColor")
+
+	diffuse := m.Diffuse.Vec4(m.Opacity)
+	for _, field := range []string{"diffuse", "diffuseColor"} {
+		set(field, gl.FLOAT_VEC4, func(uniform string) error { return s.SetVec4(uniform, diffuse) })
+	}
+
+	set("shininess", gl.FLOAT, func(uniform string) error { return s.SetFloat(uniform, m.Shininess) })
+	set("opacity", gl.FLOAT, func(uniform string) error { return s.SetFloat(uniform, m.Opacity) })
+	set("metallic", gl.FLOAT, func(uniform string) error { return s.SetFloat(uniform, m.Metallic) })
+	set("roughness", gl.FLOAT, func(uniform string) error { return s.SetFloat(uniform, m.Roughness) })
+
+	sampler(DiffuseMap, "diffuse", "diffuseMap")
+	sampler(SpecularMap, "specular", "specularMap")
+	sampler(NormalMap, "normal", "normalMap")
+	sampler(HeightMap, "height", "heightMap")
+	sampler(EmissiveMap, "emission", "emissive", "emissionMap", "emissiveMap")
+	sampler(MetallicRoughnessMap, "metallicRoughness", "metallicRoughnessMap")
+	sampler(OcclusionMap, "occlusion", "occlusionMap")
+
+	gl.ActiveTexture(gl.TEXTURE0)
+
+	return unit, errors.Join(errs...)
+}
+
+// texture returns the texture of type t, or its fallback.
+func (m *Material) texture(t TextureType) *texture.Texture {
+	if m.Textures[t] != nil {
+		return m.Textures[t]
+	}
+
+	c := m.fallbackColor(t)
+
+	f := m.fallbacks[t]
+	if f != nil && f.color == c {
+		return f.texture
+	}
+	if f != nil {
+		// The color of the material changed
+		f.texture.Delete()
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, 1, 1))
+	img.SetRGBA(0, 0, c)
+
+	opts := texture.DefaultOptions
+	opts.MinFilter = gl.NEAREST
+	opts.MagFilter = gl.NEAREST
+	opts.Mipmaps = false
+
+	// A 1x1 texture with valid options can not fail to be created
+	tex, _ := texture.NewFromImage(img, gl.TEXTURE0, opts)
+	m.fallbacks[t] = &fallback{texture: tex, color: c}
+
+	return tex
+}
+
+func (m *Material) fallbackColor(t TextureType) color.RGBA {
+	switch t {
+	case DiffuseMap:
+		return toRGBA(m.Diffuse, m.Opacity)
+	case SpecularMap:
+		return toRGBA(m.Specular, 1.0)
+	case EmissiveMap:
+		return toRGBA(m.Emissive, 1.0)
+	case NormalMap:
+		// (0, 0, 1) in tangent space
+		return color.RGBA{128, 128, 255, 255}
+	case MetallicRoughnessMap:
+		return toRGBA(mgl32.Vec3{0.0, m.Roughness, m.Metallic}, 1.0)
+	case OcclusionMap:
+		return color.RGBA{255, 255, 255, 255}
+	default:
+		return color.RGBA{0, 0, 0, 255}
+	}
+}
+
+func toRGBA(c mgl32.Vec3, alpha float32) color.RGBA {
+	channel := func(v float32) uint8 {
+		return uint8(mgl32.Clamp(v, 0.0, 1.0)*255.0 + 0.5)
+	}
+	return color.RGBA{channel(c.X()), channel(c.Y()), channel(c.Z()), channel(alpha)}
+}
+
+// Delete releases the fallback textures. The textures of the material are kept.
+func (m *Material) Delete() {
+	for i, f := range m.fallbacks {
+		if f != nil {
+			f.texture.Delete()
+			m.fallbacks[i] = nil
+		}
+	}
+}
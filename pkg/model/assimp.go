@@ -0,0 +1,320 @@
+//go:build !noassimp
+
+package model
+
+import (
+	"encoding/binary"
+	"math"
+	"reflect"
+
+	"github.com/bloeys/assimp-go/asig"
+	"github.com/bloeys/gglm/gglm"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/igoramorim/gopengl/pkg/animation"
+	"github.com/igoramorim/gopengl/pkg/material"
+)
+
+// loadAssimp loads the meshes and materials of any format supported by assimp.
+func (l *loader) loadAssimp(path string) error {
+	scene, release, err := asig.ImportFileEx(path, asig.PostProcessTriangulate|asig.PostProcessGenSmoothNormals|asig.PostProcessCalcTangentSpace, l.fsys)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	// fmt.Printf("path received:%s\n\n", path)
+	// fmt.Printf("root node:\n%+v\n\n", scene.RootNode)
+
+	// Added in order, so the indices of the materials are the ones of assimp
+	for _, aiMaterial := range scene.Materials {
+		if err := l.loadMaterial(aiMaterial); err != nil {
+			return err
+		}
+	}
+
+	l.loadAssimpSkeleton(scene.RootNode)
+
+	if err := l.processNode(scene.RootNode, scene); err != nil {
+		return err
+	}
+
+	if len(l.data.Skeleton.Bones) == 0 {
+		l.data.Skeleton = nil
+	}
+
+	return nil
+}
+
+// loadAssimpSkeleton sets the joints of the skeleton from the nodes, the bones are added
+// by the meshes. asig does not read the keyframes of the animations, so there are no
+// clips.
+func (l *loader) loadAssimpSkeleton(root *asig.Node) {
+	skeleton := &animation.Skeleton{}
+
+	var visit func(node *asig.Node, parent int)
+	visit = func(node *asig.Node, parent int) {
+		joint := len(skeleton.Joints)
+		skeleton.Joints = append(skeleton.Joints, animation.Joint{
+			Name:   node.Name,
+			Parent: parent,
+			Rest:   animation.Decompose(toMat4(node.Transformation)),
+		})
+		for _, c := range node.Children {
+			visit(c, joint)
+		}
+	}
+	visit(root, -1)
+
+	l.data.Skeleton = skeleton
+	l.bones = make(map[string]int)
+}
+
+// assimpBone returns the index of the bone, adding it the first time.
+func (l *loader) assimpBone(aiBone *asig.Bone) int {
+	if i, ok := l.bones[aiBone.Name]; ok {
+		return i
+	}
+
+	skeleton := l.data.Skeleton
+	i := len(skeleton.Bones)
+	skeleton.Bones = append(skeleton.Bones, animation.Bone{
+		// The bones are named after their nodes, -1 would fail the validation
+		Joint:       max(skeleton.Joint(aiBone.Name), 0),
+		InverseBind: toMat4(&aiBone.OffsetMatrix),
+	})
+	l.bones[aiBone.Name] = i
+
+	return i
+}
+
+func toMat4(m *gglm.Mat4) mgl32.Mat4 {
+	if m == nil {
+		return mgl32.Ident4()
+	}
+
+	var mat mgl32.Mat4
+	for col := range 4 {
+		for row := range 4 {
+			mat.Set(row, col, m.Get(row, col))
+		}
+	}
+	return mat
+}
+
+func (l *loader) processNode(aiNode *asig.Node, aiScene *asig.Scene) error {
+	for _, i := range aiNode.MeshIndicies {
+		aiMesh := aiScene.Meshes[i]
+
+		l.processMesh(aiMesh)
+	}
+
+	for i := range aiNode.Children {
+		if err := l.processNode(aiNode.Children[i], aiScene); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (l *loader) processMesh(aiMesh *asig.Mesh) {
+	var vertices []Vertex
+	var indices []uint32
+
+	// Walk through each of the mesh's vertices
+	for i := range aiMesh.Vertices {
+		var vertex Vertex
+
+		// Position
+		vec3 := mgl32.Vec3{
+			aiMesh.Vertices[i].X(),
+			aiMesh.Vertices[i].Y(),
+			aiMesh.Vertices[i].Z(),
+		}
+		vertex.Position = vec3
+
+		// Normals
+		if len(aiMesh.Normals) > 0 {
+			vec3 = mgl32.Vec3{
+				aiMesh.Normals[i].X(),
+				aiMesh.Normals[i].Y(),
+				aiMesh.Normals[i].Z(),
+			}
+			vertex.Normal = vec3
+		}
+
+		// Texture Coordinates
+		if len(aiMesh.TexCoords[0]) > 0 {
+			// A vertex can contain up to 8 different texture coordinates. We thus make the assumption
+			// that we won't use models where a vertex can have multiple texture coordinates
+			// so we always take the first set (0)
+			vec2 := mgl32.Vec2{
+				aiMesh.TexCoords[0][i].X(),
+				aiMesh.TexCoords[0][i].Y(),
+			}
+			vertex.TexCoords = vec2
+
+			// Tangent
+			vec3 = mgl32.Vec3{
+				aiMesh.Tangents[i].X(),
+				aiMesh.Tangents[i].Y(),
+				aiMesh.Tangents[i].Z(),
+			}
+			vertex.Tangent = vec3
+
+			// Bitangent
+			vec3 = mgl32.Vec3{
+				aiMesh.BitTangents[i].X(),
+				aiMesh.BitTangents[i].Y(),
+				aiMesh.BitTangents[i].Z(),
+			}
+			vertex.Bitangent = vec3
+		} else {
+			vertex.TexCoords = mgl32.Vec2{0.0, 0.0}
+		}
+		vertices = append(vertices, vertex)
+	}
+	// fmt.Printf("vertices: %+v\n\n", vertices)
+
+	// Walk through each of the mesh's faces (a face is a mesh's triangle) and retrieve the
+	// corresponding vertex indices
+	for i := range aiMesh.Faces {
+		aiFace := aiMesh.Faces[i]
+		for j := range aiFace.Indices {
+			indices = append(indices, uint32(aiFace.Indices[j]))
+		}
+	}
+	// fmt.Printf("indices: %+v\n\n", indices)
+
+	for _, aiBone := range aiMesh.Bones {
+		bone := l.assimpBone(aiBone)
+		for _, w := range aiBone.Weights {
+			vertices[w.VertIndex].addBoneWeight(bone, w.Weight)
+		}
+	}
+	for i := range vertices {
+		vertices[i].normalizeWeights()
+	}
+
+	l.addMesh(aiMesh.Name, vertices, indices, int(aiMesh.MaterialIndex))
+}
+
+// materialTextures are the assimp texture types loaded into each material.TextureType.
+var materialTextures = []struct {
+	aiType asig.TextureType
+	xtype  material.TextureType
+}{
+	{asig.TextureTypeDiffuse, material.DiffuseMap},
+	{asig.TextureTypeSpecular, material.SpecularMap},
+	{asig.TextureTypeNormal, material.NormalMap},
+	{asig.TextureTypeHeight, material.HeightMap},
+	{asig.TextureTypeEmissive, material.EmissiveMap},
+}
+
+// loadMaterial adds an assimp material, loading its textures. Only the first texture of
+// each type is used.
+func (l *loader) loadMaterial(aiMaterial *asig.Material) error {
+	mat := material.New("")
+
+	for _, p := range aiMaterial.Properties {
+		switch propertyName(p) {
+		case "?mat.name":
+			mat.Name = propertyString(p)
+		case "$clr.ambient":
+			mat.Ambient = propertyVec3(p, mat.Ambient)
+		case "$clr.diffuse":
+			mat.Diffuse = propertyVec3(p, mat.Diffuse)
+		case "$clr.specular":
+			mat.Specular = propertyVec3(p, mat.Specular)
+		case "$clr.emissive":
+			mat.Emissive = propertyVec3(p, mat.Emissive)
+		case "$mat.shininess":
+			// Formats without a specular exponent report 0, which would light the
+			// whole surface as a highlight
+			if shininess := propertyFloat(p, 0); shininess > 0 {
+				mat.Shininess = shininess
+			}
+		case "$mat.opacity":
+			mat.Opacity = propertyFloat(p, mat.Opacity)
+		}
+	}
+
+	index := l.addMaterial(mat)
+
+	for _, t := range materialTextures {
+		if asig.GetMaterialTextureCount(aiMaterial, t.aiType) == 0 {
+			continue
+		}
+
+		info, err := asig.GetMaterialTexture(aiMaterial, t.aiType, 0)
+		if err != nil {
+			return err
+		}
+
+		tex, err := l.loadTexture(info.Path, t.xtype)
+		if err != nil {
+			return err
+		}
+		l.data.Materials[index].Textures[t.xtype] = tex
+	}
+
+	return nil
+}
+
+// propertyName returns the key of p, e.g. "$clr.diffuse". asig does not export it but
+// reflection can still read it.
+func propertyName(p *asig.MaterialProperty) string {
+	return reflect.ValueOf(p).Elem().FieldByName("name").String()
+}
+
+// propertyFloats decodes the numbers of p, which assimp stores in the native byte order.
+func propertyFloats(p *asig.MaterialProperty) []float32 {
+	var floats []float32
+
+	switch p.TypeInfo {
+	case asig.MatPropTypeInfoFloat32:
+		for i := 0; i+4 <= len(p.Data); i += 4 {
+			floats = append(floats, math.Float32frombits(binary.NativeEndian.Uint32(p.Data[i:])))
+		}
+	case asig.MatPropTypeInfoFloat64:
+		for i := 0; i+8 <= len(p.Data); i += 8 {
+			floats = append(floats, float32(math.Float64frombits(binary.NativeEndian.Uint64(p.Data[i:]))))
+		}
+	case asig.MatPropTypeInfoInt32:
+		for i := 0; i+4 <= len(p.Data); i += 4 {
+			floats = append(floats, float32(int32(binary.NativeEndian.Uint32(p.Data[i:]))))
+		}
+	}
+
+	return floats
+}
+
+func propertyFloat(p *asig.MaterialProperty, fallback float32) float32 {
+	floats := propertyFloats(p)
+	if len(floats) < 1 {
+		return fallback
+	}
+	return floats[0]
+}
+
+// propertyVec3 decodes a color, which is an RGB or RGBA color, ignoring the alpha.
+func propertyVec3(p *asig.MaterialProperty, fallback mgl32.Vec3) mgl32.Vec3 {
+	floats := propertyFloats(p)
+	if len(floats) < 3 {
+		return fallback
+	}
+	return mgl32.Vec3{floats[0], floats[1], floats[2]}
+}
+
+// propertyString decodes an aiString, its length as a uint32 followed by its bytes.
+func propertyString(p *asig.MaterialProperty) string {
+	if p.TypeInfo != asig.MatPropTypeInfoString || len(p.Data) < 4 {
+		return ""
+	}
+
+	n := int(binary.NativeEndian.Uint32(p.Data))
+	if 4+n > len(p.Data) {
+		return ""
+	}
+	return string(p.Data[4 : 4+n])
+}
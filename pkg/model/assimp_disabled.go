@@ -0,0 +1,11 @@
+//go:build noassimp
+
+package model
+
+import "errors"
+
+// loadAssimp fails when built with the noassimp tag, which drops the dependency on the
+// native assimp library. LoadOBJ and LoadGLTF still work.
+func (l *loader) loadAssimp(path string) error {
+	return errors.New("model: built without assimp (noassimp tag), only the OBJ and glTF loaders are available")
+}
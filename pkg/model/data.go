@@ -0,0 +1,185 @@
+package model
+
+import (
+	"fmt"
+	"image"
+	"io/fs"
+	"path"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/igoramorim/gopengl/pkg/animation"
+	"github.com/igoramorim/gopengl/pkg/bounds"
+	"github.com/igoramorim/gopengl/pkg/imageutil"
+	"github.com/igoramorim/gopengl/pkg/material"
+	"github.com/igoramorim/gopengl/pkg/texture"
+)
+
+// ModelData is a model loaded in memory, with its images decoded, but not uploaded to
+// the GPU. Loading it makes no GL calls, so it can happen in another goroutine than the
+// one of the context, and be inspected without one. See Upload.
+type ModelData struct {
+	Meshes    []MeshData
+	Materials []MaterialData
+	Textures  []TextureData
+	// Skeleton moves the vertices with bone weights, nil when the model has no bones.
+	Skeleton *animation.Skeleton
+	// Clips are the animations of Skeleton.
+	Clips []*animation.Clip
+}
+
+// MeshData is a mesh not uploaded to the GPU yet.
+type MeshData struct {
+	Name     string
+	Vertices []Vertex
+	// Indices are the vertices of the triangles, three by three.
+	Indices []uint32
+	// Material is the index of the material in ModelData.Materials.
+	Material int
+	// Bounds is the box around the positions of the vertices.
+	Bounds bounds.AABB
+	// Sphere is a sphere around the positions of the vertices, cheaper to test than
+	// Bounds but looser for long meshes.
+	Sphere bounds.Sphere
+}
+
+// MaterialData is a material whose textures are not uploaded yet.
+type MaterialData struct {
+	// Material has the colors of the material. Its textures are set by Upload.
+	Material material.Material
+	// Textures are the indices of the textures of the material in ModelData.Textures.
+	Textures map[material.TextureType]int
+}
+
+// TextureData is a decoded image and how to upload it.
+type TextureData struct {
+	// Name identifies the image in errors, e.g. its path.
+	Name    string
+	Image   *image.RGBA
+	Options texture.Options
+}
+
+// positionsOf returns the positions of the vertices.
+func positionsOf(vertices []Vertex) []mgl32.Vec3 {
+	positions := make([]mgl32.Vec3, len(vertices))
+	for i, v := range vertices {
+		positions[i] = v.Position
+	}
+	return positions
+}
+
+// Upload creates the textures, materials and buffers of the model. It must be called
+// from the goroutine of the GL context. On failure what was created is released.
+func (d *ModelData) Upload() (*Model, error) {
+	model := &Model{
+		skeleton: d.Skeleton,
+		clips:    d.Clips,
+	}
+
+	for _, t := range d.Textures {
+		tex, err := texture.NewFromImage(t.Image, gl.TEXTURE0, t.Options)
+		if err != nil {
+			model.Delete()
+			return nil, fmt.Errorf("model: texture %q: %w", t.Name, err)
+		}
+		model.textures = append(model.textures, tex)
+	}
+
+	for _, md := range d.Materials {
+		mat := md.Material
+		for xtype, i := range md.Textures {
+			mat.Textures[xtype] = model.textures[i]
+		}
+		model.materials = append(model.materials, &mat)
+	}
+
+	for _, meshData := range d.Meshes {
+		mesh := NewMesh(meshData, model.materials[meshData.Material])
+		mesh.Upload()
+		model.meshes = append(model.meshes, mesh)
+	}
+
+	return model, nil
+}
+
+// loader holds what the loaders of every format share to build a ModelData.
+type loader struct {
+	fsys fs.FS
+	// directory is the one of the model file, the paths of its textures are relative to it
+	directory       string
+	gammaCorrection bool
+
+	data *ModelData
+	// textures are the indices of the textures in data.Textures, by source
+	textures map[string]int
+	// bones are the indices of the bones in data.Skeleton.Bones, by name
+	bones map[string]int
+}
+
+func newLoader(fsys fs.FS, file string) *loader {
+	return &loader{
+		fsys:      fsys,
+		directory: path.Dir(file),
+		data:      &ModelData{},
+		textures:  make(map[string]int),
+	}
+}
+
+// addMaterial adds mat, without textures yet, and returns its index.
+func (l *loader) addMaterial(mat *material.Material) int {
+	l.data.Materials = append(l.data.Materials, MaterialData{
+		Material: *mat,
+		Textures: make(map[material.TextureType]int),
+	})
+	return len(l.data.Materials) - 1
+}
+
+// addMesh adds a mesh of the material at index mat, computing its bounds.
+func (l *loader) addMesh(name string, vertices []Vertex, indices []uint32, mat int) {
+	positions := positionsOf(vertices)
+	l.data.Meshes = append(l.data.Meshes, MeshData{
+		Name:     name,
+		Vertices: vertices,
+		Indices:  indices,
+		Material: mat,
+		Bounds:   bounds.AABBOf(positions),
+		Sphere:   bounds.SphereOf(positions),
+	})
+}
+
+// textureOptions returns how a texture of type xtype is uploaded.
+func (l *loader) textureOptions(xtype material.TextureType) texture.Options {
+	opts := texture.DefaultOptions
+	// Only the color textures are stored in sRGB, the others hold data
+	opts.SRGB = l.gammaCorrection && (xtype == material.DiffuseMap || xtype == material.EmissiveMap)
+	return opts
+}
+
+// loadTexture decodes the texture at file, relative to the model, once for the whole
+// model and returns its index.
+func (l *loader) loadTexture(file string, xtype material.TextureType) (int, error) {
+	opts := l.textureOptions(xtype)
+	return l.addTexture(fmt.Sprintf("%s %t", file, opts.SRGB), file, opts, func() (*image.RGBA, error) {
+		// Joined, so files in other directories, like ../textures/wood.png, are valid in fsys
+		return imageutil.Load(l.fsys, path.Join(l.directory, file), opts.FlipY)
+	})
+}
+
+// addTexture decodes the texture identified by key, once for the whole model, and
+// returns its index.
+func (l *loader) addTexture(key, name string, opts texture.Options, decode func() (*image.RGBA, error)) (int, error) {
+	if i, ok := l.textures[key]; ok {
+		return i, nil
+	}
+
+	img, err := decode()
+	if err != nil {
+		return 0, err
+	}
+
+	l.data.Textures = append(l.data.Textures, TextureData{Name: name, Image: img, Options: opts})
+	i := len(l.data.Textures) - 1
+	l.textures[key] = i
+
+	return i, nil
+}
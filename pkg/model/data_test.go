@@ -0,0 +1,221 @@
+package model
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/png"
+	"math"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/igoramorim/gopengl/pkg/bounds"
+	"github.com/igoramorim/gopengl/pkg/material"
+)
+
+// testPNG returns a 2x2 png.
+func testPNG(t *testing.T) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 2, 2))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// meshWant is what a MeshData is checked against.
+type meshWant struct {
+	name     string
+	vertices int
+	indices  int
+	material int
+	bounds   bounds.AABB
+}
+
+func checkMeshes(t *testing.T, data *ModelData, want []meshWant) {
+	t.Helper()
+
+	if len(data.Meshes) != len(want) {
+		t.Fatalf("got %d meshes, want %d", len(data.Meshes), len(want))
+	}
+	for i, w := range want {
+		m := data.Meshes[i]
+		if m.Name != w.name {
+			t.Errorf("mesh %d name = %q, want %q", i, m.Name, w.name)
+		}
+		if len(m.Vertices) != w.vertices || len(m.Indices) != w.indices {
+			t.Errorf("mesh %q has %d vertices and %d indices, want %d and %d", w.name, len(m.Vertices), len(m.Indices), w.vertices, w.indices)
+		}
+		if m.Material != w.material {
+			t.Errorf("mesh %q material = %d, want %d", w.name, m.Material, w.material)
+		}
+		if m.Bounds != w.bounds {
+			t.Errorf("mesh %q bounds = %v, want %v", w.name, m.Bounds, w.bounds)
+		}
+		if m.Material < 0 || m.Material >= len(data.Materials) {
+			t.Errorf("mesh %q material %d out of the %d materials", w.name, m.Material, len(data.Materials))
+		}
+	}
+}
+
+// checkTextures fails unless the textures of the material at index mat are want.
+func checkTextures(t *testing.T, data *ModelData, mat int, want map[material.TextureType]int) {
+	t.Helper()
+
+	got := data.Materials[mat].Textures
+	if len(got) != len(want) {
+		t.Errorf("material %d has %d textures, want %d", mat, len(got), len(want))
+	}
+	for xtype, w := range want {
+		i, ok := got[xtype]
+		if !ok || i != w {
+			t.Errorf("material %d texture %v = %d, want %d", mat, xtype, i, w)
+			continue
+		}
+		if img := data.Textures[i].Image; img == nil || img.Bounds() != image.Rect(0, 0, 2, 2) {
+			t.Errorf("texture %d is not the decoded png", i)
+		}
+	}
+}
+
+func TestLoadOBJ(t *testing.T) {
+	const src = `
+mtllib quad.mtl
+v 0 0 0
+v 2 0 0
+v 2 1 0
+v 0 1 0
+v 0 0 -1
+vt 0 0
+vt 1 0
+vt 1 1
+vt 0 1
+vn 0 0 1
+o plain
+f 1 2 5
+o front
+usemtl paint
+f 1/1/1 2/2/1 3/3/1 4/4/1
+o floor
+usemtl bare
+f 1/1/1 2/2/1 5/4/1
+`
+	const mtl = `
+newmtl paint
+Kd 1 0 0
+map_Kd paint.png
+map_Ks paint.png
+
+newmtl bare
+Kd 0.5 0.5 0.5
+`
+	fsys := fstest.MapFS{
+		"models/quad/quad.obj":  {Data: []byte(src)},
+		"models/quad/quad.mtl":  {Data: []byte(mtl)},
+		"models/quad/paint.png": {Data: testPNG(t)},
+	}
+
+	data, err := LoadOBJ(fsys, "models/quad/quad.obj")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The materials of the library by name, bare then paint, then the default one
+	checkMeshes(t, data, []meshWant{
+		{name: "plain", vertices: 3, indices: 3, material: 2, bounds: bounds.AABB{Min: mgl32.Vec3{0, 0, -1}, Max: mgl32.Vec3{2, 0, 0}}},
+		{name: "front", vertices: 4, indices: 6, material: 1, bounds: bounds.AABB{Min: mgl32.Vec3{0, 0, 0}, Max: mgl32.Vec3{2, 1, 0}}},
+		{name: "floor", vertices: 3, indices: 3, material: 0, bounds: bounds.AABB{Min: mgl32.Vec3{0, 0, -1}, Max: mgl32.Vec3{2, 0, 0}}},
+	})
+
+	if len(data.Materials) != 3 {
+		t.Fatalf("got %d materials, want 3", len(data.Materials))
+	}
+	for i, name := range []string{"bare", "paint", ""} {
+		if got := data.Materials[i].Material.Name; got != name {
+			t.Errorf("material %d = %q, want %q", i, got, name)
+		}
+	}
+
+	// The diffuse and specular maps share the image, it is decoded once
+	if len(data.Textures) != 1 {
+		t.Fatalf("got %d textures, want 1", len(data.Textures))
+	}
+	checkTextures(t, data, 0, nil)
+	checkTextures(t, data, 1, map[material.TextureType]int{material.DiffuseMap: 0, material.SpecularMap: 0})
+}
+
+func TestLoadGLTF(t *testing.T) {
+	// A quad, as 4 positions and the indices of its 2 triangles
+	var buffer []byte
+	for _, f := range []float32{0, 0, 0, 1, 0, 0, 1, 1, 0, 0, 1, 0} {
+		buffer = binary.LittleEndian.AppendUint32(buffer, math.Float32bits(f))
+	}
+	for _, i := range []uint16{0, 1, 2, 0, 2, 3} {
+		buffer = binary.LittleEndian.AppendUint16(buffer, i)
+	}
+
+	src := fmt.Sprintf(`{
+  "asset": {"version": "2.0"},
+  "buffers": [{"uri": "data:application/octet-stream;base64,%s", "byteLength": %d}],
+  "bufferViews": [
+    {"buffer": 0, "byteLength": 48},
+    {"buffer": 0, "byteOffset": 48, "byteLength": 12}
+  ],
+  "accessors": [
+    {"bufferView": 0, "componentType": 5126, "count": 4, "type": "VEC3"},
+    {"bufferView": 1, "componentType": 5123, "count": 6, "type": "SCALAR"}
+  ],
+  "meshes": [
+    {"name": "painted", "primitives": [{"attributes": {"POSITION": 0}, "indices": 1, "material": 0}]},
+    {"name": "plain", "primitives": [{"attributes": {"POSITION": 0}, "indices": 1}]}
+  ],
+  "nodes": [{"mesh": 0}, {"mesh": 1, "translation": [0, 0, -2]}],
+  "materials": [{"name": "paint", "pbrMetallicRoughness": {"baseColorTexture": {"index": 0}}}],
+  "textures": [{"source": 0}],
+  "images": [{"uri": "paint.png"}]
+}`, base64.StdEncoding.EncodeToString(buffer), len(buffer))
+
+	fsys := fstest.MapFS{
+		"models/quad/quad.gltf": {Data: []byte(src)},
+		"models/quad/paint.png": {Data: testPNG(t)},
+	}
+
+	data, err := LoadGLTF(fsys, "models/quad/quad.gltf")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Without normals the faces are flat, a vertex per corner
+	checkMeshes(t, data, []meshWant{
+		{name: "painted", vertices: 6, indices: 6, material: 0, bounds: bounds.AABB{Min: mgl32.Vec3{0, 0, 0}, Max: mgl32.Vec3{1, 1, 0}}},
+		{name: "plain", vertices: 6, indices: 6, material: 1, bounds: bounds.AABB{Min: mgl32.Vec3{0, 0, -2}, Max: mgl32.Vec3{1, 1, -2}}},
+	})
+
+	if len(data.Materials) != 2 {
+		t.Fatalf("got %d materials, want 2", len(data.Materials))
+	}
+	if got := data.Materials[0].Material.Name; got != "paint" {
+		t.Errorf("material 0 = %q, want \"paint\"", got)
+	}
+	checkTextures(t, data, 0, map[material.TextureType]int{material.DiffuseMap: 0})
+	checkTextures(t, data, 1, nil)
+
+	if data.Skeleton != nil {
+		t.Error("got a skeleton for a model without skins")
+	}
+}
+
+func TestLoadMissingTexture(t *testing.T) {
+	fsys := fstest.MapFS{
+		"models/quad/quad.obj": {Data: []byte("mtllib quad.mtl\nv 0 0 0\nv 1 0 0\nv 0 1 0\nusemtl paint\nf 1 2 3\n")},
+		"models/quad/quad.mtl": {Data: []byte("newmtl paint\nmap_Kd paint.png\n")},
+	}
+
+	if _, err := LoadOBJ(fsys, "models/quad/quad.obj"); err == nil {
+		t.Error("LoadOBJ without the texture succeeded")
+	}
+}
@@ -0,0 +1,305 @@
+package model
+
+import (
+	"fmt"
+	"image"
+	"io/fs"
+	"os"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/igoramorim/gopengl/pkg/animation"
+	"github.com/igoramorim/gopengl/pkg/gltf"
+	"github.com/igoramorim/gopengl/pkg/imageutil"
+	"github.com/igoramorim/gopengl/pkg/material"
+)
+
+// NewGLTF loads the glTF or GLB model at path, and the files it references, from the
+// working directory. Unlike New it does not need the assimp library.
+func NewGLTF(path string) (*Model, error) {
+	return NewGLTFFS(os.DirFS("."), path)
+}
+
+// NewGLTFFS loads the glTF or GLB model at path, and the files it references, from fsys,
+// and uploads it. Unlike NewFS it does not need the assimp library.
+func NewGLTFFS(fsys fs.FS, path string) (*Model, error) {
+	data, err := LoadGLTF(fsys, path)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.Upload()
+}
+
+// LoadGLTF loads the glTF or GLB model at path, and the files it references, from fsys
+// without uploading it.
+func LoadGLTF(fsys fs.FS, path string) (*ModelData, error) {
+	l := newLoader(fsys, path)
+	if err := l.loadGLTF(path); err != nil {
+		return nil, err
+	}
+
+	return l.data, nil
+}
+
+func (l *loader) loadGLTF(file string) error {
+	gltfModel, err := gltf.Load(l.fsys, file)
+	if err != nil {
+		return err
+	}
+
+	materials := make([]int, len(gltfModel.Materials))
+	for i, gltfMaterial := range gltfModel.Materials {
+		mat, err := l.convertGLTFMaterial(gltfModel, gltfMaterial)
+		if err != nil {
+			return err
+		}
+		materials[i] = mat
+	}
+
+	boneBases := l.convertGLTFSkeleton(gltfModel)
+
+	defaultMaterial := -1
+
+	for _, gltfMesh := range gltfModel.Meshes {
+		var mat int
+		if gltfMesh.Material >= 0 {
+			mat = materials[gltfMesh.Material]
+		} else {
+			if defaultMaterial < 0 {
+				defaultMaterial = l.addMaterial(material.New(""))
+			}
+			mat = defaultMaterial
+		}
+
+		vertices := make([]Vertex, len(gltfMesh.Vertices))
+		for i, v := range gltfMesh.Vertices {
+			vertices[i] = Vertex{
+				Position:   v.Position,
+				Normal:     v.Normal,
+				TexCoords:  v.TexCoords[0],
+				Tangent:    v.Tangent,
+				Bitangent:  v.Bitangent,
+				TexCoords1: v.TexCoords[1],
+			}
+
+			if gltfMesh.Skin >= 0 {
+				for c, joint := range v.Joints {
+					vertices[i].addBoneWeight(boneBases[gltfMesh.Skin]+int(joint), v.Weights[c])
+				}
+				vertices[i].normalizeWeights()
+			}
+		}
+
+		l.addMesh(gltfMesh.Name, vertices, gltfMesh.Indices, mat)
+	}
+
+	return nil
+}
+
+// convertGLTFSkeleton sets the skeleton of the model, whose joints are all the nodes, and
+// its clips, and returns the index of the first bone of each skin. Only skinned meshes
+// are animated, so models without skins have no skeleton.
+func (l *loader) convertGLTFSkeleton(gltfModel *gltf.Model) []int {
+	if len(gltfModel.Skins) == 0 {
+		return nil
+	}
+
+	skeleton := &animation.Skeleton{}
+
+	children := make([][]int, len(gltfModel.Nodes))
+	for i, n := range gltfModel.Nodes {
+		if n.Parent >= 0 {
+			children[n.Parent] = append(children[n.Parent], i)
+		}
+	}
+
+	// The joints are sorted depth first, so the parents come before their children
+	jointOf := make([]int, len(gltfModel.Nodes))
+	var visit func(node, parent int)
+	visit = func(node, parent int) {
+		n := gltfModel.Nodes[node]
+		jointOf[node] = len(skeleton.Joints)
+		skeleton.Joints = append(skeleton.Joints, animation.Joint{
+			Name:   n.Name,
+			Parent: parent,
+			Rest:   animation.Decompose(n.Transform),
+		})
+		for _, c := range children[node] {
+			visit(c, jointOf[node])
+		}
+	}
+	for i, n := range gltfModel.Nodes {
+		if n.Parent < 0 {
+			visit(i, -1)
+		}
+	}
+
+	boneBases := make([]int, len(gltfModel.Skins))
+	for i, skin := range gltfModel.Skins {
+		boneBases[i] = len(skeleton.Bones)
+		for j, node := range skin.Joints {
+			skeleton.Bones = append(skeleton.Bones, animation.Bone{
+				Joint:       jointOf[node],
+				InverseBind: skin.InverseBindMatrices[j],
+			})
+		}
+	}
+
+	l.data.Skeleton = skeleton
+
+	for _, a := range gltfModel.Animations {
+		l.data.Clips = append(l.data.Clips, convertGLTFAnimation(a, jointOf))
+	}
+
+	return boneBases
+}
+
+// convertGLTFAnimation converts the channels of a, merging the ones of the same node.
+// Cubic splines are interpolated linearly between their values. Morph target weights are
+// not supported.
+func convertGLTFAnimation(a gltf.Animation, jointOf []int) *animation.Clip {
+	clip := &animation.Clip{Name: a.Name}
+	channels := make(map[int]int)
+
+	for _, ch := range a.Channels {
+		var components int
+		switch ch.Path {
+		case "translation", "scale":
+			components = 3
+		case "rotation":
+			components = 4
+		default:
+			continue
+		}
+
+		index, ok := channels[ch.Node]
+		if !ok {
+			index = len(clip.Channels)
+			channels[ch.Node] = index
+			clip.Channels = append(clip.Channels, animation.Channel{Joint: jointOf[ch.Node]})
+		}
+		channel := &clip.Channels[index]
+
+		interpolation := animation.Linear
+		if ch.Interpolation == "STEP" {
+			interpolation = animation.Step
+		}
+
+		// The value of each keyframe, skipping the tangents of cubic splines
+		stride, offset := components, 0
+		if ch.Interpolation == "CUBICSPLINE" {
+			stride, offset = components*3, components
+		}
+		value := func(key int) []float32 {
+			start := key*stride + offset
+			return ch.Values[start : start+components]
+		}
+
+		switch ch.Path {
+		case "translation", "scale":
+			track := animation.Vec3Track{Interpolation: interpolation, Times: ch.Times}
+			for k := range ch.Times {
+				v := value(k)
+				track.Values = append(track.Values, mgl32.Vec3{v[0], v[1], v[2]})
+			}
+			if ch.Path == "translation" {
+				channel.Translation = track
+			} else {
+				channel.Scale = track
+			}
+		case "rotation":
+			track := animation.QuatTrack{Interpolation: interpolation, Times: ch.Times}
+			for k := range ch.Times {
+				v := value(k)
+				track.Values = append(track.Values, mgl32.Quat{W: v[3], V: mgl32.Vec3{v[0], v[1], v[2]}}.Normalize())
+			}
+			channel.Rotation = track
+		}
+
+		if n := len(ch.Times); n > 0 {
+			clip.Duration = max(clip.Duration, ch.Times[n-1])
+		}
+	}
+
+	return clip
+}
+
+// convertGLTFMaterial adds a PBR material, loading its textures, and returns its index.
+// The Phong colors are approximated, so the shaders written for the other formats still
+// light it.
+func (l *loader) convertGLTFMaterial(gltfModel *gltf.Model, gltfMaterial gltf.Material) (int, error) {
+	mat := material.New(gltfMaterial.Name)
+
+	baseColor := gltfMaterial.BaseColor.Vec3()
+	mat.Ambient = baseColor
+	mat.Diffuse = baseColor
+	mat.Opacity = gltfMaterial.BaseColor.W()
+	mat.Emissive = gltfMaterial.Emissive
+	mat.Metallic = gltfMaterial.Metallic
+	mat.Roughness = gltfMaterial.Roughness
+
+	// Dielectrics reflect 4% of the light, metals their base color
+	dielectric := mgl32.Vec3{0.04, 0.04, 0.04}
+	mat.Specular = dielectric.Add(baseColor.Sub(dielectric).Mul(mat.Metallic))
+
+	// The exponent whose Blinn-Phong highlight is as wide as the roughness
+	alpha := mat.Roughness * mat.Roughness
+	mat.Shininess = mgl32.Clamp(2.0/max(alpha*alpha, 1e-4)-2.0, 1.0, 256.0)
+
+	index := l.addMaterial(mat)
+
+	textures := []struct {
+		ref   *gltf.TextureRef
+		xtype material.TextureType
+	}{
+		{gltfMaterial.BaseColorTexture, material.DiffuseMap},
+		{gltfMaterial.MetallicRoughnessTexture, material.MetallicRoughnessMap},
+		{gltfMaterial.NormalTexture, material.NormalMap},
+		{gltfMaterial.OcclusionTexture, material.OcclusionMap},
+		{gltfMaterial.EmissiveTexture, material.EmissiveMap},
+	}
+
+	for _, t := range textures {
+		if t.ref == nil {
+			continue
+		}
+
+		tex, err := l.loadGLTFTexture(gltfModel, t.ref, t.xtype)
+		if err != nil {
+			return 0, err
+		}
+		l.data.Materials[index].Textures[t.xtype] = tex
+	}
+
+	return index, nil
+}
+
+// loadGLTFTexture decodes the image of ref, once per sampler for the whole model, and
+// returns its index.
+func (l *loader) loadGLTFTexture(gltfModel *gltf.Model, ref *gltf.TextureRef, xtype material.TextureType) (int, error) {
+	opts := l.textureOptions(xtype)
+	// The first row of glTF images is at the texture coordinate v = 0
+	opts.FlipY = false
+	opts.WrapS = ref.Sampler.WrapS
+	opts.WrapT = ref.Sampler.WrapT
+	if ref.Sampler.MagFilter != 0 {
+		opts.MagFilter = ref.Sampler.MagFilter
+	}
+	if ref.Sampler.MinFilter != 0 {
+		opts.MinFilter = ref.Sampler.MinFilter
+	}
+
+	img := gltfModel.Images[ref.Image]
+	name := fmt.Sprintf("gltf image %d %q", ref.Image, img.Name)
+
+	// The same image can be sampled differently by the materials
+	key := fmt.Sprintf("gltf image %d %v %t", ref.Image, ref.Sampler, opts.SRGB)
+
+	return l.addTexture(key, name, opts, func() (*image.RGBA, error) {
+		rgba, err := imageutil.Decode(img.Data, opts.FlipY)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		return rgba, nil
+	})
+}
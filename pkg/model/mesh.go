@@ -1,118 +1,83 @@
 package model
 
 import (
-	"fmt"
-	"strconv"
 	"unsafe"
 
 	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/igoramorim/gopengl/pkg/material"
 	"github.com/igoramorim/gopengl/pkg/shader"
+	"github.com/igoramorim/gopengl/pkg/vertex"
 )
 
-func NewMesh(vertices []Vertex, indices []uint32, textures []Texture) Mesh {
-	mesh := Mesh{
-		Vertices: vertices,
-		Indices:  indices,
-		Textures: textures,
+// NewMesh returns a mesh of data drawn with mat. It makes no GL calls, see Upload.
+func NewMesh(data MeshData, mat *material.Material) *Mesh {
+	return &Mesh{
+		MeshData: data,
+		Material: mat,
 	}
-
-	mesh.setup()
-
-	return mesh
 }
 
 type Mesh struct {
-	Vertices []Vertex
-	Indices  []uint32
-	Textures []Texture
+	MeshData
+	// Material is bound to the "material" struct uniform of the shader, if any
+	Material *material.Material
 	vao      uint32
 	vbo      uint32
 	ebo      uint32
 }
 
-func (m *Mesh) Draw(shader *shader.Shader) {
-	diffuseNr := 1
-	specularNr := 1
-	normalNr := 1
-	heightNr := 1
-
-	for i, tex := range m.Textures {
-		var number string
-		name := tex.xtype
-
-		switch name {
-		case texDiffuse:
-			number = strconv.Itoa(diffuseNr)
-			diffuseNr++
-		case texSpecular:
-			number = strconv.Itoa(specularNr)
-			specularNr++
-		case texNormal:
-			number = strconv.Itoa(normalNr)
-			normalNr++
-		case texHeight:
-			number = strconv.Itoa(heightNr)
-			heightNr++
-		}
+// Upload creates the buffers of the mesh, unless it is uploaded already. It must be
+// called before Draw.
+func (m *Mesh) Upload() {
+	if m.Uploaded() {
+		return
+	}
+	m.setup()
+}
 
-		gl.ActiveTexture(gl.TEXTURE0 + uint32(i))
+// Uploaded reports whether the buffers of the mesh exist.
+func (m *Mesh) Uploaded() bool {
+	return m.vao != 0
+}
 
-		uniform := fmt.Sprintf("%s%s", name, number)
-		// fmt.Printf("uniform name: %+v\n", uniform)
-		shader.SetInt(uniform, int32(i))
-		gl.BindTexture(gl.TEXTURE_2D, tex.id)
+func (m *Mesh) Draw(shader *shader.Shader) {
+	if m.Material != nil {
+		m.Material.Bind(shader, "material", 0)
 	}
 
 	gl.BindVertexArray(m.vao)
 	gl.DrawElements(gl.TRIANGLES, int32(len(m.Indices)), gl.UNSIGNED_INT, nil)
 	gl.BindVertexArray(0)
+}
 
-	gl.ActiveTexture(gl.TEXTURE0)
+// Delete releases the buffers of the mesh, which can be uploaded again. The material is
+// kept.
+func (m *Mesh) Delete() {
+	if !m.Uploaded() {
+		return
+	}
+	gl.DeleteVertexArrays(1, &m.vao)
+	gl.DeleteBuffers(1, &m.vbo)
+	gl.DeleteBuffers(1, &m.ebo)
+	m.vao, m.vbo, m.ebo = 0, 0, 0
 }
 
-func (m *Mesh) setup() {
-	var dummy Vertex
-	vertexSize := int(unsafe.Sizeof(dummy))
-	vertexSize32 := int32(vertexSize)
+// vertexLayout is the layout of Vertex, a mistake in its tags fails at start up.
+var vertexLayout = vertex.MustOf[Vertex]()
 
+func (m *Mesh) setup() {
 	gl.GenVertexArrays(1, &m.vao)
 	gl.GenBuffers(1, &m.vbo)
 	gl.GenBuffers(1, &m.ebo)
 
 	gl.BindVertexArray(m.vao)
 	gl.BindBuffer(gl.ARRAY_BUFFER, m.vbo)
-	gl.BufferData(gl.ARRAY_BUFFER, len(m.Vertices)*vertexSize, gl.Ptr(m.Vertices), gl.STATIC_DRAW)
+	gl.BufferData(gl.ARRAY_BUFFER, len(m.Vertices)*int(vertexLayout.Stride), gl.Ptr(m.Vertices), gl.STATIC_DRAW)
 
 	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, m.ebo)
 	gl.BufferData(gl.ELEMENT_ARRAY_BUFFER, len(m.Indices)*int(unsafe.Sizeof(m.ebo)), gl.Ptr(m.Indices), gl.STATIC_DRAW)
 
-	// Vertex Positions
-	gl.EnableVertexAttribArray(0)
-	gl.VertexAttribPointer(0, 3, gl.FLOAT, false, vertexSize32, nil)
-
-	// Vertex Normals
-	gl.EnableVertexAttribArray(1)
-	gl.VertexAttribPointerWithOffset(1, 3, gl.FLOAT, false, vertexSize32, unsafe.Offsetof(dummy.Normal))
-
-	// Vertex Texture Coords
-	gl.EnableVertexAttribArray(2)
-	gl.VertexAttribPointerWithOffset(2, 2, gl.FLOAT, false, vertexSize32, unsafe.Offsetof(dummy.TexCoords))
-
-	// Vertex Tangent
-	gl.EnableVertexAttribArray(3)
-	gl.VertexAttribPointerWithOffset(3, 3, gl.FLOAT, false, vertexSize32, unsafe.Offsetof(dummy.Tangent))
-
-	// Vertex Bitangent
-	gl.EnableVertexAttribArray(4)
-	gl.VertexAttribPointerWithOffset(4, 3, gl.FLOAT, false, vertexSize32, unsafe.Offsetof(dummy.Bitangent))
-
-	// Vertex BoneIDs
-	gl.EnableVertexAttribArray(5)
-	gl.VertexAttribPointerWithOffset(5, 4, gl.INT, false, vertexSize32, unsafe.Offsetof(dummy.BoneIDs))
-
-	// Vertex Weights
-	gl.EnableVertexAttribArray(6)
-	gl.VertexAttribPointerWithOffset(6, 4, gl.FLOAT, false, vertexSize32, unsafe.Offsetof(dummy.Weights))
+	vertexLayout.Apply()
 
 	gl.BindVertexArray(0)
 }
@@ -0,0 +1,120 @@
+package model
+
+import (
+	"io/fs"
+	"maps"
+	"os"
+	"slices"
+
+	"github.com/igoramorim/gopengl/pkg/material"
+	"github.com/igoramorim/gopengl/pkg/obj"
+)
+
+// NewOBJ loads the Wavefront OBJ model at path, its MTL materials and their textures
+// from the working directory. Unlike New it does not need the assimp library.
+func NewOBJ(path string) (*Model, error) {
+	return NewOBJFS(os.DirFS("."), path)
+}
+
+// NewOBJFS loads the Wavefront OBJ model at path, its MTL materials and their textures
+// from fsys, and uploads it. Unlike NewFS it does not need the assimp library.
+func NewOBJFS(fsys fs.FS, path string) (*Model, error) {
+	data, err := LoadOBJ(fsys, path)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.Upload()
+}
+
+// LoadOBJ loads the Wavefront OBJ model at path, its MTL materials and their textures
+// from fsys without uploading it.
+func LoadOBJ(fsys fs.FS, path string) (*ModelData, error) {
+	l := newLoader(fsys, path)
+	if err := l.loadOBJ(path); err != nil {
+		return nil, err
+	}
+
+	return l.data, nil
+}
+
+func (l *loader) loadOBJ(file string) error {
+	objModel, err := obj.Load(l.fsys, file)
+	if err != nil {
+		return err
+	}
+
+	// By name, so the indices of the materials and textures are the same on every load
+	materials := make(map[string]int)
+	for _, name := range slices.Sorted(maps.Keys(objModel.Materials)) {
+		mat, err := l.convertOBJMaterial(objModel.Materials[name])
+		if err != nil {
+			return err
+		}
+		materials[name] = mat
+	}
+
+	for _, objMesh := range objModel.Meshes {
+		mat, ok := materials[objMesh.Material]
+		if !ok {
+			// No usemtl, or a material missing from the libraries
+			mat = l.addMaterial(material.New(objMesh.Material))
+			materials[objMesh.Material] = mat
+		}
+
+		vertices := make([]Vertex, len(objMesh.Vertices))
+		for i, v := range objMesh.Vertices {
+			vertices[i] = Vertex{
+				Position:  v.Position,
+				Normal:    v.Normal,
+				TexCoords: v.TexCoords,
+				Tangent:   v.Tangent,
+				Bitangent: v.Bitangent,
+			}
+		}
+
+		l.addMesh(objMesh.Name, vertices, objMesh.Indices, mat)
+	}
+
+	return nil
+}
+
+// convertOBJMaterial adds an MTL material, loading its textures, and returns its index.
+func (l *loader) convertOBJMaterial(objMaterial *obj.Material) (int, error) {
+	mat := material.New(objMaterial.Name)
+	mat.Ambient = objMaterial.Ambient
+	mat.Diffuse = objMaterial.Diffuse
+	mat.Specular = objMaterial.Specular
+	mat.Emissive = objMaterial.Emissive
+	mat.Opacity = objMaterial.Opacity
+	// Without Ns the whole surface would be lit as a highlight
+	if objMaterial.Shininess > 0 {
+		mat.Shininess = objMaterial.Shininess
+	}
+	index := l.addMaterial(mat)
+
+	maps := []struct {
+		path  string
+		xtype material.TextureType
+	}{
+		{objMaterial.DiffuseMap, material.DiffuseMap},
+		{objMaterial.SpecularMap, material.SpecularMap},
+		{objMaterial.NormalMap, material.NormalMap},
+		{objMaterial.HeightMap, material.HeightMap},
+		{objMaterial.EmissiveMap, material.EmissiveMap},
+	}
+
+	for _, t := range maps {
+		if t.path == "" {
+			continue
+		}
+
+		tex, err := l.loadTexture(t.path, t.xtype)
+		if err != nil {
+			return 0, err
+		}
+		l.data.Materials[index].Textures[t.xtype] = tex
+	}
+
+	return index, nil
+}
@@ -2,18 +2,52 @@ package model
 
 import "github.com/go-gl/mathgl/mgl32"
 
-const (
-	sizefloat32      = 4
-	sizeInt          = 4
-	maxBoneInfluence = 4
-)
+const maxBoneInfluence = 4
 
+// Vertex is a vertex of a mesh. The tags are the locations of its attributes in the
+// shaders, see vertex.Of.
 type Vertex struct {
-	Position  mgl32.Vec3
-	Normal    mgl32.Vec3
-	TexCoords mgl32.Vec2
-	Tangent   mgl32.Vec3
-	Bitangent mgl32.Vec3
-	BoneIDs   [maxBoneInfluence]int
-	Weights   [maxBoneInfluence]float32
+	Position  mgl32.Vec3 `vertex:"0"`
+	Normal    mgl32.Vec3 `vertex:"1"`
+	TexCoords mgl32.Vec2 `vertex:"2"`
+	Tangent   mgl32.Vec3 `vertex:"3"`
+	Bitangent mgl32.Vec3 `vertex:"4"`
+	// BoneIDs are the indices in Skeleton.Bones of the bones moving the vertex, by Weights.
+	// int32 as the shaders read them as an ivec4.
+	BoneIDs [maxBoneInfluence]int32   `vertex:"5"`
+	Weights [maxBoneInfluence]float32 `vertex:"6"`
+	// TexCoords1 is a second set of texture coordinates, e.g. for the occlusion maps of
+	// glTF models. Zero for the other formats.
+	TexCoords1 mgl32.Vec2 `vertex:"7"`
+}
+
+// addBoneWeight binds the vertex to bone, replacing the bone with the smallest weight when
+// it already has maxBoneInfluence and that one is smaller.
+func (v *Vertex) addBoneWeight(bone int, weight float32) {
+	smallest := 0
+	for i := range v.Weights {
+		if v.Weights[i] < v.Weights[smallest] {
+			smallest = i
+		}
+	}
+
+	if weight > v.Weights[smallest] {
+		v.BoneIDs[smallest] = int32(bone)
+		v.Weights[smallest] = weight
+	}
+}
+
+// normalizeWeights makes the weights of the vertex add up to 1, as the ones dropped by
+// addBoneWeight are missing.
+func (v *Vertex) normalizeWeights() {
+	var sum float32
+	for _, w := range v.Weights {
+		sum += w
+	}
+	if sum == 0 {
+		return
+	}
+	for i := range v.Weights {
+		v.Weights[i] /= sum
+	}
 }
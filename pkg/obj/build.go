@@ -0,0 +1,104 @@
+package obj
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/igoramorim/gopengl/pkg/tangent"
+)
+
+// vertexKey identifies the vertices shared by the triangles of a mesh.
+type vertexKey struct {
+	corner
+	// smoothing is the smoothing group of the generated normal, 0 when the corner has one
+	smoothing int
+	// triangle is the triangle of a generated flat normal, -1 otherwise
+	triangle int
+}
+
+// smoothKey identifies the generated smooth normal of a position in a smoothing group.
+type smoothKey struct {
+	position  int
+	smoothing int
+}
+
+// build triangulates the faces of g into the vertices and indices of a mesh.
+func (p *parser) build(g *group) Mesh {
+	mesh := Mesh{Name: g.name, Material: g.material}
+
+	vertices := make(map[vertexKey]uint32)
+	var keys []vertexKey
+	smooth := make(map[smoothKey]mgl32.Vec3)
+	var faceNormals []mgl32.Vec3
+
+	for _, f := range g.faces {
+		// Fan triangulation around the first corner
+		for i := 1; i+1 < len(f.corners); i++ {
+			triangle := len(faceNormals)
+			corners := [3]corner{f.corners[0], f.corners[i], f.corners[i+1]}
+
+			// Not normalized, so bigger faces weigh more in the smooth normals
+			p0, p1, p2 := p.positions[corners[0].position], p.positions[corners[1].position], p.positions[corners[2].position]
+			normal := p1.Sub(p0).Cross(p2.Sub(p0))
+			faceNormals = append(faceNormals, normal)
+
+			for _, c := range corners {
+				key := vertexKey{corner: c, triangle: -1}
+				if c.normal < 0 {
+					if f.smoothing == 0 {
+						key.triangle = triangle
+					} else {
+						key.smoothing = f.smoothing
+						sk := smoothKey{c.position, f.smoothing}
+						smooth[sk] = smooth[sk].Add(normal)
+					}
+				}
+
+				index, ok := vertices[key]
+				if !ok {
+					index = uint32(len(keys))
+					vertices[key] = index
+					keys = append(keys, key)
+				}
+				mesh.Indices = append(mesh.Indices, index)
+			}
+		}
+	}
+
+	mesh.Vertices = make([]Vertex, len(keys))
+	for i, key := range keys {
+		v := &mesh.Vertices[i]
+		v.Position = p.positions[key.position]
+
+		if key.texCoords >= 0 {
+			v.TexCoords = p.texCoords[key.texCoords]
+		}
+
+		switch {
+		case key.normal >= 0:
+			v.Normal = p.normals[key.normal]
+		case key.triangle >= 0:
+			v.Normal = tangent.Normalize(faceNormals[key.triangle])
+		default:
+			v.Normal = tangent.Normalize(smooth[smoothKey{key.position, key.smoothing}])
+		}
+	}
+
+	generateTangents(mesh.Vertices, mesh.Indices)
+
+	return mesh
+}
+
+// generateTangents sets the tangent and bitangent of the vertices, for normal mapping.
+func generateTangents(vertices []Vertex, indices []uint32) {
+	positions := make([]mgl32.Vec3, len(vertices))
+	normals := make([]mgl32.Vec3, len(vertices))
+	texCoords := make([]mgl32.Vec2, len(vertices))
+	for i, v := range vertices {
+		positions[i], normals[i], texCoords[i] = v.Position, v.Normal, v.TexCoords
+	}
+
+	tangents, bitangents := tangent.Generate(positions, normals, texCoords, indices)
+	for i := range vertices {
+		vertices[i].Tangent = tangents[i]
+		vertices[i].Bitangent = bitangents[i]
+	}
+}
@@ -0,0 +1,145 @@
+package obj
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Material is a material of an MTL file. The maps are the paths of the textures,
+// relative to the MTL file, or empty when the material has none.
+type Material struct {
+	Name string
+
+	Ambient  mgl32.Vec3 // Ka
+	Diffuse  mgl32.Vec3 // Kd
+	Specular mgl32.Vec3 // Ks
+	Emissive mgl32.Vec3 // Ke
+
+	Shininess float32 // Ns
+	Opacity   float32 // d, or 1 - Tr
+
+	DiffuseMap  string // map_Kd
+	SpecularMap string // map_Ks
+	EmissiveMap string // map_Ke
+	// NormalMap is read from norm, and from map_Bump and bump, which most exporters
+	// write for normal maps even though it was meant for height maps.
+	NormalMap string
+	HeightMap string // disp
+}
+
+// newMaterial returns the MTL defaults.
+func newMaterial(name string) *Material {
+	return &Material{
+		Name:     name,
+		Ambient:  mgl32.Vec3{0.2, 0.2, 0.2},
+		Diffuse:  mgl32.Vec3{0.8, 0.8, 0.8},
+		Specular: mgl32.Vec3{1.0, 1.0, 1.0},
+		Opacity:  1.0,
+	}
+}
+
+// ParseMTL parses the materials of an MTL file, by name.
+func ParseMTL(r io.Reader) (map[string]*Material, error) {
+	materials := make(map[string]*Material)
+	var current *Material
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if i := strings.IndexByte(text, '#'); i >= 0 {
+			text = text[:i]
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+		keyword, args := fields[0], fields[1:]
+
+		if keyword == "newmtl" {
+			current = newMaterial(strings.Join(args, " "))
+			materials[current.Name] = current
+			continue
+		}
+
+		if current == nil {
+			return nil, fmt.Errorf("mtl: line %d: %s before newmtl", line, keyword)
+		}
+
+		if err := current.parse(keyword, args); err != nil {
+			return nil, fmt.Errorf("mtl: line %d: %s: %w", line, keyword, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("mtl: %w", err)
+	}
+
+	return materials, nil
+}
+
+func (m *Material) parse(keyword string, args []string) error {
+	color := func(c *mgl32.Vec3) error {
+		v, err := parseFloats(args, 3)
+		if err != nil {
+			return err
+		}
+		*c = mgl32.Vec3{v[0], v[1], v[2]}
+		return nil
+	}
+
+	scalar := func(f *float32) error {
+		v, err := parseFloats(args, 1)
+		if err != nil {
+			return err
+		}
+		*f = v[0]
+		return nil
+	}
+
+	textureMap := func(path *string) error {
+		// The options, like -bm 1.0 or -clamp on, come before the file name
+		if len(args) == 0 {
+			return fmt.Errorf("missing file name")
+		}
+		*path = args[len(args)-1]
+		return nil
+	}
+
+	switch keyword {
+	case "Ka":
+		return color(&m.Ambient)
+	case "Kd":
+		return color(&m.Diffuse)
+	case "Ks":
+		return color(&m.Specular)
+	case "Ke":
+		return color(&m.Emissive)
+	case "Ns":
+		return scalar(&m.Shininess)
+	case "d":
+		return scalar(&m.Opacity)
+	case "Tr":
+		var transparency float32
+		if err := scalar(&transparency); err != nil {
+			return err
+		}
+		m.Opacity = 1 - transparency
+	case "map_Kd":
+		return textureMap(&m.DiffuseMap)
+	case "map_Ks":
+		return textureMap(&m.SpecularMap)
+	case "map_Ke":
+		return textureMap(&m.EmissiveMap)
+	case "map_Bump", "map_bump", "bump", "norm":
+		return textureMap(&m.NormalMap)
+	case "disp":
+		return textureMap(&m.HeightMap)
+	}
+
+	// Other statements, like the illumination model or the refraction index, are ignored
+	return nil
+}
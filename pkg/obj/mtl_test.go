@@ -0,0 +1,83 @@
+package obj
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestParseMTL(t *testing.T) {
+	const src = `
+# Two materials, the second with the defaults
+newmtl Painted Metal
+Ka 0.1 0.1 0.1
+Kd 0.5 0.25 0
+Ks 1 1 1
+Ke 0 0 0.5
+Ns 64
+Tr 0.25
+illum 2
+map_Kd -bm 1.0 -clamp on textures/diffuse.png
+map_Ks specular.png
+map_Bump normal.png
+disp height.png
+
+newmtl plain
+`
+	materials, err := ParseMTL(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]Material{
+		"Painted Metal": {
+			Name:        "Painted Metal",
+			Ambient:     mgl32.Vec3{0.1, 0.1, 0.1},
+			Diffuse:     mgl32.Vec3{0.5, 0.25, 0},
+			Specular:    mgl32.Vec3{1, 1, 1},
+			Emissive:    mgl32.Vec3{0, 0, 0.5},
+			Shininess:   64,
+			Opacity:     0.75,
+			DiffuseMap:  "textures/diffuse.png",
+			SpecularMap: "specular.png",
+			NormalMap:   "normal.png",
+			HeightMap:   "height.png",
+		},
+		"plain": *newMaterial("plain"),
+	}
+
+	if len(materials) != len(want) {
+		t.Fatalf("got %d materials, want %d", len(materials), len(want))
+	}
+	for name, w := range want {
+		got, ok := materials[name]
+		if !ok {
+			t.Errorf("material %q missing", name)
+			continue
+		}
+		if *got != w {
+			t.Errorf("material %q = %+v, want %+v", name, *got, w)
+		}
+	}
+}
+
+func TestParseMTLErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{name: "before newmtl", src: "Kd 1 1 1"},
+		{name: "short color", src: "newmtl a\nKd 1 1"},
+		{name: "not a number", src: "newmtl a\nNs shiny"},
+		{name: "map without file", src: "newmtl a\nmap_Kd"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseMTL(strings.NewReader(tt.src)); err == nil {
+				t.Errorf("ParseMTL(%q) succeeded", tt.src)
+			}
+		})
+	}
+}
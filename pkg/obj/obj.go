@@ -0,0 +1,310 @@
+// Package obj parses Wavefront OBJ models and their MTL materials in pure Go. It only
+// produces vertex data, so it does not need a GL context nor a native library.
+package obj
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+type Vertex struct {
+	Position  mgl32.Vec3
+	Normal    mgl32.Vec3
+	TexCoords mgl32.Vec2
+	Tangent   mgl32.Vec3
+	Bitangent mgl32.Vec3
+}
+
+// Mesh is a part of the model drawn with a single material.
+type Mesh struct {
+	// Name is the name of the last object (o) or group (g) statement before the faces.
+	Name string
+	// Material is the name of the material in use (usemtl), empty when there is none.
+	Material string
+	Vertices []Vertex
+	// Indices are the vertices of the triangles, three by three.
+	Indices []uint32
+}
+
+type Model struct {
+	Meshes []Mesh
+	// Materials are the materials of the MTL files referenced by the model (mtllib),
+	// by name.
+	Materials map[string]*Material
+	// MaterialLibs are the MTL files referenced by the model, as written in it.
+	MaterialLibs []string
+}
+
+// Load parses the OBJ file from fsys and the MTL files it references, relative to it.
+func Load(fsys fs.FS, file string) (*Model, error) {
+	f, err := fsys.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("obj: %w", err)
+	}
+	defer f.Close()
+
+	model, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+
+	for _, lib := range model.MaterialLibs {
+		libPath := path.Join(path.Dir(file), lib)
+
+		f, err := fsys.Open(libPath)
+		if err != nil {
+			return nil, fmt.Errorf("obj: %w", err)
+		}
+
+		materials, err := ParseMTL(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", libPath, err)
+		}
+
+		for name, m := range materials {
+			model.Materials[name] = m
+		}
+	}
+
+	return model, nil
+}
+
+// corner is a vertex of a face as the indices of its attributes, -1 when missing.
+type corner struct {
+	position  int
+	texCoords int
+	normal    int
+}
+
+// face is a polygon of the model.
+type face struct {
+	corners []corner
+	// smoothing is the smoothing group of the face, 0 when it is not smoothed.
+	smoothing int
+}
+
+// group are the faces of a mesh, before they are turned into vertices.
+type group struct {
+	name     string
+	material string
+	faces    []face
+}
+
+// parser holds the state of Parse.
+type parser struct {
+	positions []mgl32.Vec3
+	texCoords []mgl32.Vec2
+	normals   []mgl32.Vec3
+
+	groups    []*group
+	current   *group
+	smoothing int
+	libs      []string
+}
+
+// Parse parses an OBJ model. The material libraries are not read, see Load.
+//
+// Polygons are triangulated as fans, so they must be convex. Faces without normals get
+// smooth normals averaged over the faces of their smoothing group and flat normals after
+// "s off". Files without smoothing groups are smoothed as a single group.
+func Parse(r io.Reader) (*Model, error) {
+	p := &parser{
+		// Smoothed until told otherwise, as most exporters only write normals
+		smoothing: 1,
+	}
+	p.current = &group{}
+	p.groups = append(p.groups, p.current)
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		if err := p.parseLine(scanner.Text()); err != nil {
+			return nil, fmt.Errorf("obj: line %d: %w", line, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("obj: %w", err)
+	}
+
+	model := &Model{
+		Materials:    make(map[string]*Material),
+		MaterialLibs: p.libs,
+	}
+
+	for _, g := range p.groups {
+		if len(g.faces) == 0 {
+			continue
+		}
+		model.Meshes = append(model.Meshes, p.build(g))
+	}
+
+	return model, nil
+}
+
+func (p *parser) parseLine(line string) error {
+	if i := strings.IndexByte(line, '#'); i >= 0 {
+		line = line[:i]
+	}
+
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return nil
+	}
+	keyword, args := fields[0], fields[1:]
+
+	switch keyword {
+	case "v":
+		v, err := parseFloats(args, 3)
+		if err != nil {
+			return err
+		}
+		p.positions = append(p.positions, mgl32.Vec3{v[0], v[1], v[2]})
+
+	case "vt":
+		v, err := parseFloats(args, 2)
+		if err != nil {
+			return err
+		}
+		p.texCoords = append(p.texCoords, mgl32.Vec2{v[0], v[1]})
+
+	case "vn":
+		v, err := parseFloats(args, 3)
+		if err != nil {
+			return err
+		}
+		p.normals = append(p.normals, mgl32.Vec3{v[0], v[1], v[2]})
+
+	case "f":
+		return p.parseFace(args)
+
+	case "o", "g":
+		p.startGroup(strings.Join(args, " "), p.current.material)
+
+	case "usemtl":
+		p.startGroup(p.current.name, strings.Join(args, " "))
+
+	case "mtllib":
+		// The file names can not have spaces, as they are separated by them
+		p.libs = append(p.libs, args...)
+
+	case "s":
+		if len(args) != 1 {
+			return fmt.Errorf("s: expected 1 argument, got %d", len(args))
+		}
+		if args[0] == "off" {
+			p.smoothing = 0
+			return nil
+		}
+		group, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("s: %w", err)
+		}
+		p.smoothing = group
+	}
+
+	// Other statements, like lines, points and curves, are not drawn
+	return nil
+}
+
+// startGroup starts a new mesh, unless the current one has no faces yet.
+func (p *parser) startGroup(name, material string) {
+	if len(p.current.faces) > 0 {
+		p.current = &group{}
+		p.groups = append(p.groups, p.current)
+	}
+	p.current.name = name
+	p.current.material = material
+}
+
+func (p *parser) parseFace(args []string) error {
+	if len(args) < 3 {
+		return fmt.Errorf("f: a face needs 3 vertices at least, got %d", len(args))
+	}
+
+	f := face{smoothing: p.smoothing}
+	for _, arg := range args {
+		c, err := p.parseCorner(arg)
+		if err != nil {
+			return fmt.Errorf("f: %q: %w", arg, err)
+		}
+		f.corners = append(f.corners, c)
+	}
+
+	p.current.faces = append(p.current.faces, f)
+	return nil
+}
+
+// parseCorner parses v, v/vt, v//vn or v/vt/vn.
+func (p *parser) parseCorner(arg string) (corner, error) {
+	c := corner{texCoords: -1, normal: -1}
+	parts := strings.Split(arg, "/")
+	if len(parts) > 3 {
+		return c, fmt.Errorf("too many indices")
+	}
+
+	var err error
+	if c.position, err = resolveIndex(parts[0], len(p.positions)); err != nil {
+		return c, err
+	}
+	if len(parts) > 1 && parts[1] != "" {
+		if c.texCoords, err = resolveIndex(parts[1], len(p.texCoords)); err != nil {
+			return c, err
+		}
+	}
+	if len(parts) > 2 && parts[2] != "" {
+		if c.normal, err = resolveIndex(parts[2], len(p.normals)); err != nil {
+			return c, err
+		}
+	}
+
+	return c, nil
+}
+
+// resolveIndex converts a 1-based index, or a negative one relative to the end of the
+// count elements defined so far, to a 0-based index.
+func resolveIndex(s string, count int) (int, error) {
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, err
+	}
+
+	switch {
+	case i > 0:
+		i--
+	case i < 0:
+		i += count
+	default:
+		return 0, fmt.Errorf("index 0 is not valid, they start at 1")
+	}
+
+	if i < 0 || i >= count {
+		return 0, fmt.Errorf("index %s out of range, %d elements are defined", s, count)
+	}
+
+	return i, nil
+}
+
+func parseFloats(args []string, n int) ([]float32, error) {
+	// Extra values, like the w of positions or the colors some exporters add, are ignored
+	if len(args) < n {
+		return nil, fmt.Errorf("expected %d numbers, got %d", n, len(args))
+	}
+
+	floats := make([]float32, n)
+	for i := range floats {
+		f, err := strconv.ParseFloat(args[i], 32)
+		if err != nil {
+			return nil, err
+		}
+		floats[i] = float32(f)
+	}
+
+	return floats, nil
+}
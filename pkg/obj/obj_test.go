@@ -0,0 +1,306 @@
+package obj
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func parse(t *testing.T, src string) *Model {
+	t.Helper()
+
+	model, err := Parse(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return model
+}
+
+// onlyMesh returns the single mesh of model.
+func onlyMesh(t *testing.T, model *Model) Mesh {
+	t.Helper()
+
+	if len(model.Meshes) != 1 {
+		t.Fatalf("got %d meshes, want 1", len(model.Meshes))
+	}
+	return model.Meshes[0]
+}
+
+// trianglePositions returns the positions of the corners of the triangles of mesh.
+func trianglePositions(mesh Mesh) [][3]mgl32.Vec3 {
+	var triangles [][3]mgl32.Vec3
+	for i := 0; i+2 < len(mesh.Indices); i += 3 {
+		triangles = append(triangles, [3]mgl32.Vec3{
+			mesh.Vertices[mesh.Indices[i]].Position,
+			mesh.Vertices[mesh.Indices[i+1]].Position,
+			mesh.Vertices[mesh.Indices[i+2]].Position,
+		})
+	}
+	return triangles
+}
+
+func TestParseTriangulation(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		// want are the triangles as the 1-based positions of their corners
+		want [][3]int
+	}{
+		{
+			name: "triangle",
+			src:  "f 1 2 3",
+			want: [][3]int{{1, 2, 3}},
+		},
+		{
+			name: "quad",
+			src:  "f 1 2 3 4",
+			want: [][3]int{{1, 2, 3}, {1, 3, 4}},
+		},
+		{
+			name: "pentagon",
+			src:  "f 1 2 3 4 5",
+			want: [][3]int{{1, 2, 3}, {1, 3, 4}, {1, 4, 5}},
+		},
+	}
+
+	// A convex pentagon in the plane z = 0
+	positions := []mgl32.Vec3{{0, 0, 0}, {2, 0, 0}, {3, 1, 0}, {1, 2, 0}, {-1, 1, 0}}
+	var header strings.Builder
+	for _, p := range positions {
+		fmt.Fprintf(&header, "v %g %g %g\n", p.X(), p.Y(), p.Z())
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mesh := onlyMesh(t, parse(t, header.String()+tt.src))
+
+			got := trianglePositions(mesh)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d triangles, want %d", len(got), len(tt.want))
+			}
+			for i, tri := range tt.want {
+				for c := range 3 {
+					if want := positions[tri[c]-1]; got[i][c] != want {
+						t.Errorf("triangle %d corner %d = %v, want %v", i, c, got[i][c], want)
+					}
+				}
+			}
+
+			// The corners are shared between the triangles of a face
+			if len(mesh.Vertices) != len(tt.want)+2 {
+				t.Errorf("got %d vertices, want %d", len(mesh.Vertices), len(tt.want)+2)
+			}
+		})
+	}
+}
+
+func TestParseNegativeIndices(t *testing.T) {
+	const src = `
+v 0 0 0
+v 1 0 0
+v 0 1 0
+vt 0 0
+vt 1 0
+vt 0 1
+vn 0 0 1
+f -3/-3/-1 -2/-2/-1 -1/-1/-1
+v 5 5 5
+f 1/1/1 2/2/1 -2/3/1
+`
+	model := parse(t, src)
+	mesh := onlyMesh(t, model)
+
+	triangles := trianglePositions(mesh)
+	if len(triangles) != 2 {
+		t.Fatalf("got %d triangles, want 2", len(triangles))
+	}
+
+	// -1 is the last position defined before the face, not in the whole file
+	want := [3]mgl32.Vec3{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}}
+	for i, tri := range triangles {
+		if tri != want {
+			t.Errorf("triangle %d = %v, want %v", i, tri, want)
+		}
+	}
+
+	for i, v := range mesh.Vertices {
+		if v.Normal != (mgl32.Vec3{0, 0, 1}) {
+			t.Errorf("vertex %d normal = %v, want the one of vn", i, v.Normal)
+		}
+	}
+	if got := mesh.Vertices[mesh.Indices[1]].TexCoords; got != (mgl32.Vec2{1, 0}) {
+		t.Errorf("texture coords of corner 1 = %v, want [1 0]", got)
+	}
+}
+
+func TestParseInvalidIndices(t *testing.T) {
+	tests := []struct {
+		name string
+		face string
+	}{
+		{name: "zero", face: "f 0 1 2"},
+		{name: "past the end", face: "f 1 2 4"},
+		{name: "negative past the start", face: "f -4 1 2"},
+		{name: "missing normal", face: "f 1//1 2//1 3//1"},
+		{name: "too few corners", face: "f 1 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := "v 0 0 0\nv 1 0 0\nv 0 1 0\n" + tt.face
+			if _, err := Parse(strings.NewReader(src)); err == nil {
+				t.Errorf("Parse(%q) succeeded", tt.face)
+			}
+		})
+	}
+}
+
+// bentTriangles are two triangles sharing the edge from v 2 to v 3, one facing +Z and the
+// other +X, without normals.
+const bentTriangles = `
+v 0 0 0
+v 1 0 0
+v 1 1 0
+v 1 0 -1
+`
+
+func TestParseSmoothingGroups(t *testing.T) {
+	front := mgl32.Vec3{0, 0, 1}
+	side := mgl32.Vec3{1, 0, 0}
+	shared := front.Add(side).Normalize()
+
+	tests := []struct {
+		name  string
+		faces string
+		// vertices is the number of vertices, as the corners of different normals are
+		// split
+		vertices int
+		// normals are the normals of the corners of the triangles
+		normals [2][3]mgl32.Vec3
+	}{
+		{
+			name:     "same group",
+			faces:    "s 1\nf 1 2 3\nf 2 4 3",
+			vertices: 4,
+			normals:  [2][3]mgl32.Vec3{{front, shared, shared}, {shared, side, shared}},
+		},
+		{
+			name:     "no group is smoothed",
+			faces:    "f 1 2 3\nf 2 4 3",
+			vertices: 4,
+			normals:  [2][3]mgl32.Vec3{{front, shared, shared}, {shared, side, shared}},
+		},
+		{
+			name:     "different groups",
+			faces:    "s 1\nf 1 2 3\ns 2\nf 2 4 3",
+			vertices: 6,
+			normals:  [2][3]mgl32.Vec3{{front, front, front}, {side, side, side}},
+		},
+		{
+			name:     "off",
+			faces:    "s off\nf 1 2 3\nf 2 4 3",
+			vertices: 6,
+			normals:  [2][3]mgl32.Vec3{{front, front, front}, {side, side, side}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mesh := onlyMesh(t, parse(t, bentTriangles+tt.faces))
+
+			if len(mesh.Vertices) != tt.vertices {
+				t.Errorf("got %d vertices, want %d", len(mesh.Vertices), tt.vertices)
+			}
+			if len(mesh.Indices) != 6 {
+				t.Fatalf("got %d indices, want 6", len(mesh.Indices))
+			}
+			for i, index := range mesh.Indices {
+				got := mesh.Vertices[index].Normal
+				if want := tt.normals[i/3][i%3]; !got.ApproxEqual(want) {
+					t.Errorf("normal of corner %d = %v, want %v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseGeneratedNormals(t *testing.T) {
+	// Counter-clockwise seen from +Z, like OpenGL front faces
+	mesh := onlyMesh(t, parse(t, "v 0 0 0\nv 2 0 0\nv 0 2 0\nvt 0 0\nvt 1 0\nvt 0 1\nf 1/1 2/2 3/3"))
+
+	for i, v := range mesh.Vertices {
+		if !v.Normal.ApproxEqual(mgl32.Vec3{0, 0, 1}) {
+			t.Errorf("vertex %d normal = %v, want [0 0 1]", i, v.Normal)
+		}
+		// The texture coords grow along +X and +Y
+		if !v.Tangent.ApproxEqual(mgl32.Vec3{1, 0, 0}) {
+			t.Errorf("vertex %d tangent = %v, want [1 0 0]", i, v.Tangent)
+		}
+		if !v.Bitangent.ApproxEqual(mgl32.Vec3{0, 1, 0}) {
+			t.Errorf("vertex %d bitangent = %v, want [0 1 0]", i, v.Bitangent)
+		}
+	}
+}
+
+func TestParseGroups(t *testing.T) {
+	const src = `
+mtllib a.mtl b.mtl
+v 0 0 0
+v 1 0 0
+v 0 1 0
+o body
+usemtl red
+f 1 2 3
+usemtl blue
+f 1 2 3
+g lid
+f 1 2 3
+`
+	model := parse(t, src)
+
+	want := []struct{ name, material string }{
+		{"body", "red"},
+		{"body", "blue"},
+		{"lid", "blue"},
+	}
+	if len(model.Meshes) != len(want) {
+		t.Fatalf("got %d meshes, want %d", len(model.Meshes), len(want))
+	}
+	for i, w := range want {
+		if m := model.Meshes[i]; m.Name != w.name || m.Material != w.material {
+			t.Errorf("mesh %d = %q with %q, want %q with %q", i, m.Name, m.Material, w.name, w.material)
+		}
+	}
+
+	if got := strings.Join(model.MaterialLibs, " "); got != "a.mtl b.mtl" {
+		t.Errorf("material libs = %q, want \"a.mtl b.mtl\"", got)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"models/tri/tri.obj": {Data: []byte("mtllib tri.mtl\nv 0 0 0\nv 1 0 0\nv 0 1 0\nusemtl paint\nf 1 2 3\n")},
+		"models/tri/tri.mtl": {Data: []byte("newmtl paint\nKd 1 0 0\nmap_Kd paint.png\n")},
+	}
+
+	model, err := Load(fsys, "models/tri/tri.obj")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m, ok := model.Materials["paint"]
+	if !ok {
+		t.Fatal("material paint not loaded")
+	}
+	if m.Diffuse != (mgl32.Vec3{1, 0, 0}) || m.DiffuseMap != "paint.png" {
+		t.Errorf("paint = %+v", m)
+	}
+
+	delete(fsys, "models/tri/tri.mtl")
+	if _, err := Load(fsys, "models/tri/tri.obj"); err == nil {
+		t.Error("Load without the material library succeeded")
+	}
+}
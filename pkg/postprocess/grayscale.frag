@@ -0,0 +1,14 @@
+#version 330 core
+
+in vec2 TexCoords;
+
+uniform sampler2D screen;
+
+out vec4 FragColor;
+
+void main() {
+	vec3 color = texture(screen, TexCoords).rgb;
+	// Weighted by how sensitive the human eye is to each channel
+	float average = 0.2126 * color.r + 0.7152 * color.g + 0.0722 * color.b;
+	FragColor = vec4(vec3(average), 1.0);
+}
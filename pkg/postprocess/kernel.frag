@@ -0,0 +1,23 @@
+#version 330 core
+
+in vec2 TexCoords;
+
+uniform sampler2D screen;
+// kernel weights the 3x3 pixels around the current one
+uniform mat3 kernel;
+
+out vec4 FragColor;
+
+void main() {
+	vec2 texel = 1.0 / vec2(textureSize(screen, 0));
+
+	vec3 color = vec3(0.0);
+	for (int x = -1; x <= 1; x++) {
+		for (int y = -1; y <= 1; y++) {
+			vec2 offset = vec2(float(x), float(y)) * texel;
+			color += texture(screen, TexCoords + offset).rgb * kernel[x + 1][1 - y];
+		}
+	}
+
+	FragColor = vec4(color, 1.0);
+}
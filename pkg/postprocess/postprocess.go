@@ -0,0 +1,269 @@
+// Package postprocess applies full-screen effects to a scene after it is drawn.
+package postprocess
+
+import (
+	"embed"
+	"fmt"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/igoramorim/gopengl/pkg/framebuffer"
+	"github.com/igoramorim/gopengl/pkg/shader"
+	"github.com/igoramorim/gopengl/pkg/vertex"
+)
+
+//go:embed *.vert *.frag
+var shaders embed.FS
+
+const floatSize = 4
+
+// Effect is a full-screen pass. The enabled effects are applied in the order they are declared.
+type Effect int
+
+const (
+	Inversion Effect = iota
+	Grayscale
+	Sharpen
+	Blur
+	EdgeDetection
+	numEffects
+)
+
+func (e Effect) String() string {
+	switch e {
+	case Inversion:
+		return "inversion"
+	case Grayscale:
+		return "grayscale"
+	case Sharpen:
+		return "sharpen"
+	case Blur:
+		return "blur"
+	case EdgeDetection:
+		return "edge_detection"
+	default:
+		return fmt.Sprintf("Effect(%d)", int(e))
+	}
+}
+
+// kernels of the effects that weight the 3x3 pixels around each pixel, as seen on the screen.
+var kernels = map[Effect]mgl32.Mat3{
+	Sharpen: mgl32.Mat3FromRows(
+		mgl32.Vec3{-1, -1, -1},
+		mgl32.Vec3{-1, 9, -1},
+		mgl32.Vec3{-1, -1, -1},
+	),
+	Blur: mgl32.Mat3FromRows(
+		mgl32.Vec3{1.0 / 16, 2.0 / 16, 1.0 / 16},
+		mgl32.Vec3{2.0 / 16, 4.0 / 16, 2.0 / 16},
+		mgl32.Vec3{1.0 / 16, 2.0 / 16, 1.0 / 16},
+	),
+	EdgeDetection: mgl32.Mat3FromRows(
+		mgl32.Vec3{1, 1, 1},
+		mgl32.Vec3{1, -8, 1},
+		mgl32.Vec3{1, 1, 1},
+	),
+}
+
+// New creates a chain of width x height pixels with every effect disabled.
+func New(width, height int) (*Chain, error) {
+	c := &Chain{}
+
+	var err error
+	c.scene, err = framebuffer.New(width, height, framebuffer.Color(0), framebuffer.DepthStencil())
+	if err != nil {
+		return nil, err
+	}
+
+	// The effects read from one buffer and write to the other
+	for i := range c.pingPong {
+		c.pingPong[i], err = framebuffer.New(width, height, framebuffer.Color(0))
+		if err != nil {
+			c.Delete()
+			return nil, err
+		}
+	}
+
+	for name, program := range map[string]**shader.Shader{
+		"copy.frag":      &c.copyShader,
+		"inversion.frag": &c.inversionShader,
+		"grayscale.frag": &c.grayscaleShader,
+		"kernel.frag":    &c.kernelShader,
+	} {
+		*program, err = shader.NewFS(shaders, "quad.vert", name)
+		if err != nil {
+			c.Delete()
+			return nil, err
+		}
+	}
+
+	c.setupQuad()
+
+	return c, nil
+}
+
+// Chain renders a scene into a framebuffer and draws it to the default framebuffer
+// through the enabled effects.
+type Chain struct {
+	scene    *framebuffer.Framebuffer
+	pingPong [2]*framebuffer.Framebuffer
+	enabled  [numEffects]bool
+
+	copyShader      *shader.Shader
+	inversionShader *shader.Shader
+	grayscaleShader *shader.Shader
+	kernelShader    *shader.Shader
+
+	quadVAO uint32
+	quadVBO uint32
+}
+
+func (c *Chain) setupQuad() {
+	// Two triangles covering the whole screen in normalized device coordinates
+	quad := []float32{
+		// x y     u v
+		-1.0, 1.0, 0.0, 1.0,
+		-1.0, -1.0, 0.0, 0.0,
+		1.0, -1.0, 1.0, 0.0,
+
+		-1.0, 1.0, 0.0, 1.0,
+		1.0, -1.0, 1.0, 0.0,
+		1.0, 1.0, 1.0, 1.0,
+	}
+
+	gl.GenVertexArrays(1, &c.quadVAO)
+	gl.GenBuffers(1, &c.quadVBO)
+
+	gl.BindVertexArray(c.quadVAO)
+	gl.BindBuffer(gl.ARRAY_BUFFER, c.quadVBO)
+	gl.BufferData(gl.ARRAY_BUFFER, len(quad)*floatSize, gl.Ptr(quad), gl.STATIC_DRAW)
+
+	// Position and texture coord attributes
+	vertex.Floats(2, 2).Apply()
+
+	gl.BindVertexArray(0)
+}
+
+func (c *Chain) Enabled(e Effect) bool {
+	return c.enabled[e]
+}
+
+func (c *Chain) SetEnabled(e Effect, enabled bool) {
+	c.enabled[e] = enabled
+}
+
+// Toggle enables the effect if it is disabled and the other way around.
+func (c *Chain) Toggle(e Effect) {
+	c.enabled[e] = !c.enabled[e]
+}
+
+// Begin makes the draw calls of the scene go to the chain. The scene still has to clear it.
+func (c *Chain) Begin() {
+	c.scene.Bind()
+}
+
+// End applies the enabled effects to what was drawn since Begin and draws the result
+// to the default framebuffer.
+func (c *Chain) End() {
+	// The quad covers the whole screen, so it is neither tested nor drawn in wireframe.
+	// The state of the scene is restored at the end
+	depthTest := gl.IsEnabled(gl.DEPTH_TEST)
+	stencilTest := gl.IsEnabled(gl.STENCIL_TEST)
+	var polygonMode [2]int32
+	gl.GetIntegerv(gl.POLYGON_MODE, &polygonMode[0])
+
+	gl.Disable(gl.DEPTH_TEST)
+	gl.Disable(gl.STENCIL_TEST)
+	gl.PolygonMode(gl.FRONT_AND_BACK, gl.FILL)
+	gl.BindVertexArray(c.quadVAO)
+	gl.ActiveTexture(gl.TEXTURE0)
+
+	source := c.scene
+	next := 0
+	for e := Effect(0); e < numEffects; e++ {
+		if !c.enabled[e] {
+			continue
+		}
+
+		target := c.pingPong[next]
+		target.Bind()
+		c.draw(e, source)
+
+		source = target
+		next = 1 - next
+	}
+
+	framebuffer.BindDefault()
+	gl.Viewport(0, 0, int32(c.scene.Width()), int32(c.scene.Height()))
+	c.copyShader.Use()
+	c.copyShader.SetInt("screen", 0)
+	gl.BindTexture(gl.TEXTURE_2D, source.Texture(gl.COLOR_ATTACHMENT0))
+	gl.DrawArrays(gl.TRIANGLES, 0, 6)
+
+	gl.BindVertexArray(0)
+
+	if depthTest {
+		gl.Enable(gl.DEPTH_TEST)
+	}
+	if stencilTest {
+		gl.Enable(gl.STENCIL_TEST)
+	}
+	gl.PolygonMode(gl.FRONT_AND_BACK, uint32(polygonMode[0]))
+}
+
+func (c *Chain) draw(e Effect, source *framebuffer.Framebuffer) {
+	var program *shader.Shader
+	switch e {
+	case Inversion:
+		program = c.inversionShader
+	case Grayscale:
+		program = c.grayscaleShader
+	default:
+		program = c.kernelShader
+	}
+
+	program.Use()
+	program.SetInt("screen", 0)
+	if kernel, ok := kernels[e]; ok {
+		program.SetMat3("kernel", kernel)
+	}
+
+	gl.BindTexture(gl.TEXTURE_2D, source.Texture(gl.COLOR_ATTACHMENT0))
+	gl.DrawArrays(gl.TRIANGLES, 0, 6)
+}
+
+// Resize resizes the framebuffers of the chain, e.g. when the window is resized.
+func (c *Chain) Resize(width, height int) error {
+	if err := c.scene.Resize(width, height); err != nil {
+		return err
+	}
+
+	for _, fb := range c.pingPong {
+		if err := fb.Resize(width, height); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Delete releases the framebuffers, shaders and buffers of the chain.
+func (c *Chain) Delete() {
+	if c.scene != nil {
+		c.scene.Delete()
+	}
+	for _, fb := range c.pingPong {
+		if fb != nil {
+			fb.Delete()
+		}
+	}
+
+	for _, program := range []*shader.Shader{c.copyShader, c.inversionShader, c.grayscaleShader, c.kernelShader} {
+		if program != nil {
+			program.Delete()
+		}
+	}
+
+	gl.DeleteVertexArrays(1, &c.quadVAO)
+	gl.DeleteBuffers(1, &c.quadVBO)
+}
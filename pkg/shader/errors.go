@@ -0,0 +1,97 @@
+package shader
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// CompileError is returned when a stage fails to compile. Its message has the driver
+// log, with the line numbers rewritten to the file and line they come from after
+// resolving the includes, and the offending source lines below each log line.
+type CompileError struct {
+	Stage  Stage
+	Log    string
+	source *source
+}
+
+func (e *CompileError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "shader: compile %s shader %s", e.Stage, e.Stage.Path)
+
+	for _, logLine := range strings.Split(e.Log, "\n") {
+		line, msg, ok := parseLogLine(logLine)
+		origin, found := e.source.origin(line)
+		if !ok || !found {
+			fmt.Fprintf(&b, "\n  %s", logLine)
+			continue
+		}
+
+		fmt.Fprintf(&b, "\n  %s:%d: %s", origin.file, origin.line, msg)
+
+		// The line before the error is included as it is often where the mistake is,
+		// e.g. a missing semicolon
+		for n := max(line-1, 1); n <= line; n++ {
+			l, _ := e.source.origin(n)
+			fmt.Fprintf(&b, "\n    %s:%d: %s", l.file, l.line, l.text)
+		}
+	}
+
+	return b.String()
+}
+
+// LinkError is returned when the compiled stages fail to link into a program.
+type LinkError struct {
+	Stages []Stage
+	Log    string
+}
+
+func (e *LinkError) Error() string {
+	return fmt.Sprintf("shader: link program %s\n  %s",
+		stagePaths(e.Stages), strings.ReplaceAll(e.Log, "\n", "\n  "))
+}
+
+// logLineRegexps match the source line in the logs of the common drivers:
+//
+//	0:12(5): error: ...       Mesa
+//	0(12) : error C0000: ...  NVIDIA
+//	ERROR: 0:12: ...          AMD, Intel and Apple
+var logLineRegexps = []*regexp.Regexp{
+	regexp.MustCompile(`^\s*\d+:(?P<line>\d+)\(\d+\):\s*(?P<msg>.*)$`),
+	regexp.MustCompile(`^\s*\d+\((?P<line>\d+)\)\s*:\s*(?P<msg>.*)$`),
+	regexp.MustCompile(`^\s*(?P<severity>ERROR|WARNING):\s*\d+:(?P<line>\d+):\s*(?P<msg>.*)$`),
+}
+
+// parseLogLine returns the line of the compiled code a driver log line points to
+// and its message without the location.
+func parseLogLine(logLine string) (int, string, bool) {
+	for _, re := range logLineRegexps {
+		match := re.FindStringSubmatch(logLine)
+		if match == nil {
+			continue
+		}
+
+		line, err := strconv.Atoi(match[re.SubexpIndex("line")])
+		if err != nil {
+			return 0, "", false
+		}
+
+		msg := match[re.SubexpIndex("msg")]
+		if i := re.SubexpIndex("severity"); i >= 0 {
+			msg = strings.ToLower(match[i]) + ": " + msg
+		}
+
+		return line, msg, true
+	}
+
+	return 0, "", false
+}
+
+func stagePaths(stages []Stage) string {
+	paths := make([]string, 0, len(stages))
+	for _, stage := range stages {
+		paths = append(paths, stage.Path)
+	}
+	return strings.Join(paths, " ")
+}
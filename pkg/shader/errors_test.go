@@ -0,0 +1,90 @@
+package shader
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestParseLogLine(t *testing.T) {
+	tests := []struct {
+		name    string
+		logLine string
+		line    int
+		msg     string
+		ok      bool
+	}{
+		{name: "mesa", logLine: "0:12(5): error: `color' undeclared", line: 12, msg: "error: `color' undeclared", ok: true},
+		{name: "nvidia", logLine: "0(12) : error C0000: syntax error", line: 12, msg: "error C0000: syntax error", ok: true},
+		{name: "amd error", logLine: "ERROR: 0:7: 'vec5' : undeclared identifier", line: 7, msg: "error: 'vec5' : undeclared identifier", ok: true},
+		{name: "apple warning", logLine: "WARNING: 0:3: extension not supported", line: 3, msg: "warning: extension not supported", ok: true},
+		{name: "summary", logLine: "ERROR: 1 compilation errors.  No code generated."},
+		{name: "empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line, msg, ok := parseLogLine(tt.logLine)
+			if line != tt.line || msg != tt.msg || ok != tt.ok {
+				t.Errorf("parseLogLine(%q) = %d, %q, %v, want %d, %q, %v", tt.logLine, line, msg, ok, tt.line, tt.msg, tt.ok)
+			}
+		})
+	}
+}
+
+func TestCompileError(t *testing.T) {
+	fsys := fstest.MapFS{
+		"shaders/main.frag":   {Data: []byte("#version 410 core\n#include \"lights.glsl\"\nvoid main() {\n  color = vec4(1.0)\n}\n")},
+		"shaders/lights.glsl": {Data: []byte("struct Light {\n  vec3 color\n};\n")},
+	}
+	stage := Fragment("shaders/main.frag").Define("NR_LIGHTS", 2)
+
+	src := preprocessFS(t, fsys, stage)
+
+	tests := []struct {
+		name string
+		log  string
+		want string
+	}{
+		{
+			name: "in an include",
+			// Line 5 is the third of lights.glsl, after #version and the define
+			log: "0:5(1): error: syntax error, unexpected '}'",
+			want: "shader: compile FRAGMENT shader shaders/main.frag" +
+				"\n  shaders/lights.glsl:3: error: syntax error, unexpected '}'" +
+				"\n    shaders/lights.glsl:2:   vec3 color" +
+				"\n    shaders/lights.glsl:3: };",
+		},
+		{
+			name: "in the stage after an include",
+			log:  "0(8) : error C0000: syntax error, unexpected '}'",
+			want: "shader: compile FRAGMENT shader shaders/main.frag" +
+				"\n  shaders/main.frag:5: error C0000: syntax error, unexpected '}'" +
+				"\n    shaders/main.frag:4:   color = vec4(1.0)" +
+				"\n    shaders/main.frag:5: }",
+		},
+		{
+			name: "in the defines",
+			log:  "ERROR: 0:2: 'NR_LIGHTS' : redefinition",
+			want: "shader: compile FRAGMENT shader shaders/main.frag" +
+				"\n  <defines>:1: error: 'NR_LIGHTS' : redefinition" +
+				"\n    shaders/main.frag:1: #version 410 core" +
+				"\n    <defines>:1: #define NR_LIGHTS 2",
+		},
+		{
+			name: "unknown lines are kept",
+			log:  "0:99(1): error: past the end\nlinker says no",
+			want: "shader: compile FRAGMENT shader shaders/main.frag" +
+				"\n  0:99(1): error: past the end" +
+				"\n  linker says no",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := &CompileError{Stage: stage, Log: tt.log, source: src}
+			if got := err.Error(); got != tt.want {
+				t.Errorf("Error() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package shader
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// definesFile is the file name reported for the lines with the defines injected from Go.
+const definesFile = "<defines>"
+
+var includeRegexp = regexp.MustCompile(`^\s*#\s*include\s+"([^"]+)"\s*$`)
+
+// sourceLine is a line of the preprocessed code and where it comes from.
+type sourceLine struct {
+	file string
+	line int
+	text string
+}
+
+// source is the code of a stage after resolving its includes.
+type source struct {
+	lines []sourceLine
+	// files are the stage file and every file included by it
+	files []string
+}
+
+func (s *source) code() string {
+	var b strings.Builder
+	for _, l := range s.lines {
+		b.WriteString(l.text)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// origin returns where the line n (1-based) of the preprocessed code comes from.
+func (s *source) origin(n int) (sourceLine, bool) {
+	if n < 1 || n > len(s.lines) {
+		return sourceLine{}, false
+	}
+	return s.lines[n-1], true
+}
+
+// preprocess resolves the #include "file" directives of the stage, relative to the
+// directory of the file including them, and injects its defines right after #version.
+func preprocess(fsys fs.FS, stage Stage) (*source, error) {
+	src := &source{}
+	if err := src.include(fsys, stage.Path, nil); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(stage.Defines))
+	for name := range stage.Defines {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	defines := make([]sourceLine, 0, len(names))
+	for i, name := range names {
+		defines = append(defines, sourceLine{
+			file: definesFile,
+			line: i + 1,
+			text: fmt.Sprintf("#define %s %s", name, stage.Defines[name]),
+		})
+	}
+
+	// #version has to be the first statement of the shader
+	at := 0
+	for i, l := range src.lines {
+		if strings.HasPrefix(strings.TrimSpace(l.text), "#version") {
+			at = i + 1
+			break
+		}
+	}
+	src.lines = append(src.lines[:at], append(defines, src.lines[at:]...)...)
+
+	return src, nil
+}
+
+// include appends the lines of file, recursively replacing the #include directives.
+// stack holds the files being included to detect cycles. A file is only included once,
+// so two headers can include the same one without repeating its definitions.
+func (s *source) include(fsys fs.FS, file string, stack []string) error {
+	for _, p := range stack {
+		if p == file {
+			return fmt.Errorf("shader: include cycle %s -> %s", strings.Join(stack, " -> "), file)
+		}
+	}
+	if slices.Contains(s.files, file) {
+		return nil
+	}
+	stack = append(stack, file)
+
+	data, err := fs.ReadFile(fsys, file)
+	if err != nil {
+		if len(stack) > 1 {
+			return fmt.Errorf("shader: included from %s: %w", stack[len(stack)-2], err)
+		}
+		return fmt.Errorf("shader: %w", err)
+	}
+	s.files = append(s.files, file)
+
+	for i, text := range strings.Split(strings.TrimSuffix(string(data), "\n"), "\n") {
+		text = strings.TrimRight(text, "\r")
+
+		match := includeRegexp.FindStringSubmatch(text)
+		if match == nil {
+			s.lines = append(s.lines, sourceLine{file: file, line: i + 1, text: text})
+			continue
+		}
+
+		if err := s.include(fsys, path.Join(path.Dir(file), match[1]), stack); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,169 @@
+package shader
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func preprocessFS(t *testing.T, fsys fstest.MapFS, stage Stage) *source {
+	t.Helper()
+
+	src, err := preprocess(fsys, stage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return src
+}
+
+// checkLines fails unless the lines of src are want, as file:line: text.
+func checkLines(t *testing.T, src *source, want []string) {
+	t.Helper()
+
+	var got []string
+	for _, l := range src.lines {
+		got = append(got, fmt.Sprintf("%s:%d: %s", l.file, l.line, l.text))
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("lines =\n  %s\nwant\n  %s", strings.Join(got, "\n  "), strings.Join(want, "\n  "))
+	}
+}
+
+func TestPreprocessDiamondInclude(t *testing.T) {
+	// Both headers include lights.glsl, from different directories
+	fsys := fstest.MapFS{
+		"shaders/main.frag":   {Data: []byte("#version 410 core\n#include \"a.glsl\"\n#include \"sub/b.glsl\"\nvoid main() {}\n")},
+		"shaders/a.glsl":      {Data: []byte("#include \"lights.glsl\"\nfloat a;\n")},
+		"shaders/sub/b.glsl":  {Data: []byte("#include \"../lights.glsl\"\nfloat b;\n")},
+		"shaders/lights.glsl": {Data: []byte("struct Light { vec3 color; };\n")},
+	}
+
+	src := preprocessFS(t, fsys, Fragment("shaders/main.frag"))
+
+	checkLines(t, src, []string{
+		"shaders/main.frag:1: #version 410 core",
+		"shaders/lights.glsl:1: struct Light { vec3 color; };",
+		"shaders/a.glsl:2: float a;",
+		"shaders/sub/b.glsl:2: float b;",
+		"shaders/main.frag:4: void main() {}",
+	})
+
+	wantFiles := []string{"shaders/main.frag", "shaders/a.glsl", "shaders/lights.glsl", "shaders/sub/b.glsl"}
+	if !slices.Equal(src.files, wantFiles) {
+		t.Errorf("files = %v, want %v", src.files, wantFiles)
+	}
+}
+
+func TestPreprocessIncludes(t *testing.T) {
+	fsys := fstest.MapFS{
+		// Windows line endings and spaces in the directive
+		"shaders/main.vert":          {Data: []byte("#version 410 core\r\n  #  include \"common/light.glsl\"  \r\nvoid main() {}\r\n")},
+		"shaders/common/light.glsl":  {Data: []byte("#include \"../common/shadow.glsl\"\nvec3 light;")},
+		"shaders/common/shadow.glsl": {Data: []byte("float shadow;\n")},
+	}
+
+	src := preprocessFS(t, fsys, Vertex("shaders/main.vert"))
+
+	checkLines(t, src, []string{
+		"shaders/main.vert:1: #version 410 core",
+		"shaders/common/shadow.glsl:1: float shadow;",
+		"shaders/common/light.glsl:2: vec3 light;",
+		"shaders/main.vert:3: void main() {}",
+	})
+	if got := src.code(); got != "#version 410 core\nfloat shadow;\nvec3 light;\nvoid main() {}\n" {
+		t.Errorf("code = %q", got)
+	}
+}
+
+func TestPreprocessDefines(t *testing.T) {
+	tests := []struct {
+		name string
+		code string
+		want []string
+	}{
+		{
+			name: "after version",
+			code: "#version 410 core\nvoid main() {}\n",
+			want: []string{
+				"main.frag:1: #version 410 core",
+				"<defines>:1: #define LIGHTS_BLOCK 1",
+				"<defines>:2: #define NR_POINT_LIGHTS 4",
+				"main.frag:2: void main() {}",
+			},
+		},
+		{
+			name: "version after comments",
+			code: "// The lights\n  #version 410 core\nvoid main() {}\n",
+			want: []string{
+				"main.frag:1: // The lights",
+				"main.frag:2:   #version 410 core",
+				"<defines>:1: #define LIGHTS_BLOCK 1",
+				"<defines>:2: #define NR_POINT_LIGHTS 4",
+				"main.frag:3: void main() {}",
+			},
+		},
+		{
+			name: "without version",
+			code: "void main() {}\n",
+			want: []string{
+				"<defines>:1: #define LIGHTS_BLOCK 1",
+				"<defines>:2: #define NR_POINT_LIGHTS 4",
+				"main.frag:1: void main() {}",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := fstest.MapFS{"main.frag": {Data: []byte(tt.code)}}
+			// Sorted by name, whatever the order they are defined in
+			stage := Fragment("main.frag").Define("NR_POINT_LIGHTS", 4).Define("LIGHTS_BLOCK", 1)
+
+			checkLines(t, preprocessFS(t, fsys, stage), tt.want)
+		})
+	}
+}
+
+func TestPreprocessErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+		want string
+	}{
+		{
+			name: "cycle",
+			fsys: fstest.MapFS{
+				"main.frag": {Data: []byte("#include \"a.glsl\"\n")},
+				"a.glsl":    {Data: []byte("#include \"b.glsl\"\n")},
+				"b.glsl":    {Data: []byte("#include \"a.glsl\"\n")},
+			},
+			want: "shader: include cycle main.frag -> a.glsl -> b.glsl -> a.glsl",
+		},
+		{
+			name: "includes itself",
+			fsys: fstest.MapFS{"main.frag": {Data: []byte("#include \"main.frag\"\n")}},
+			want: "shader: include cycle main.frag -> main.frag",
+		},
+		{
+			name: "missing include",
+			fsys: fstest.MapFS{"main.frag": {Data: []byte("#include \"missing.glsl\"\n")}},
+			want: "shader: included from main.frag: open missing.glsl",
+		},
+		{
+			name: "missing stage",
+			fsys: fstest.MapFS{},
+			want: "shader: open main.frag",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := preprocess(tt.fsys, Fragment("main.frag"))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("preprocess() = %v, want an error with %q", err, tt.want)
+			}
+		})
+	}
+}
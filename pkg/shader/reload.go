@@ -0,0 +1,127 @@
+package shader
+
+import (
+	"fmt"
+	"io/fs"
+	"time"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// hotReload watches every shader created once EnableHotReload is called.
+var hotReload *watcher
+
+type watcher struct {
+	interval time.Duration
+	lastPoll time.Time
+	shaders  map[*Shader]struct{}
+}
+
+// EnableHotReload makes every shader created from now on be watched for changes in its
+// source files. The files are checked at most once per interval by PollHotReload.
+// Files without a modification time, like the ones in an embed.FS, never reload.
+func EnableHotReload(interval time.Duration) {
+	hotReload = &watcher{
+		interval: interval,
+		shaders:  make(map[*Shader]struct{}),
+	}
+}
+
+// PollHotReload reloads the watched shaders whose source files changed. It must be called
+// from the thread owning the GL context, usually once per frame before rendering.
+// When a shader fails to compile the error log is printed and the previous program is kept.
+func PollHotReload() {
+	if hotReload == nil || time.Since(hotReload.lastPoll) < hotReload.interval {
+		return
+	}
+	hotReload.lastPoll = time.Now()
+
+	for s := range hotReload.shaders {
+		reloaded, err := s.ReloadIfChanged()
+		if err != nil {
+			fmt.Printf("shader: reload %s failed, keeping the previous program\n%v\n", stagePaths(s.stages), err)
+			continue
+		}
+		if reloaded {
+			fmt.Printf("shader: reloaded %s\n", stagePaths(s.stages))
+		}
+	}
+}
+
+func watch(s *Shader) {
+	if hotReload != nil {
+		hotReload.shaders[s] = struct{}{}
+	}
+}
+
+func unwatch(s *Shader) {
+	if hotReload != nil {
+		delete(hotReload.shaders, s)
+	}
+}
+
+// ReloadIfChanged reloads the shader when any of its source files was modified since
+// it was last built. See Reload.
+func (s *Shader) ReloadIfChanged() (bool, error) {
+	modTime, err := s.sourceModTime()
+	if err != nil {
+		return false, err
+	}
+
+	if !modTime.After(s.modTime) {
+		return false, nil
+	}
+
+	// The files are not retried until they change again, even if the build fails
+	s.modTime = modTime
+
+	if err := s.Reload(); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Reload compiles and links the source files again. On success the program ID is swapped
+// and the old program deleted, otherwise the shader keeps using the old program.
+// Uniform values are not carried over to the new program, the scene has to set them again
+// before the next draw, which the scenes already do every frame.
+func (s *Shader) Reload() error {
+	id, files, err := buildProgram(s.fsys, s.stages)
+	if err != nil {
+		return err
+	}
+	s.files = files
+
+	var current int32
+	gl.GetIntegerv(gl.CURRENT_PROGRAM, &current)
+
+	old := s.ID
+	s.ID = id
+	s.uniforms = loadUniforms(id)
+	s.reported = nil
+	gl.DeleteProgram(old)
+
+	if uint32(current) == old {
+		gl.UseProgram(id)
+	}
+
+	return nil
+}
+
+func (s *Shader) sourceModTime() (time.Time, error) {
+	var latest time.Time
+
+	for _, path := range s.files {
+		info, err := fs.Stat(s.fsys, path)
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+
+	return latest, nil
+}
@@ -3,108 +3,255 @@ package shader
 import (
 	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/go-gl/gl/v4.1-core/gl"
-	"github.com/go-gl/mathgl/mgl32"
 )
 
+// computeShader is GL_COMPUTE_SHADER, which is part of OpenGL 4.3 and not of the 4.1 bindings.
+const computeShader = 0x91B9
+
+// Stage is a shader source file and the pipeline stage it is compiled for.
+// The file may #include "other.glsl" files relative to its own directory.
+type Stage struct {
+	Type uint32
+	Path string
+	// Defines are injected as #define name value right after #version.
+	Defines map[string]string
+}
+
+func Vertex(path string) Stage {
+	return Stage{Type: gl.VERTEX_SHADER, Path: path}
+}
+
+func TessControl(path string) Stage {
+	return Stage{Type: gl.TESS_CONTROL_SHADER, Path: path}
+}
+
+func TessEvaluation(path string) Stage {
+	return Stage{Type: gl.TESS_EVALUATION_SHADER, Path: path}
+}
+
+func Geometry(path string) Stage {
+	return Stage{Type: gl.GEOMETRY_SHADER, Path: path}
+}
+
+func Fragment(path string) Stage {
+	return Stage{Type: gl.FRAGMENT_SHADER, Path: path}
+}
+
+// Compute needs an OpenGL 4.3 context or the ARB_compute_shader extension.
+// It can not be linked together with other stages.
+func Compute(path string) Stage {
+	return Stage{Type: computeShader, Path: path}
+}
+
+// Define returns a copy of the stage with the #define name value injected, e.g.
+//
+//	shader.Fragment("multiple_lights.frag").Define("NR_POINT_LIGHTS", 4)
+func (s Stage) Define(name string, value any) Stage {
+	defines := make(map[string]string, len(s.Defines)+1)
+	for k, v := range s.Defines {
+		defines[k] = v
+	}
+	defines[name] = fmt.Sprint(value)
+	s.Defines = defines
+
+	return s
+}
+
+func (s Stage) String() string {
+	switch s.Type {
+	case gl.VERTEX_SHADER:
+		return "VERTEX"
+	case gl.TESS_CONTROL_SHADER:
+		return "TESS_CONTROL"
+	case gl.TESS_EVALUATION_SHADER:
+		return "TESS_EVALUATION"
+	case gl.GEOMETRY_SHADER:
+		return "GEOMETRY"
+	case gl.FRAGMENT_SHADER:
+		return "FRAGMENT"
+	case computeShader:
+		return "COMPUTE"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// New builds a program from a vertex and a fragment shader read from the working directory.
 func New(vertexPath, fragPath string) (*Shader, error) {
-	vertexCode, err := readFile(vertexPath)
-	if err != nil {
+	return NewFS(os.DirFS("."), vertexPath, fragPath)
+}
+
+// NewFS builds a program from a vertex and a fragment shader read from fsys.
+func NewFS(fsys fs.FS, vertexPath, fragPath string) (*Shader, error) {
+	return NewProgramFS(fsys, Vertex(vertexPath), Fragment(fragPath))
+}
+
+// NewProgram compiles every stage read from the working directory and links them into a program, e.g.
+//
+//	shader.NewProgram(shader.Vertex("a.vert"), shader.Geometry("a.geom"), shader.Fragment("a.frag"))
+func NewProgram(stages ...Stage) (*Shader, error) {
+	return NewProgramFS(os.DirFS("."), stages...)
+}
+
+// NewProgramFS compiles every stage read from fsys and links them into a program.
+func NewProgramFS(fsys fs.FS, stages ...Stage) (*Shader, error) {
+	if err := validateStages(stages); err != nil {
 		return nil, err
 	}
 
-	vertexShader, err := buildShader(gl.VERTEX_SHADER, vertexCode)
+	id, files, err := buildProgram(fsys, stages)
 	if err != nil {
 		return nil, err
 	}
-	defer gl.DeleteShader(vertexShader)
 
-	fragCode, err := readFile(fragPath)
-	if err != nil {
-		return nil, err
+	s := &Shader{
+		ID:       id,
+		uniforms: loadUniforms(id),
+		stages:   stages,
+		fsys:     fsys,
+		files:    files,
 	}
+	s.modTime, _ = s.sourceModTime()
+	watch(s)
 
-	fragShader, err := buildShader(gl.FRAGMENT_SHADER, fragCode)
-	if err != nil {
-		return nil, err
+	return s, nil
+}
+
+func validateStages(stages []Stage) error {
+	if len(stages) == 0 {
+		return errors.New("shader: program needs at least one stage")
 	}
-	defer gl.DeleteShader(fragShader)
 
-	id := gl.CreateProgram()
-	gl.AttachShader(id, vertexShader)
-	gl.AttachShader(id, fragShader)
-	gl.LinkProgram(id)
+	seen := make(map[uint32]bool, len(stages))
+	for _, stage := range stages {
+		if stage.String() == "UNKNOWN" {
+			return fmt.Errorf("shader: %s: unknown stage type 0x%X", stage.Path, stage.Type)
+		}
 
-	if err := checkCompileErr(id, "PROGRAM"); err != nil {
-		return nil, err
+		if seen[stage.Type] {
+			return fmt.Errorf("shader: %s: more than one %s stage", stage.Path, stage)
+		}
+		seen[stage.Type] = true
 	}
 
-	return &Shader{ID: id}, nil
+	if seen[computeShader] && len(stages) > 1 {
+		return errors.New("shader: a COMPUTE stage can not be linked with other stages")
+	}
+
+	return nil
 }
 
-func readFile(path string) ([]byte, error) {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return nil, err
+// buildProgram returns the linked program and every file read to build it.
+func buildProgram(fsys fs.FS, stages []Stage) (uint32, []string, error) {
+	id := gl.CreateProgram()
+
+	var files []string
+	for _, stage := range stages {
+		src, err := preprocess(fsys, stage)
+		if err != nil {
+			gl.DeleteProgram(id)
+			return 0, nil, err
+		}
+		files = append(files, src.files...)
+
+		shader, err := buildShader(stage, src)
+		if err != nil {
+			gl.DeleteProgram(id)
+			return 0, nil, err
+		}
+
+		gl.AttachShader(id, shader)
+		// Only flagged for deletion, it is deleted once the program is
+		defer gl.DeleteShader(shader)
+	}
+
+	gl.LinkProgram(id)
+
+	if err := checkLinkErr(id, stages); err != nil {
+		gl.DeleteProgram(id)
+		return 0, nil, err
 	}
 
-	return data, nil
+	return id, files, nil
 }
 
-func buildShader(xtype uint32, sourceCode []byte) (uint32, error) {
-	shader := gl.CreateShader(xtype)
+func buildShader(stage Stage, src *source) (uint32, error) {
+	shader := gl.CreateShader(stage.Type)
+	if shader == 0 {
+		return 0, fmt.Errorf("shader: %s: %s shaders are not supported by this context", stage.Path, stage)
+	}
 
-	csrc, free := gl.Strs(string(sourceCode) + "\x00")
+	csrc, free := gl.Strs(src.code() + "\x00")
 	defer free()
 
 	gl.ShaderSource(shader, 1, csrc, nil)
 	gl.CompileShader(shader)
 
-	var typestr string
-	switch xtype {
-	case gl.VERTEX_SHADER:
-		typestr = "VERTEX"
-	case gl.FRAGMENT_SHADER:
-		typestr = "FRAGMENT"
-	default:
-		typestr = "UNKNOWN"
-	}
-
-	if err := checkCompileErr(shader, typestr); err != nil {
+	if err := checkCompileErr(shader, stage, src); err != nil {
+		gl.DeleteShader(shader)
 		return 0, err
 	}
 
 	return shader, nil
 }
 
-func checkCompileErr(shader uint32, xtype string) error {
+func checkCompileErr(shader uint32, stage Stage, src *source) error {
 	var status int32
-
-	if xtype != "PROGRAM" {
-		gl.GetShaderiv(shader, gl.COMPILE_STATUS, &status)
-	} else {
-		gl.GetProgramiv(shader, gl.LINK_STATUS, &status)
+	gl.GetShaderiv(shader, gl.COMPILE_STATUS, &status)
+	if status == gl.TRUE {
+		return nil
 	}
 
-	if status == gl.FALSE {
-		var logLength int32
-		gl.GetShaderiv(shader, gl.INFO_LOG_LENGTH, &logLength)
+	var logLength int32
+	gl.GetShaderiv(shader, gl.INFO_LOG_LENGTH, &logLength)
 
-		log := strings.Repeat("\x00", int(logLength+1))
-		gl.GetShaderInfoLog(shader, logLength, nil, gl.Str(log))
+	log := strings.Repeat("\x00", int(logLength+1))
+	gl.GetShaderInfoLog(shader, logLength, nil, gl.Str(log))
 
-		msg := fmt.Sprintf("compile %s shader\n %s\n", xtype, log)
-		return errors.New(msg)
+	return &CompileError{
+		Stage:  stage,
+		Log:    strings.TrimRight(log, "\x00\n"),
+		source: src,
 	}
+}
 
-	return nil
+func checkLinkErr(program uint32, stages []Stage) error {
+	var status int32
+	gl.GetProgramiv(program, gl.LINK_STATUS, &status)
+	if status == gl.TRUE {
+		return nil
+	}
+
+	var logLength int32
+	gl.GetProgramiv(program, gl.INFO_LOG_LENGTH, &logLength)
+
+	log := strings.Repeat("\x00", int(logLength+1))
+	gl.GetProgramInfoLog(program, logLength, nil, gl.Str(log))
+
+	return &LinkError{
+		Stages: stages,
+		Log:    strings.TrimRight(log, "\x00\n"),
+	}
 }
 
 type Shader struct {
-	ID uint32
+	ID       uint32
+	uniforms map[string]uniform
+	// reported holds the uniforms whose errors were printed, see report
+	reported map[string]bool
+
+	// Stages the program was built from, the files they read including the
+	// included ones and their latest modification time, used to reload the program
+	stages  []Stage
+	fsys    fs.FS
+	files   []string
+	modTime time.Time
 }
 
 func (s *Shader) Use() {
@@ -112,32 +259,6 @@ func (s *Shader) Use() {
 }
 
 func (s *Shader) Delete() {
+	unwatch(s)
 	gl.DeleteProgram(s.ID)
 }
-
-// TODO: Add uniform location cache to be used in every Set*Uniform* method below
-
-func (s *Shader) SetInt(name string, value int32) {
-	uniform := gl.GetUniformLocation(s.ID, gl.Str(name+"\x00"))
-	gl.Uniform1i(uniform, value)
-}
-
-func (s *Shader) SetFloat(name string, value float32) {
-	uniform := gl.GetUniformLocation(s.ID, gl.Str(name+"\x00"))
-	gl.Uniform1f(uniform, value)
-}
-
-func (s *Shader) SetMat4(name string, value mgl32.Mat4) {
-	uniform := gl.GetUniformLocation(s.ID, gl.Str(name+"\x00"))
-	gl.UniformMatrix4fv(uniform, 1, false, &value[0])
-}
-
-func (s *Shader) SetVec3f(name string, x, y, z float32) {
-	uniform := gl.GetUniformLocation(s.ID, gl.Str(name+"\x00"))
-	gl.Uniform3f(uniform, x, y, z)
-}
-
-func (s *Shader) SetVec3(name string, value mgl32.Vec3) {
-	uniform := gl.GetUniformLocation(s.ID, gl.Str(name+"\x00"))
-	gl.Uniform3fv(uniform, 1, &value[0])
-}
@@ -0,0 +1,298 @@
+package shader
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+var (
+	// ErrUnknownUniform is returned when setting a uniform that is not active in the program.
+	// Keep in mind the GLSL compiler removes uniforms that do not contribute to the output.
+	ErrUnknownUniform = errors.New("unknown uniform")
+
+	// ErrUniformType is returned when the value does not match the uniform type declared in GLSL.
+	ErrUniformType = errors.New("uniform type mismatch")
+)
+
+// uniform is an active uniform of the program as reported by glGetActiveUniform.
+type uniform struct {
+	location int32
+	xtype    uint32
+	// size is the number of elements for arrays and 1 otherwise.
+	size int32
+}
+
+// loadUniforms introspects the active uniforms of a linked program. Arrays can be set
+// by their name with or without the [0] suffix and each element also by its own index.
+func loadUniforms(program uint32) map[string]uniform {
+	var count, maxLength int32
+	gl.GetProgramiv(program, gl.ACTIVE_UNIFORMS, &count)
+	gl.GetProgramiv(program, gl.ACTIVE_UNIFORM_MAX_LENGTH, &maxLength)
+
+	uniforms := make(map[string]uniform, count)
+	buf := make([]uint8, maxLength+1)
+
+	for i := int32(0); i < count; i++ {
+		var length, size int32
+		var xtype uint32
+		gl.GetActiveUniform(program, uint32(i), maxLength+1, &length, &size, &xtype, &buf[0])
+		name := string(buf[:length])
+
+		location := gl.GetUniformLocation(program, gl.Str(name+"\x00"))
+		if location < 0 {
+			// Uniforms inside uniform blocks do not have a location
+			continue
+		}
+
+		uniforms[name] = uniform{location: location, xtype: xtype, size: size}
+
+		base, isArray := strings.CutSuffix(name, "[0]")
+		if !isArray {
+			continue
+		}
+
+		uniforms[base] = uniforms[name]
+		for idx := int32(1); idx < size; idx++ {
+			elem := fmt.Sprintf("%s[%d]", base, idx)
+			location := gl.GetUniformLocation(program, gl.Str(elem+"\x00"))
+			uniforms[elem] = uniform{location: location, xtype: xtype, size: size - idx}
+		}
+	}
+
+	return uniforms
+}
+
+// uniform looks up name and checks its GLSL type is one of types and it can hold count
+// elements. The errors are also reported.
+func (s *Shader) uniform(name string, count int, types ...uint32) (int32, error) {
+	location, err := s.lookup(name, count, types)
+	if err != nil {
+		s.report(name, err)
+	}
+	return location, err
+}
+
+// report prints the first error setting the uniform name in the program. The scenes set
+// their uniforms every frame without checking the errors of the setters, so a typo in a
+// name or a type shows once instead of never or on every frame.
+func (s *Shader) report(name string, err error) {
+	if s.reported[name] {
+		return
+	}
+	if s.reported == nil {
+		s.reported = make(map[string]bool)
+	}
+	s.reported[name] = true
+
+	fmt.Printf("%v in %s\n", err, stagePaths(s.stages))
+}
+
+func (s *Shader) lookup(name string, count int, types []uint32) (int32, error) {
+	u, ok := s.uniforms[name]
+	if !ok {
+		return -1, fmt.Errorf("shader: %w %q", ErrUnknownUniform, name)
+	}
+
+	if !matchType(u.xtype, types) {
+		return -1, fmt.Errorf("shader: %w: %q is %s", ErrUniformType, name, typeName(u.xtype))
+	}
+
+	if int32(count) > u.size {
+		return -1, fmt.Errorf("shader: %w: %q holds %d elements, got %d", ErrUniformType, name, u.size, count)
+	}
+
+	return u.location, nil
+}
+
+// UniformType returns the GLSL type of the active uniform called name, e.g. gl.FLOAT_VEC3
+// or gl.SAMPLER_2D, so what is set can depend on what the shader declares.
+func (s *Shader) UniformType(name string) (uint32, bool) {
+	u, ok := s.uniforms[name]
+	return u.xtype, ok
+}
+
+func matchType(xtype uint32, types []uint32) bool {
+	for _, t := range types {
+		if t == xtype {
+			return true
+		}
+	}
+	return false
+}
+
+// intTypes can be set with glUniform1i. Samplers are set with the texture unit they read from.
+var intTypes = []uint32{
+	gl.INT,
+	gl.BOOL,
+	gl.SAMPLER_1D,
+	gl.SAMPLER_2D,
+	gl.SAMPLER_3D,
+	gl.SAMPLER_CUBE,
+	gl.SAMPLER_1D_SHADOW,
+	gl.SAMPLER_2D_SHADOW,
+	gl.SAMPLER_1D_ARRAY,
+	gl.SAMPLER_2D_ARRAY,
+	gl.SAMPLER_2D_ARRAY_SHADOW,
+	gl.SAMPLER_CUBE_SHADOW,
+	gl.SAMPLER_CUBE_MAP_ARRAY,
+	gl.SAMPLER_2D_MULTISAMPLE,
+	gl.SAMPLER_BUFFER,
+	gl.INT_SAMPLER_2D,
+	gl.INT_SAMPLER_3D,
+	gl.INT_SAMPLER_CUBE,
+	gl.INT_SAMPLER_2D_ARRAY,
+	gl.UNSIGNED_INT_SAMPLER_2D,
+	gl.UNSIGNED_INT_SAMPLER_3D,
+	gl.UNSIGNED_INT_SAMPLER_CUBE,
+	gl.UNSIGNED_INT_SAMPLER_2D_ARRAY,
+}
+
+func typeName(xtype uint32) string {
+	switch xtype {
+	case gl.FLOAT:
+		return "float"
+	case gl.FLOAT_VEC2:
+		return "vec2"
+	case gl.FLOAT_VEC3:
+		return "vec3"
+	case gl.FLOAT_VEC4:
+		return "vec4"
+	case gl.FLOAT_MAT3:
+		return "mat3"
+	case gl.FLOAT_MAT4:
+		return "mat4"
+	case gl.INT:
+		return "int"
+	case gl.BOOL:
+		return "bool"
+	}
+
+	if matchType(xtype, intTypes) {
+		return "sampler"
+	}
+
+	return fmt.Sprintf("type 0x%X", xtype)
+}
+
+func (s *Shader) SetInt(name string, value int32) error {
+	location, err := s.uniform(name, 1, intTypes...)
+	if err != nil {
+		return err
+	}
+	gl.Uniform1i(location, value)
+	return nil
+}
+
+func (s *Shader) SetBool(name string, value bool) error {
+	location, err := s.uniform(name, 1, gl.BOOL)
+	if err != nil {
+		return err
+	}
+
+	var v int32
+	if value {
+		v = 1
+	}
+	gl.Uniform1i(location, v)
+	return nil
+}
+
+func (s *Shader) SetFloat(name string, value float32) error {
+	location, err := s.uniform(name, 1, gl.FLOAT)
+	if err != nil {
+		return err
+	}
+	gl.Uniform1f(location, value)
+	return nil
+}
+
+func (s *Shader) SetVec2(name string, value mgl32.Vec2) error {
+	return s.SetVec2Array(name, []mgl32.Vec2{value})
+}
+
+func (s *Shader) SetVec3f(name string, x, y, z float32) error {
+	return s.SetVec3(name, mgl32.Vec3{x, y, z})
+}
+
+func (s *Shader) SetVec3(name string, value mgl32.Vec3) error {
+	return s.SetVec3Array(name, []mgl32.Vec3{value})
+}
+
+func (s *Shader) SetVec4(name string, value mgl32.Vec4) error {
+	return s.SetVec4Array(name, []mgl32.Vec4{value})
+}
+
+func (s *Shader) SetMat3(name string, value mgl32.Mat3) error {
+	return s.SetMat3Array(name, []mgl32.Mat3{value})
+}
+
+func (s *Shader) SetMat4(name string, value mgl32.Mat4) error {
+	return s.SetMat4Array(name, []mgl32.Mat4{value})
+}
+
+// The array setters write values starting at the element referred by name, so "lights[2]"
+// sets the elements from 2 onwards.
+
+func (s *Shader) SetVec2Array(name string, values []mgl32.Vec2) error {
+	if len(values) == 0 {
+		return nil
+	}
+	location, err := s.uniform(name, len(values), gl.FLOAT_VEC2)
+	if err != nil {
+		return err
+	}
+	gl.Uniform2fv(location, int32(len(values)), &values[0][0])
+	return nil
+}
+
+func (s *Shader) SetVec3Array(name string, values []mgl32.Vec3) error {
+	if len(values) == 0 {
+		return nil
+	}
+	location, err := s.uniform(name, len(values), gl.FLOAT_VEC3)
+	if err != nil {
+		return err
+	}
+	gl.Uniform3fv(location, int32(len(values)), &values[0][0])
+	return nil
+}
+
+func (s *Shader) SetVec4Array(name string, values []mgl32.Vec4) error {
+	if len(values) == 0 {
+		return nil
+	}
+	location, err := s.uniform(name, len(values), gl.FLOAT_VEC4)
+	if err != nil {
+		return err
+	}
+	gl.Uniform4fv(location, int32(len(values)), &values[0][0])
+	return nil
+}
+
+func (s *Shader) SetMat3Array(name string, values []mgl32.Mat3) error {
+	if len(values) == 0 {
+		return nil
+	}
+	location, err := s.uniform(name, len(values), gl.FLOAT_MAT3)
+	if err != nil {
+		return err
+	}
+	gl.UniformMatrix3fv(location, int32(len(values)), false, &values[0][0])
+	return nil
+}
+
+func (s *Shader) SetMat4Array(name string, values []mgl32.Mat4) error {
+	if len(values) == 0 {
+		return nil
+	}
+	location, err := s.uniform(name, len(values), gl.FLOAT_MAT4)
+	if err != nil {
+		return err
+	}
+	gl.UniformMatrix4fv(location, int32(len(values)), false, &values[0][0])
+	return nil
+}
@@ -0,0 +1,120 @@
+package shadow
+
+import (
+	"errors"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/igoramorim/gopengl/pkg/framebuffer"
+	"github.com/igoramorim/gopengl/pkg/shader"
+)
+
+// cubeFaces are the direction and up vector of each face, in the order of their targets
+// gl.TEXTURE_CUBE_MAP_POSITIVE_X + i. The up vectors point down as cubemaps are sampled
+// with the first row at the top.
+var cubeFaces = [6][2]mgl32.Vec3{
+	{{1, 0, 0}, {0, -1, 0}},
+	{{-1, 0, 0}, {0, -1, 0}},
+	{{0, 1, 0}, {0, 0, 1}},
+	{{0, -1, 0}, {0, 0, -1}},
+	{{0, 0, 1}, {0, -1, 0}},
+	{{0, 0, -1}, {0, -1, 0}},
+}
+
+// NewCubeMap creates an omnidirectional shadow map for point lights.
+func NewCubeMap(opts Options) (*CubeMap, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	depth, err := shader.NewProgramFS(shaders,
+		shader.Vertex("depth_cube.vert"),
+		shader.Geometry("depth_cube.geom"),
+		shader.Fragment("depth_cube.frag"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	fb, err := framebuffer.New(opts.Size, opts.Size, framebuffer.DepthCube())
+	if err != nil {
+		depth.Delete()
+		return nil, err
+	}
+
+	return &CubeMap{
+		Options: opts,
+		fb:      fb,
+		depth:   depth,
+	}, nil
+}
+
+// CubeMap is the distance from a point light to the closest caster in every direction.
+type CubeMap struct {
+	// Options of the map. The bias and PCF can be changed at any time, the size and
+	// range only by NewCubeMap.
+	Options
+
+	fb    *framebuffer.Framebuffer
+	depth *shader.Shader
+	// position is the light position of the last Render
+	position mgl32.Vec3
+}
+
+// Render draws the distance from position to the casters on the six faces at once.
+// The framebuffer and viewport bound before are restored.
+func (m *CubeMap) Render(position mgl32.Vec3, draw DrawFunc) {
+	m.position = position
+
+	restore := save()
+	defer restore()
+
+	m.fb.Bind()
+	gl.Enable(gl.DEPTH_TEST)
+	gl.Clear(gl.DEPTH_BUFFER_BIT)
+
+	m.depth.Use()
+	m.depth.SetMat4Array("faces", m.Faces(position))
+	m.depth.SetVec3("lightPos", position)
+	m.depth.SetFloat("far", m.Far)
+	draw(m.depth)
+}
+
+// Faces returns the projection and view matrices of each face seen from position.
+func (m *CubeMap) Faces(position mgl32.Vec3) []mgl32.Mat4 {
+	projection := mgl32.Perspective(mgl32.DegToRad(90.0), 1.0, m.Near, m.Far)
+
+	faces := make([]mgl32.Mat4, len(cubeFaces))
+	for i, f := range cubeFaces {
+		faces[i] = projection.Mul4(mgl32.LookAtV(position, position.Add(f[0]), f[1]))
+	}
+
+	return faces
+}
+
+// Texture returns the depth cubemap of the shadow map.
+func (m *CubeMap) Texture() uint32 {
+	return m.fb.Texture(gl.DEPTH_ATTACHMENT)
+}
+
+// Bind binds the shadow map to the texture unit gl.TEXTURE0 + unit and sets the fields
+// of the PointShadow struct uniform called name, declared in shaders/shadows.glsl.
+// s must be in use.
+func (m *CubeMap) Bind(s *shader.Shader, name string, unit uint32) error {
+	gl.ActiveTexture(gl.TEXTURE0 + unit)
+	gl.BindTexture(gl.TEXTURE_CUBE_MAP, m.Texture())
+
+	return errors.Join(
+		s.SetInt(name+".map", int32(unit)),
+		s.SetVec3(name+".position", m.position),
+		s.SetFloat(name+".far", m.Far),
+		s.SetFloat(name+".bias", m.Bias),
+		s.SetFloat(name+".slopeBias", m.SlopeBias),
+		s.SetInt(name+".pcf", int32(m.PCF)),
+	)
+}
+
+func (m *CubeMap) Delete() {
+	m.fb.Delete()
+	m.depth.Delete()
+}
@@ -0,0 +1,5 @@
+#version 330 core
+
+void main() {
+	// Only the depth is written, which OpenGL does by itself
+}
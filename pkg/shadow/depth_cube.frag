@@ -0,0 +1,11 @@
+#version 330 core
+
+in vec4 FragPos;
+
+uniform vec3 lightPos;
+uniform float far;
+
+void main() {
+	// The linear distance to the light, mapped to [0, 1], instead of the perspective depth
+	gl_FragDepth = length(FragPos.xyz - lightPos) / far;
+}
@@ -0,0 +1,10 @@
+#version 330 core
+
+layout (location = 0) in vec3 position;
+
+uniform mat4 model;
+
+void main() {
+	// The geometry shader projects the vertex on each face
+	gl_Position = model * vec4(position, 1.0);
+}
@@ -0,0 +1,165 @@
+// Package shadow renders the depth of a scene as seen from a light, so it can be
+// sampled to find which fragments the light does not reach.
+package shadow
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/igoramorim/gopengl/pkg/framebuffer"
+	"github.com/igoramorim/gopengl/pkg/shader"
+)
+
+//go:embed *.vert *.geom *.frag
+var shaders embed.FS
+
+// DrawFunc draws the shadow casters with depth, which is already in use. It has to set
+// the "model" matrix of each caster, e.g. depth.SetMat4("model", model), and the
+// position of its vertices must be the attribute 0.
+type DrawFunc func(depth *shader.Shader)
+
+// Options controls the resolution and filtering of a shadow map.
+type Options struct {
+	// Size is the width and height of the shadow map, or of each face of a cube map.
+	Size int
+
+	// Near and Far are the range of the projection of spot and point lights. Nothing
+	// outside of it casts shadows.
+	Near float32
+	Far  float32
+
+	// Bias is the minimum depth offset that avoids shadow acne on surfaces facing the light.
+	// SlopeBias is added for surfaces at grazing angles, scaled by the tangent of the angle
+	// between the normal and the light, which is where acne shows up the most.
+	Bias      float32
+	SlopeBias float32
+
+	// PCF is the radius, in texels, of the percentage-closer filter that softens the edges
+	// of the shadows. 0 samples the map once per fragment.
+	PCF int
+}
+
+// DefaultOptions is a 2048x2048 shadow map softened by a 3x3 filter.
+var DefaultOptions = Options{
+	Size:      2048,
+	Near:      0.1,
+	Far:       50.0,
+	Bias:      0.0005,
+	SlopeBias: 0.002,
+	PCF:       1,
+}
+
+func (o Options) validate() error {
+	if o.Size <= 0 {
+		return fmt.Errorf("shadow: invalid size %d", o.Size)
+	}
+	if o.Near <= 0 || o.Far <= o.Near {
+		return fmt.Errorf("shadow: invalid range [%g, %g]", o.Near, o.Far)
+	}
+	if o.PCF < 0 {
+		return fmt.Errorf("shadow: invalid PCF radius %d", o.PCF)
+	}
+	return nil
+}
+
+// NewMap creates a 2D shadow map for directional and spot lights.
+func NewMap(opts Options) (*Map, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	depth, err := shader.NewFS(shaders, "depth.vert", "depth.frag")
+	if err != nil {
+		return nil, err
+	}
+
+	fb, err := framebuffer.New(opts.Size, opts.Size, framebuffer.Depth())
+	if err != nil {
+		depth.Delete()
+		return nil, err
+	}
+
+	return &Map{
+		Options:    opts,
+		fb:         fb,
+		depth:      depth,
+		lightSpace: mgl32.Ident4(),
+	}, nil
+}
+
+// Map is the depth of a scene projected from a directional or spot light.
+type Map struct {
+	// Options of the map. The bias and PCF can be changed at any time, the size and
+	// range only by NewMap.
+	Options
+
+	fb    *framebuffer.Framebuffer
+	depth *shader.Shader
+	// lightSpace is the projection and view of the last Render
+	lightSpace mgl32.Mat4
+}
+
+// Render draws the depth of the casters as seen through lightSpace, the projection and
+// view matrices of the light. The framebuffer and viewport bound before are restored.
+func (m *Map) Render(lightSpace mgl32.Mat4, draw DrawFunc) {
+	m.lightSpace = lightSpace
+
+	restore := save()
+	defer restore()
+
+	m.fb.Bind()
+	gl.Enable(gl.DEPTH_TEST)
+	gl.Clear(gl.DEPTH_BUFFER_BIT)
+
+	m.depth.Use()
+	m.depth.SetMat4("lightSpace", lightSpace)
+	draw(m.depth)
+}
+
+// LightSpace returns the matrix used by the last Render.
+func (m *Map) LightSpace() mgl32.Mat4 {
+	return m.lightSpace
+}
+
+// Texture returns the depth texture of the shadow map.
+func (m *Map) Texture() uint32 {
+	return m.fb.Texture(gl.DEPTH_ATTACHMENT)
+}
+
+// Bind binds the shadow map to the texture unit gl.TEXTURE0 + unit and sets the fields
+// of the Shadow struct uniform called name, declared in shaders/shadows.glsl.
+// s must be in use.
+func (m *Map) Bind(s *shader.Shader, name string, unit uint32) error {
+	gl.ActiveTexture(gl.TEXTURE0 + unit)
+	gl.BindTexture(gl.TEXTURE_2D, m.Texture())
+
+	return errors.Join(
+		s.SetInt(name+".map", int32(unit)),
+		s.SetMat4(name+".lightSpace", m.lightSpace),
+		s.SetFloat(name+".bias", m.Bias),
+		s.SetFloat(name+".slopeBias", m.SlopeBias),
+		s.SetInt(name+".pcf", int32(m.PCF)),
+	)
+}
+
+func (m *Map) Delete() {
+	m.fb.Delete()
+	m.depth.Delete()
+}
+
+// save returns a function that restores the framebuffer and viewport bound now, so
+// a shadow pass does not disturb the pass it is called from.
+func save() func() {
+	var fb int32
+	gl.GetIntegerv(gl.FRAMEBUFFER_BINDING, &fb)
+	var viewport [4]int32
+	gl.GetIntegerv(gl.VIEWPORT, &viewport[0])
+
+	return func() {
+		gl.BindFramebuffer(gl.FRAMEBUFFER, uint32(fb))
+		gl.Viewport(viewport[0], viewport[1], viewport[2], viewport[3])
+	}
+}
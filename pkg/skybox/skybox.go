@@ -0,0 +1,126 @@
+// Package skybox draws a cubemap as the background of a scene.
+package skybox
+
+import (
+	"embed"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/igoramorim/gopengl/pkg/shader"
+	"github.com/igoramorim/gopengl/pkg/texture"
+	"github.com/igoramorim/gopengl/pkg/vertex"
+)
+
+//go:embed skybox.vert skybox.frag
+var shaders embed.FS
+
+const floatSize = 4
+
+// New creates a skybox of the cubemap, which is not owned by the skybox.
+func New(cubemap *texture.Texture) (*Skybox, error) {
+	program, err := shader.NewFS(shaders, "skybox.vert", "skybox.frag")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Skybox{
+		cubemap: cubemap,
+		shader:  program,
+	}
+
+	gl.GenVertexArrays(1, &s.vao)
+	gl.GenBuffers(1, &s.vbo)
+
+	gl.BindVertexArray(s.vao)
+	gl.BindBuffer(gl.ARRAY_BUFFER, s.vbo)
+	gl.BufferData(gl.ARRAY_BUFFER, len(vertices)*floatSize, gl.Ptr(vertices), gl.STATIC_DRAW)
+
+	// Position attribute
+	vertex.Floats(3).Apply()
+
+	gl.BindVertexArray(0)
+
+	return s, nil
+}
+
+type Skybox struct {
+	cubemap *texture.Texture
+	shader  *shader.Shader
+	vao     uint32
+	vbo     uint32
+}
+
+// Draw draws the skybox around the camera. It should be drawn after the opaque
+// geometry, so the fragments hidden by it are not shaded, and before the transparent one.
+func (s *Skybox) Draw(view, projection mgl32.Mat4) {
+	// The skybox is drawn where the depth buffer is still cleared to 1.0
+	var depthFunc int32
+	gl.GetIntegerv(gl.DEPTH_FUNC, &depthFunc)
+	gl.DepthFunc(gl.LEQUAL)
+
+	s.shader.Use()
+	// Removes the translation so the skybox moves along with the camera
+	s.shader.SetMat4("view", view.Mat3().Mat4())
+	s.shader.SetMat4("projection", projection)
+	s.shader.SetInt("skybox", 0)
+
+	gl.BindVertexArray(s.vao)
+	gl.ActiveTexture(gl.TEXTURE0)
+	gl.BindTexture(gl.TEXTURE_CUBE_MAP, s.cubemap.ID())
+	gl.DrawArrays(gl.TRIANGLES, 0, int32(len(vertices)/3))
+	gl.BindVertexArray(0)
+
+	gl.DepthFunc(uint32(depthFunc))
+}
+
+// Delete releases the resources of the skybox, but not its cubemap.
+func (s *Skybox) Delete() {
+	gl.DeleteVertexArrays(1, &s.vao)
+	gl.DeleteBuffers(1, &s.vbo)
+	s.shader.Delete()
+}
+
+// vertices of a cube facing inwards
+var vertices = []float32{
+	-1.0, 1.0, -1.0,
+	-1.0, -1.0, -1.0,
+	1.0, -1.0, -1.0,
+	1.0, -1.0, -1.0,
+	1.0, 1.0, -1.0,
+	-1.0, 1.0, -1.0,
+
+	-1.0, -1.0, 1.0,
+	-1.0, -1.0, -1.0,
+	-1.0, 1.0, -1.0,
+	-1.0, 1.0, -1.0,
+	-1.0, 1.0, 1.0,
+	-1.0, -1.0, 1.0,
+
+	1.0, -1.0, -1.0,
+	1.0, -1.0, 1.0,
+	1.0, 1.0, 1.0,
+	1.0, 1.0, 1.0,
+	1.0, 1.0, -1.0,
+	1.0, -1.0, -1.0,
+
+	-1.0, -1.0, 1.0,
+	-1.0, 1.0, 1.0,
+	1.0, 1.0, 1.0,
+	1.0, 1.0, 1.0,
+	1.0, -1.0, 1.0,
+	-1.0, -1.0, 1.0,
+
+	-1.0, 1.0, -1.0,
+	1.0, 1.0, -1.0,
+	1.0, 1.0, 1.0,
+	1.0, 1.0, 1.0,
+	-1.0, 1.0, 1.0,
+	-1.0, 1.0, -1.0,
+
+	-1.0, -1.0, -1.0,
+	-1.0, -1.0, 1.0,
+	1.0, -1.0, -1.0,
+	1.0, -1.0, -1.0,
+	-1.0, -1.0, 1.0,
+	1.0, -1.0, 1.0,
+}
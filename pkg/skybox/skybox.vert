@@ -0,0 +1,18 @@
+#version 330 core
+
+layout (location = 0) in vec3 position;
+
+uniform mat4 view;
+uniform mat4 projection;
+
+out vec3 TexCoords;
+
+void main() {
+	// The position of the cube is also the direction to sample the cubemap
+	TexCoords = position;
+
+	vec4 pos = projection * view * vec4(position, 1.0);
+	// z = w makes the depth of the skybox always 1.0, the farthest possible,
+	// so it is only drawn where no geometry was
+	gl_Position = pos.xyww;
+}
@@ -0,0 +1,84 @@
+// Package tangent generates the tangent space of meshes for normal mapping, for the
+// loaders of formats that do not store it.
+package tangent
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Generate returns the tangent and bitangent of the vertices, which point where the
+// texture coordinates u and v grow. They are averaged over the triangles sharing the
+// vertex, indices three by three, and made orthogonal to its normal.
+func Generate(positions, normals []mgl32.Vec3, texCoords []mgl32.Vec2, indices []uint32) (tangents, bitangents []mgl32.Vec3) {
+	tangents = make([]mgl32.Vec3, len(positions))
+	bitangents = make([]mgl32.Vec3, len(positions))
+
+	for i := 0; i+2 < len(indices); i += 3 {
+		i0, i1, i2 := indices[i], indices[i+1], indices[i+2]
+
+		edge1 := positions[i1].Sub(positions[i0])
+		edge2 := positions[i2].Sub(positions[i0])
+		duv1 := texCoords[i1].Sub(texCoords[i0])
+		duv2 := texCoords[i2].Sub(texCoords[i0])
+
+		det := duv1.X()*duv2.Y() - duv2.X()*duv1.Y()
+		if det == 0 {
+			// No or degenerate texture coordinates
+			continue
+		}
+		r := 1 / det
+
+		tangent := edge1.Mul(duv2.Y()).Sub(edge2.Mul(duv1.Y())).Mul(r)
+		bitangent := edge2.Mul(duv1.X()).Sub(edge1.Mul(duv2.X())).Mul(r)
+
+		for _, idx := range []uint32{i0, i1, i2} {
+			tangents[idx] = tangents[idx].Add(tangent)
+			bitangents[idx] = bitangents[idx].Add(bitangent)
+		}
+	}
+
+	for i, n := range normals {
+		// Gram-Schmidt: removes the part of the tangent along the normal
+		t := tangents[i].Sub(n.Mul(n.Dot(tangents[i])))
+		if t.Len() < 1e-6 {
+			t = Perpendicular(n)
+		}
+		t = Normalize(t)
+
+		// The bitangent is flipped for mirrored texture coordinates
+		b := n.Cross(t)
+		if b.Dot(bitangents[i]) < 0 {
+			b = b.Mul(-1)
+		}
+
+		tangents[i] = t
+		bitangents[i] = b
+	}
+
+	return tangents, bitangents
+}
+
+// Perpendicular returns a unit vector perpendicular to n.
+func Perpendicular(n mgl32.Vec3) mgl32.Vec3 {
+	axis := mgl32.Vec3{1, 0, 0}
+	if abs(n.X()) > 0.9 {
+		axis = mgl32.Vec3{0, 1, 0}
+	}
+	return Normalize(n.Cross(axis))
+}
+
+// Normalize is Vec3.Normalize without NaNs for the zero vector, e.g. the normal of a
+// degenerate triangle.
+func Normalize(v mgl32.Vec3) mgl32.Vec3 {
+	if v.Len() == 0 {
+		return v
+	}
+	return v.Normalize()
+}
+
+func abs(x float32) float32 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,271 @@
+package texture
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"io/fs"
+	"math"
+	"os"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/igoramorim/gopengl/pkg/imageutil"
+)
+
+// Cubemap faces in the order of their targets, gl.TEXTURE_CUBE_MAP_POSITIVE_X + i.
+const (
+	FaceRight  = iota // +X
+	FaceLeft          // -X
+	FaceTop           // +Y
+	FaceBottom        // -Y
+	FaceFront         // +Z
+	FaceBack          // -Z
+)
+
+// CubemapOptions is a linear filtered cubemap clamped to the edges, so the seams
+// between faces are not visible. The faces are not flipped as, unlike 2D textures,
+// cubemaps expect the first row to be the top of the image.
+var CubemapOptions = Options{
+	MinFilter: gl.LINEAR,
+	MagFilter: gl.LINEAR,
+	WrapS:     gl.CLAMP_TO_EDGE,
+	WrapT:     gl.CLAMP_TO_EDGE,
+	WrapR:     gl.CLAMP_TO_EDGE,
+	Format:    gl.RGB,
+}
+
+// NewCubemap loads the six face images from the working directory.
+// See NewCubemapFS.
+func NewCubemap(facePaths [6]string, slotType uint32, opts Options) (*Texture, error) {
+	return NewCubemapFS(os.DirFS("."), facePaths, slotType, opts)
+}
+
+// NewCubemapFS loads the six face images from fsys, in the order right, left, top,
+// bottom, front and back. The faces must be square and of the same size.
+func NewCubemapFS(fsys fs.FS, facePaths [6]string, slotType uint32, opts Options) (*Texture, error) {
+	var faces [6]*image.RGBA
+	for i, path := range facePaths {
+		face, err := imageutil.Load(fsys, path, opts.FlipY)
+		if err != nil {
+			return nil, err
+		}
+		faces[i] = face
+	}
+
+	return NewCubemapFromImages(faces, slotType, opts)
+}
+
+// NewCubemapFromCrossFS loads a cubemap from a single image with the faces laid out
+// in a horizontal (4x3) or vertical (3x4) cross:
+//
+//	horizontal      vertical
+//	  T               T
+//	L F R B         L F R
+//	  B               B
+//	                  K
+//
+// The back face of the vertical cross is upside-down, as if the cross was folded.
+func NewCubemapFromCrossFS(fsys fs.FS, path string, slotType uint32, opts Options) (*Texture, error) {
+	img, err := imageutil.Load(fsys, path, false)
+	if err != nil {
+		return nil, err
+	}
+
+	faces, err := crossFaces(img)
+	if err != nil {
+		return nil, fmt.Errorf("texture: %s: %w", path, err)
+	}
+
+	return NewCubemapFromImages(faces, slotType, opts)
+}
+
+// NewCubemapFromEquirectFS loads a cubemap from a single equirectangular (2:1) image,
+// the usual format of HDRI panoramas, projecting it on faces of size x size pixels.
+func NewCubemapFromEquirectFS(fsys fs.FS, path string, size int, slotType uint32, opts Options) (*Texture, error) {
+	img, err := imageutil.Load(fsys, path, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewCubemapFromImages(equirectFaces(img, size), slotType, opts)
+}
+
+// NewCubemapFromImages uploads the six faces, in the order right, left, top, bottom,
+// front and back, so opts.FlipY is ignored.
+func NewCubemapFromImages(faces [6]*image.RGBA, slotType uint32, opts Options) (*Texture, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+	internalFormat, channels, _ := opts.formats()
+
+	size := faces[0].Rect.Size()
+	for i, face := range faces {
+		if face.Rect.Size() != size || size.X != size.Y {
+			return nil, fmt.Errorf("texture: cubemap face %d is %v, faces must be square and of the same size", i, face.Rect.Size())
+		}
+	}
+
+	var id uint32
+
+	gl.GenTextures(1, &id)
+	gl.BindTexture(gl.TEXTURE_CUBE_MAP, id)
+
+	opts.apply(gl.TEXTURE_CUBE_MAP)
+
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 1)
+	for i, face := range faces {
+		gl.TexImage2D(
+			gl.TEXTURE_CUBE_MAP_POSITIVE_X+uint32(i),
+			0,
+			internalFormat,
+			int32(size.X),
+			int32(size.Y),
+			0,
+			opts.Format,
+			gl.UNSIGNED_BYTE,
+			gl.Ptr(pixels(face.Pix, channels)),
+		)
+	}
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 4)
+
+	if opts.Mipmaps {
+		gl.GenerateMipmap(gl.TEXTURE_CUBE_MAP)
+	}
+
+	// Unbind
+	gl.BindTexture(gl.TEXTURE_CUBE_MAP, 0)
+
+	return &Texture{
+		id:    id,
+		xtype: gl.TEXTURE_CUBE_MAP,
+		slot:  slotType,
+	}, nil
+}
+
+// crossCells are the cells of each face in the horizontal and vertical crosses.
+var crossCells = map[bool][6]image.Point{
+	true: {
+		FaceRight:  {2, 1},
+		FaceLeft:   {0, 1},
+		FaceTop:    {1, 0},
+		FaceBottom: {1, 2},
+		FaceFront:  {1, 1},
+		FaceBack:   {3, 1},
+	},
+	false: {
+		FaceRight:  {2, 1},
+		FaceLeft:   {0, 1},
+		FaceTop:    {1, 0},
+		FaceBottom: {1, 2},
+		FaceFront:  {1, 1},
+		FaceBack:   {1, 3},
+	},
+}
+
+func crossFaces(img *image.RGBA) ([6]*image.RGBA, error) {
+	var faces [6]*image.RGBA
+
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+	horizontal := w*3 == h*4
+	if !horizontal && w*4 != h*3 {
+		return faces, fmt.Errorf("cross image is %dx%d, it must be 4x3 or 3x4", w, h)
+	}
+
+	size := w / 4
+	if !horizontal {
+		size = w / 3
+	}
+
+	for i, cell := range crossCells[horizontal] {
+		face := image.NewRGBA(image.Rect(0, 0, size, size))
+		for y := 0; y < size; y++ {
+			for x := 0; x < size; x++ {
+				sx, sy := cell.X*size+x, cell.Y*size+y
+				if !horizontal && i == FaceBack {
+					sx, sy = cell.X*size+size-1-x, cell.Y*size+size-1-y
+				}
+				face.SetRGBA(x, y, img.RGBAAt(sx, sy))
+			}
+		}
+		faces[i] = face
+	}
+
+	return faces, nil
+}
+
+// faceDirection returns the direction from the center of the cube through the point
+// (u, v) in [-1, 1] of the face, u going right and v going down the face image.
+func faceDirection(face int, u, v float32) mgl32.Vec3 {
+	switch face {
+	case FaceRight:
+		return mgl32.Vec3{1, -v, -u}
+	case FaceLeft:
+		return mgl32.Vec3{-1, -v, u}
+	case FaceTop:
+		return mgl32.Vec3{u, 1, v}
+	case FaceBottom:
+		return mgl32.Vec3{u, -1, -v}
+	case FaceFront:
+		return mgl32.Vec3{u, -v, 1}
+	default:
+		return mgl32.Vec3{-u, -v, -1}
+	}
+}
+
+func equirectFaces(img *image.RGBA, size int) [6]*image.RGBA {
+	var faces [6]*image.RGBA
+
+	for i := range faces {
+		face := image.NewRGBA(image.Rect(0, 0, size, size))
+		for y := 0; y < size; y++ {
+			for x := 0; x < size; x++ {
+				u := 2*(float32(x)+0.5)/float32(size) - 1
+				v := 2*(float32(y)+0.5)/float32(size) - 1
+				dir := faceDirection(i, u, v).Normalize()
+
+				// Longitude and latitude mapped to [0, 1], the top of the image is the zenith
+				lon := math.Atan2(float64(dir.X()), -float64(dir.Z()))
+				lat := math.Asin(float64(dir.Y()))
+				face.SetRGBA(x, y, sampleBilinear(img, 0.5+lon/(2*math.Pi), 0.5-lat/math.Pi))
+			}
+		}
+		faces[i] = face
+	}
+
+	return faces
+}
+
+// sampleBilinear samples img at (s, t) in [0, 1], wrapping horizontally.
+func sampleBilinear(img *image.RGBA, s, t float64) color.RGBA {
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+
+	x := s*float64(w) - 0.5
+	y := math.Max(0, math.Min(t*float64(h)-0.5, float64(h-1)))
+	x0, y0 := math.Floor(x), math.Floor(y)
+	fx, fy := x-x0, y-y0
+
+	at := func(x, y int) color.RGBA {
+		x = ((x % w) + w) % w
+		y = min(max(y, 0), h-1)
+		return img.RGBAAt(x, y)
+	}
+
+	c00 := at(int(x0), int(y0))
+	c10 := at(int(x0)+1, int(y0))
+	c01 := at(int(x0), int(y0)+1)
+	c11 := at(int(x0)+1, int(y0)+1)
+
+	lerp := func(a, b, c, d uint8) uint8 {
+		top := float64(a)*(1-fx) + float64(b)*fx
+		bottom := float64(c)*(1-fx) + float64(d)*fx
+		return uint8(math.Round(top*(1-fy) + bottom*fy))
+	}
+
+	return color.RGBA{
+		R: lerp(c00.R, c10.R, c01.R, c11.R),
+		G: lerp(c00.G, c10.G, c01.G, c11.G),
+		B: lerp(c00.B, c10.B, c01.B, c11.B),
+		A: lerp(c00.A, c10.A, c01.A, c11.A),
+	}
+}
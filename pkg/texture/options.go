@@ -0,0 +1,136 @@
+package texture
+
+import (
+	"fmt"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// Options controls how an image is uploaded and sampled.
+type Options struct {
+	// MinFilter and MagFilter are the filters used when the texture is drawn smaller or
+	// bigger than its size, e.g. gl.LINEAR_MIPMAP_LINEAR and gl.LINEAR.
+	MinFilter int32
+	MagFilter int32
+
+	// WrapS, WrapT and WrapR are the wrap modes for coordinates outside [0, 1], e.g. gl.REPEAT.
+	// WrapR is only used by cubemaps.
+	WrapS int32
+	WrapT int32
+	WrapR int32
+
+	// BorderColor is the RGBA color sampled outside [0, 1] with gl.CLAMP_TO_BORDER.
+	BorderColor [4]float32
+
+	// Anisotropy is the maximum anisotropic filtering samples. Values up to 1 disable it
+	// and it is clamped to what the driver supports.
+	Anisotropy float32
+
+	// Mipmaps generates the mipmaps after uploading the image. It is needed by the
+	// *_MIPMAP_* min filters.
+	Mipmaps bool
+
+	// Format is the channels kept from the image: gl.RED, gl.RG, gl.RGB or gl.RGBA.
+	Format uint32
+
+	// SRGB stores the color in an sRGB internal format, so it is converted to linear when
+	// sampled. Use it for color textures, not for data like normal or specular maps.
+	// Only valid with the gl.RGB and gl.RGBA formats.
+	SRGB bool
+
+	// FlipY uploads the image upside-down, so its bottom left corner is at the
+	// texture coordinate (0, 0) as OpenGL expects. See imageutil.Load.
+	FlipY bool
+}
+
+// DefaultOptions is a trilinear filtered, repeated RGBA texture.
+var DefaultOptions = Options{
+	MinFilter: gl.LINEAR_MIPMAP_LINEAR,
+	MagFilter: gl.LINEAR,
+	WrapS:     gl.REPEAT,
+	WrapT:     gl.REPEAT,
+	Mipmaps:   true,
+	Format:    gl.RGBA,
+	FlipY:     true,
+}
+
+func (o Options) validate() error {
+	if !o.Mipmaps {
+		switch o.MinFilter {
+		case gl.NEAREST_MIPMAP_NEAREST, gl.LINEAR_MIPMAP_NEAREST, gl.NEAREST_MIPMAP_LINEAR, gl.LINEAR_MIPMAP_LINEAR:
+			return fmt.Errorf("texture: min filter 0x%X needs mipmaps", o.MinFilter)
+		}
+	}
+
+	if _, _, err := o.formats(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// formats returns the internal format the texture is stored in and the number of
+// channels uploaded per pixel.
+func (o Options) formats() (int32, int, error) {
+	switch o.Format {
+	case gl.RED:
+		if !o.SRGB {
+			return gl.R8, 1, nil
+		}
+	case gl.RG:
+		if !o.SRGB {
+			return gl.RG8, 2, nil
+		}
+	case gl.RGB:
+		if o.SRGB {
+			return gl.SRGB8, 3, nil
+		}
+		return gl.RGB8, 3, nil
+	case gl.RGBA:
+		if o.SRGB {
+			return gl.SRGB8_ALPHA8, 4, nil
+		}
+		return gl.RGBA8, 4, nil
+	default:
+		return 0, 0, fmt.Errorf("texture: unsupported format 0x%X", o.Format)
+	}
+
+	return 0, 0, fmt.Errorf("texture: format 0x%X has no sRGB internal format", o.Format)
+}
+
+// apply sets the sampling parameters of the texture bound to target.
+func (o Options) apply(target uint32) {
+	gl.TexParameteri(target, gl.TEXTURE_MIN_FILTER, o.MinFilter)
+	gl.TexParameteri(target, gl.TEXTURE_MAG_FILTER, o.MagFilter)
+
+	gl.TexParameteri(target, gl.TEXTURE_WRAP_S, o.WrapS)
+	gl.TexParameteri(target, gl.TEXTURE_WRAP_T, o.WrapT)
+	if target == gl.TEXTURE_CUBE_MAP {
+		gl.TexParameteri(target, gl.TEXTURE_WRAP_R, o.WrapR)
+	}
+	gl.TexParameterfv(target, gl.TEXTURE_BORDER_COLOR, &o.BorderColor[0])
+
+	if o.Anisotropy > 1 {
+		// Part of OpenGL 4.6, before it comes from the widely supported
+		// EXT_texture_filter_anisotropic. The max is 0 when it is not supported
+		var max float32
+		gl.GetFloatv(gl.MAX_TEXTURE_MAX_ANISOTROPY, &max)
+		if max > 0 {
+			gl.TexParameterf(target, gl.TEXTURE_MAX_ANISOTROPY, min(o.Anisotropy, max))
+		}
+	}
+}
+
+// pixels packs the channels of the RGBA pixels kept by the format.
+func pixels(rgba []uint8, channels int) []uint8 {
+	if channels == 4 {
+		return rgba
+	}
+
+	packed := make([]uint8, 0, len(rgba)/4*channels)
+	for i := 0; i < len(rgba); i += 4 {
+		packed = append(packed, rgba[i:i+channels]...)
+	}
+
+	return packed
+}
@@ -1,96 +1,94 @@
 package texture
 
 import (
-	"fmt"
 	"image"
-	"image/draw"
-	_ "image/jpeg"
-	_ "image/png"
+	"io/fs"
 	"os"
 
 	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/igoramorim/gopengl/pkg/imageutil"
 )
 
-func New(imgPath string, texType int, slotType uint32, sourceFormat, destFormat, pixelType int) (*Texture, error) {
-	var id uint32
-
-	gl.GenTextures(1, &id)
-	gl.BindTexture(uint32(texType), id)
+// New loads the image at imgPath from the working directory.
+func New(imgPath string, slotType uint32, opts Options) (*Texture, error) {
+	return NewFS(os.DirFS("."), imgPath, slotType, opts)
+}
 
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+// NewFS loads the image at imgPath from fsys.
+func NewFS(fsys fs.FS, imgPath string, slotType uint32, opts Options) (*Texture, error) {
+	imageData, err := imageutil.Load(fsys, imgPath, opts.FlipY)
+	if err != nil {
+		return nil, err
+	}
 
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.REPEAT)
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.REPEAT)
+	return NewFromImage(imageData, slotType, opts)
+}
 
-	imageData, err := loadImage(imgPath)
-	if err != nil {
+// NewFromImage uploads the rows of imageData in order, the first one being the bottom
+// of the texture, so opts.FlipY is ignored. See imageutil.Load.
+func NewFromImage(imageData *image.RGBA, slotType uint32, opts Options) (*Texture, error) {
+	if err := opts.validate(); err != nil {
 		return nil, err
 	}
+	internalFormat, channels, _ := opts.formats()
+
+	var id uint32
 
+	gl.GenTextures(1, &id)
+	gl.BindTexture(gl.TEXTURE_2D, id)
+
+	opts.apply(gl.TEXTURE_2D)
+
+	// Rows of 1 and 3 channel images are not always aligned to 4 bytes, the default
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 1)
 	gl.TexImage2D(
 		gl.TEXTURE_2D,
 		0,
-		gl.RGBA,
+		internalFormat,
 		int32(imageData.Rect.Size().X),
 		int32(imageData.Rect.Size().Y),
 		0,
-		gl.RGBA,
+		opts.Format,
 		gl.UNSIGNED_BYTE,
-		gl.Ptr(imageData.Pix),
+		gl.Ptr(pixels(imageData.Pix, channels)),
 	)
-	// gl.GenerateTextureMipmap(texture) // FIXME: Está gerando panic
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 4)
+
+	if opts.Mipmaps {
+		gl.GenerateMipmap(gl.TEXTURE_2D)
+	}
 
 	// Unbind
-	gl.BindTexture(uint32(texType), 0)
+	gl.BindTexture(gl.TEXTURE_2D, 0)
 
 	return &Texture{
 		id:    id,
-		xtype: texType,
+		xtype: gl.TEXTURE_2D,
 		slot:  slotType,
 	}, nil
 }
 
-func loadImage(path string) (*image.RGBA, error) {
-	imgFile, err := os.Open(path)
-	if err != nil {
-		return nil, fmt.Errorf("texture %q not found on disk: %v", path, err)
-	}
-	defer imgFile.Close()
-
-	img, _, err := image.Decode(imgFile)
-	if err != nil {
-		return nil, err
-	}
-
-	rgba := image.NewRGBA(img.Bounds())
-	if rgba.Stride != rgba.Rect.Size().X*4 {
-		return nil, fmt.Errorf("unsupported stride")
-	}
-
-	// FIXME: Images are being loaded upside-down
-	draw.Draw(rgba, rgba.Bounds(), img, image.Point{0, 0}, draw.Src)
-
-	return rgba, nil
-}
-
 type Texture struct {
 	id    uint32
-	xtype int
+	xtype uint32
 	slot  uint32
 }
 
+func (t *Texture) ID() uint32 {
+	return t.id
+}
+
 func (t *Texture) ActiveAndBind() {
 	gl.ActiveTexture(t.slot)
 	t.Bind()
 }
 
 func (t *Texture) Bind() {
-	gl.BindTexture(uint32(t.xtype), t.id)
+	gl.BindTexture(t.xtype, t.id)
 }
 
 func (t *Texture) Unbind() {
-	gl.BindTexture(uint32(t.xtype), 0)
+	gl.BindTexture(t.xtype, 0)
 }
 
 func (t *Texture) Delete() {
@@ -0,0 +1,201 @@
+// Package vertex describes how interleaved vertices are laid out in a buffer, so the
+// attributes of a vertex array are set from the Go type of the vertices instead of by
+// hand.
+//
+// A struct declares the location of its attributes with tags:
+//
+//	type Vertex struct {
+//		Position mgl32.Vec3 `vertex:"0"`
+//		Color    [4]uint8   `vertex:"1,normalized"`
+//		BoneIDs  [4]int32   `vertex:"2"`
+//	}
+//
+// Integer attributes are read by the shaders as integers, e.g. an ivec4, unless they
+// are normalized to floats. Fields without a tag are skipped.
+package vertex
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// floatSize is the size of the components of the vertices laid out by Floats.
+const floatSize = 4
+
+// Attribute is an attribute of the vertices.
+type Attribute struct {
+	// Location is the one declared by the shaders, layout (location = 0).
+	Location uint32
+	// Size is the number of components, 1 to 4.
+	Size int32
+	// Type is the GL type of the components, e.g. gl.FLOAT or gl.INT.
+	Type uint32
+	// Normalized maps the integer components to floats in [0, 1], or [-1, 1] when signed.
+	Normalized bool
+	// Offset is the position of the attribute in the vertex, in bytes.
+	Offset uintptr
+}
+
+// Integer reports whether the shaders read the attribute as integers.
+func (a Attribute) Integer() bool {
+	return a.Type != gl.FLOAT && !a.Normalized
+}
+
+// Layout is the attributes of interleaved vertices.
+type Layout struct {
+	// Stride is the size of a vertex, in bytes.
+	Stride     int32
+	Attributes []Attribute
+}
+
+// componentTypes are the GL types of the kinds of components. int and uint are left out
+// as their size depends on the platform, and float64 as GL 4.1 cannot read doubles in
+// the shaders as floats.
+var componentTypes = map[reflect.Kind]uint32{
+	reflect.Float32: gl.FLOAT,
+	reflect.Int8:    gl.BYTE,
+	reflect.Uint8:   gl.UNSIGNED_BYTE,
+	reflect.Int16:   gl.SHORT,
+	reflect.Uint16:  gl.UNSIGNED_SHORT,
+	reflect.Int32:   gl.INT,
+	reflect.Uint32:  gl.UNSIGNED_INT,
+}
+
+// Of returns the layout of the struct T from the tags of its fields, see the package
+// documentation. The fields must be numbers or arrays of 1 to 4 numbers of a size GL
+// reads the same on every platform, and the locations must be unique.
+func Of[T any]() (Layout, error) {
+	t := reflect.TypeFor[T]()
+	if t.Kind() != reflect.Struct {
+		return Layout{}, fmt.Errorf("vertex: %s is not a struct", t)
+	}
+
+	layout := Layout{Stride: int32(t.Size())}
+	var errs []error
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		tag, ok := field.Tag.Lookup("vertex")
+		if !ok {
+			continue
+		}
+
+		a, err := attribute(field, tag)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("vertex: %s.%s: %w", t.Name(), field.Name, err))
+			continue
+		}
+
+		if slices.ContainsFunc(layout.Attributes, func(b Attribute) bool { return b.Location == a.Location }) {
+			errs = append(errs, fmt.Errorf("vertex: %s.%s: location %d is used twice", t.Name(), field.Name, a.Location))
+			continue
+		}
+
+		layout.Attributes = append(layout.Attributes, a)
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return Layout{}, err
+	}
+
+	return layout, nil
+}
+
+// MustOf is like Of but panics when T is not a valid vertex, for the layouts of types
+// known at compile time.
+func MustOf[T any]() Layout {
+	layout, err := Of[T]()
+	if err != nil {
+		panic(err)
+	}
+	return layout
+}
+
+// attribute returns the attribute of field, whose tag is "location" or
+// "location,normalized".
+func attribute(field reflect.StructField, tag string) (Attribute, error) {
+	location, options, _ := strings.Cut(tag, ",")
+
+	n, err := strconv.ParseUint(location, 10, 32)
+	if err != nil {
+		return Attribute{}, fmt.Errorf("invalid location %q", location)
+	}
+	a := Attribute{Location: uint32(n), Size: 1, Offset: field.Offset}
+
+	switch options {
+	case "":
+	case "normalized":
+		a.Normalized = true
+	default:
+		return Attribute{}, fmt.Errorf("unknown option %q", options)
+	}
+
+	t := field.Type
+	if t.Kind() == reflect.Array {
+		if t.Len() < 1 || t.Len() > 4 {
+			return Attribute{}, fmt.Errorf("%s has %d components, not 1 to 4", t, t.Len())
+		}
+		a.Size = int32(t.Len())
+		t = t.Elem()
+	}
+
+	xtype, ok := componentTypes[t.Kind()]
+	if !ok {
+		return Attribute{}, fmt.Errorf("%s is not a component type: float32, or a sized integer", t)
+	}
+	a.Type = xtype
+
+	if a.Normalized && a.Type == gl.FLOAT {
+		return Attribute{}, fmt.Errorf("only integers can be normalized")
+	}
+
+	return a, nil
+}
+
+// Floats returns the layout of vertices made of float32s, as in a []float32, whose
+// attributes have sizes components each, at the locations 0 onwards. E.g. positions,
+// normals and texture coordinates are Floats(3, 3, 2).
+func Floats(sizes ...int32) Layout {
+	var layout Layout
+	for i, size := range sizes {
+		layout.Attributes = append(layout.Attributes, Attribute{
+			Location: uint32(i),
+			Size:     size,
+			Type:     gl.FLOAT,
+			Offset:   uintptr(layout.Stride),
+		})
+		layout.Stride += size * floatSize
+	}
+	return layout
+}
+
+// Only returns the layout with only the attributes at locations, e.g. to draw just the
+// positions of vertices that also have normals.
+func (l Layout) Only(locations ...uint32) Layout {
+	only := Layout{Stride: l.Stride}
+	for _, a := range l.Attributes {
+		if slices.Contains(locations, a.Location) {
+			only.Attributes = append(only.Attributes, a)
+		}
+	}
+	return only
+}
+
+// Apply sets and enables the attributes of the vertex array bound, to read from the
+// buffer bound to gl.ARRAY_BUFFER.
+func (l Layout) Apply() {
+	for _, a := range l.Attributes {
+		gl.EnableVertexAttribArray(a.Location)
+		if a.Integer() {
+			gl.VertexAttribIPointerWithOffset(a.Location, a.Size, a.Type, l.Stride, a.Offset)
+		} else {
+			gl.VertexAttribPointerWithOffset(a.Location, a.Size, a.Type, a.Normalized, l.Stride, a.Offset)
+		}
+	}
+}
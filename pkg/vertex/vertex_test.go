@@ -0,0 +1,173 @@
+package vertex
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// skinned is a vertex of every kind of attribute, with a field without a tag in between.
+type skinned struct {
+	Position [3]float32 `vertex:"0"`
+	Color    [4]uint8   `vertex:"3,normalized"`
+	Name     string
+	BoneIDs  [4]int32   `vertex:"1"`
+	Weights  [4]float32 `vertex:"2"`
+	Layer    uint16     `vertex:"4"`
+}
+
+func TestOf(t *testing.T) {
+	layout, err := Of[skinned]()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Layout{
+		Stride: int32(reflect.TypeFor[skinned]().Size()),
+		Attributes: []Attribute{
+			{Location: 0, Size: 3, Type: gl.FLOAT, Offset: 0},
+			{Location: 3, Size: 4, Type: gl.UNSIGNED_BYTE, Normalized: true, Offset: 12},
+			// After the string, 8 bytes aligned
+			{Location: 1, Size: 4, Type: gl.INT, Offset: 32},
+			{Location: 2, Size: 4, Type: gl.FLOAT, Offset: 48},
+			{Location: 4, Size: 1, Type: gl.UNSIGNED_SHORT, Offset: 64},
+		},
+	}
+	if !reflect.DeepEqual(layout, want) {
+		t.Errorf("Of() =\n  %+v\nwant\n  %+v", layout, want)
+	}
+}
+
+func TestAttributeInteger(t *testing.T) {
+	layout := MustOf[skinned]()
+
+	// By location, the normalized color is read as floats
+	want := map[uint32]bool{0: false, 1: true, 2: false, 3: false, 4: true}
+	for _, a := range layout.Attributes {
+		if got := a.Integer(); got != want[a.Location] {
+			t.Errorf("location %d: Integer() = %v, want %v", a.Location, got, want[a.Location])
+		}
+	}
+}
+
+func TestOfErrors(t *testing.T) {
+	type duplicate struct {
+		Position [3]float32 `vertex:"0"`
+		Normal   [3]float32 `vertex:"0"`
+	}
+	type sizedByPlatform struct {
+		ID int `vertex:"0"`
+	}
+	type double struct {
+		Position [3]float64 `vertex:"0"`
+	}
+	type tooLong struct {
+		Weights [5]float32 `vertex:"0"`
+	}
+	type normalizedFloat struct {
+		Position [3]float32 `vertex:"0,normalized"`
+	}
+	type badTag struct {
+		Position [3]float32 `vertex:"position"`
+	}
+	type unknownOption struct {
+		Color [4]uint8 `vertex:"0,clamped"`
+	}
+	type severalErrors struct {
+		ID     int        `vertex:"0"`
+		Normal [3]float64 `vertex:"1"`
+		Color  [4]uint8   `vertex:"2"`
+		Extra  [2]float32 `vertex:"2"`
+		Other  any        // Without a tag it is skipped
+	}
+
+	tests := []struct {
+		name string
+		of   func() (Layout, error)
+		// want are parts of the error
+		want []string
+	}{
+		{name: "duplicate", of: Of[duplicate], want: []string{"vertex: duplicate.Normal: location 0 is used twice"}},
+		{name: "int", of: Of[sizedByPlatform], want: []string{"vertex: sizedByPlatform.ID: int is not a component type"}},
+		{name: "float64", of: Of[double], want: []string{"vertex: double.Position: float64 is not a component type"}},
+		{name: "too long", of: Of[tooLong], want: []string{"[5]float32 has 5 components, not 1 to 4"}},
+		{name: "normalized float", of: Of[normalizedFloat], want: []string{"only integers can be normalized"}},
+		{name: "bad tag", of: Of[badTag], want: []string{`invalid location "position"`}},
+		{name: "unknown option", of: Of[unknownOption], want: []string{`unknown option "clamped"`}},
+		{name: "not a struct", of: Of[[3]float32], want: []string{"vertex: [3]float32 is not a struct"}},
+		{name: "every error", of: Of[severalErrors], want: []string{"severalErrors.ID", "severalErrors.Normal", "severalErrors.Extra: location 2 is used twice"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layout, err := tt.of()
+			if err == nil {
+				t.Fatalf("Of() = %+v, want an error", layout)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Of() = %v, want an error with %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestMustOfPanics(t *testing.T) {
+	type double struct {
+		Position [3]float64 `vertex:"0"`
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("MustOf of an invalid vertex did not panic")
+		}
+	}()
+	MustOf[double]()
+}
+
+func TestFloats(t *testing.T) {
+	layout := Floats(3, 3, 2)
+
+	want := Layout{
+		Stride: 32,
+		Attributes: []Attribute{
+			{Location: 0, Size: 3, Type: gl.FLOAT, Offset: 0},
+			{Location: 1, Size: 3, Type: gl.FLOAT, Offset: 12},
+			{Location: 2, Size: 2, Type: gl.FLOAT, Offset: 24},
+		},
+	}
+	if !reflect.DeepEqual(layout, want) {
+		t.Errorf("Floats(3, 3, 2) =\n  %+v\nwant\n  %+v", layout, want)
+	}
+}
+
+func TestOnly(t *testing.T) {
+	layout := Floats(3, 3, 2)
+
+	tests := []struct {
+		name      string
+		locations []uint32
+		want      []Attribute
+	}{
+		{name: "positions", locations: []uint32{0}, want: layout.Attributes[:1]},
+		{name: "in the layout order", locations: []uint32{2, 0}, want: []Attribute{layout.Attributes[0], layout.Attributes[2]}},
+		{name: "unknown location", locations: []uint32{7}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			only := layout.Only(tt.locations...)
+
+			// The vertices are the same, the stride is kept
+			if only.Stride != layout.Stride {
+				t.Errorf("stride = %d, want %d", only.Stride, layout.Stride)
+			}
+			if !reflect.DeepEqual(only.Attributes, tt.want) {
+				t.Errorf("attributes = %+v, want %+v", only.Attributes, tt.want)
+			}
+		})
+	}
+}